@@ -2,4 +2,4 @@ module github.com/bobacgo/vscode-go-plus
 
 go 1.24.0
 
-require golang.org/x/mod v0.24.0
+require golang.org/x/mod v0.25.0
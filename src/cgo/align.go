@@ -0,0 +1,119 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+	"syscall/js"
+)
+
+// AlignStructFields aligns the names, types, tags and trailing comments of
+// the fields of a struct into columns the way gofmt does, so format on save
+// keeps the result. Like gofmt, alignment restarts after blank lines,
+// comment-only lines and fields spanning several lines, and the comment of an
+// untagged field lines up with the tags. This fixes a struct whose columns
+// drifted after tags were generated, without formatting the rest of the file.
+// Args: file content, struct name, 1-based line inside the struct (used when
+// the name is empty).
+// 与 gofmt 相同地将结构体字段的名称、类型、标签与行尾注释对齐为列，因此保存时格式化不会改变结果。
+// 与 gofmt 一样，空行、只有注释的行以及跨越多行的字段之后重新开始对齐，无标签字段的注释与标签对齐。
+// 用于修复生成标签后列不再对齐的结构体，而不格式化文件的其余部分
+// 参数: 文件内容、结构体名称、结构体内从 1 开始的行号（名称为空时使用）
+func AlignStructFields(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("content, struct name and line are required")
+	}
+
+	src := args[0].String()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", src, parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	st := findStruct(fset, file, args[1].String(), args[2].Int())
+	if st == nil {
+		return createErrorJSON("struct not found")
+	}
+
+	edits, err := alignFieldEdits(fset, file, src, st)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(edits)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// alignFieldEdits prints the struct with go/printer, which lays out columns
+// with the same tabwriter sections as gofmt, and returns an edit for every
+// line inside the struct whose text changes. Lines are matched in order
+// ignoring whitespace and blank lines; the printed struct is indented like the
+// line it starts on, and text after the closing brace, such as the tag of a
+// nested struct, is kept.
+// alignFieldEdits 使用 go/printer 打印结构体，其列布局与 gofmt 使用相同的 tabwriter 分段，
+// 并为结构体内文本改变的每一行返回编辑。各行按顺序匹配，忽略空白与空行；打印的结构体按其起始行缩进，
+// 右花括号之后的文本（如嵌套结构体的标签）保持不变
+func alignFieldEdits(fset *token.FileSet, file *ast.File, src string, st *ast.StructType) ([]TagEdit, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, &printer.CommentedNode{Node: st, Comments: file.Comments}); err != nil {
+		return nil, fmt.Errorf("failed to format struct: %s", err.Error())
+	}
+	var printed []string
+	for _, line := range strings.Split(buf.String(), "\n")[1:] {
+		if strings.TrimSpace(line) != "" {
+			printed = append(printed, line)
+		}
+	}
+
+	start, end := fset.Position(st.Pos()), fset.Position(st.End())
+	lines := strings.Split(src, "\n")
+	first := lines[start.Line-1]
+	indent := first[:len(first)-len(strings.TrimLeft(first, " \t"))]
+
+	edits := []TagEdit{}
+	next := 0
+	for n := start.Line + 1; n <= end.Line; n++ {
+		text := strings.TrimSuffix(lines[n-1], "\r")
+		head, tail := text, ""
+		if n == end.Line {
+			head, tail = text[:end.Column-1], text[end.Column-1:]
+		}
+		if strings.TrimSpace(head) == "" {
+			continue
+		}
+		if next == len(printed) || squeezeSpace(head) != squeezeSpace(printed[next]) {
+			return nil, fmt.Errorf("struct layout differs from gofmt at line %d, format the file first", n)
+		}
+		if want := indent + printed[next] + tail; want != text {
+			edits = append(edits, TagEdit{Line: n, Start: 0, End: utf16Len([]byte(text)), Text: want})
+		}
+		next++
+	}
+	return edits, nil
+}
+
+// squeezeSpace removes every space and tab, so lines that differ only in
+// layout compare equal
+// squeezeSpace 删除所有空格与制表符，使只有布局不同的行比较时相等
+func squeezeSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,81 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// applyEdits applies whole-line TagEdits to src
+func applyEdits(src string, edits []TagEdit) string {
+	lines := strings.Split(src, "\n")
+	for _, edit := range edits {
+		lines[edit.Line-1] = edit.Text
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestAlignStructFieldsMatchesGofmt(t *testing.T) {
+	const src = `package config
+
+type Config struct {
+	Name string ` + "`json:\"name\"`" + ` // service name
+	Port int // listen port
+	Debug bool ` + "`json:\"debug\"`" + `
+
+	// Database settings
+	DSN string ` + "`json:\"dsn\"`" + ` // data source name
+	MaxConns int ` + "`json:\"max_conns\"`" + `
+	// pool size
+	PoolSize int // idle connections
+	Timeouts struct {
+		Read int // seconds
+		WriteTimeout int
+	} ` + "`json:\"timeouts\"`" + `
+	TLS bool // after a multi-line field
+	Certificate string
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "config.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := findStruct(fset, file, "Config", 0)
+	if st == nil {
+		t.Fatal("struct not found")
+	}
+
+	edits, err := alignFieldEdits(fset, file, src, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) == 0 {
+		t.Fatal("no edits for a misaligned struct")
+	}
+
+	aligned := applyEdits(src, edits)
+	formatted, err := format.Source([]byte(aligned))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(formatted) != aligned {
+		t.Errorf("gofmt changes the aligned struct:\n%s\ngofmt:\n%s", aligned, formatted)
+	}
+
+	// Aligning again changes nothing
+	// 再次对齐不会有任何改变
+	file, _ = parser.ParseFile(fset, "config.go", aligned, parser.ParseComments)
+	edits, err = alignFieldEdits(fset, file, aligned, findStruct(fset, file, "Config", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 0 {
+		t.Errorf("second run returned %d edits", len(edits))
+	}
+}
@@ -0,0 +1,148 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"syscall/js"
+)
+
+// CheckAssertions finds interface assertions such as
+// var _ Greeter = (*EnglishGreeter)(nil) whose type does not implement the
+// interface, and generates stubs for the missing methods. Assertions whose
+// interface embeds one from outside the workspace, such as io.Closer, cannot
+// be checked and are reported as incomplete. Input is the same JSON array of
+// {path, content} as FindImplementations.
+// CheckAssertions 查找 var _ Greeter = (*EnglishGreeter)(nil) 这类接口断言中未实现接口的类型，
+// 并为缺少的方法生成桩代码。接口嵌入了工作空间之外的接口（例如 io.Closer）时无法检查，报告为不完整。
+// 输入与 FindImplementations 相同，为 {path, content} 的 JSON 数组
+func CheckAssertions(this js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return createErrorJSON("no files provided")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+
+	ws := newWorkspace(files)
+	ws.checkAll()
+
+	result := []MissingMethods{}
+	for _, pkg := range ws.sortedPackages() {
+		for _, f := range pkg.files {
+			result = append(result, checkAssertions(ws, pkg, f)...)
+		}
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+	return string(jsonData)
+}
+
+// checkAssertions checks the package-level blank assertions of a file
+// checkAssertions 检查文件中包级别的空白标识符断言
+func checkAssertions(ws *workspace, pkg *wsPackage, f *ast.File) []MissingMethods {
+	var result []MissingMethods
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if vs.Type == nil || len(vs.Names) != len(vs.Values) {
+				continue
+			}
+			for i, name := range vs.Names {
+				if name.Name != "_" {
+					continue
+				}
+				if item, ok := checkAssertion(ws, pkg, vs.Type, vs.Values[i]); ok {
+					result = append(result, item)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// checkAssertion checks that the type of value implements the interface typeExpr
+// checkAssertion 检查 value 的类型是否实现了 typeExpr 表示的接口
+func checkAssertion(ws *workspace, pkg *wsPackage, typeExpr, value ast.Expr) (MissingMethods, bool) {
+	ifaceType := pkg.info.TypeOf(typeExpr)
+	typ := pkg.info.TypeOf(value)
+	if ifaceType == nil || typ == nil {
+		return MissingMethods{}, false
+	}
+	iface, ok := ifaceType.Underlying().(*types.Interface)
+	if !ok || types.IsInterface(typ) {
+		return MissingMethods{}, false
+	}
+
+	_, pointer := typ.(*types.Pointer)
+	named, ok := deref(typ).(*types.Named)
+	if !ok || !isWorkspaceType(ws, named) {
+		return MissingMethods{}, false
+	}
+
+	// Methods of interfaces embedded from outside the workspace are unknown,
+	// so the assertion is reported as unchecked instead of holding, and no
+	// stubs are generated for a method set that is known to be incomplete
+	// 从工作空间之外嵌入的接口的方法未知，因此将断言报告为未检查而不是成立，
+	// 也不为已知不完整的方法集生成桩代码
+	missing, mismatched := interfaceMethods(typ, iface)
+	embeds := outsideEmbeds(ws, ifaceType)
+	if len(missing) == 0 && len(mismatched) == 0 && len(embeds) == 0 {
+		return MissingMethods{}, false
+	}
+
+	start, end := ws.fset.Position(value.Pos()), ws.fset.Position(value.End())
+	item := MissingMethods{
+		Path:       start.Filename,
+		Start:      Position{Line: start.Line, Column: start.Column},
+		End:        Position{Line: end.Line, Column: end.Column},
+		Interface:  types.TypeString(ifaceType, packageQualifier(pkg.types)),
+		Type:       types.TypeString(typ, packageQualifier(pkg.types)),
+		Missing:    methodNames(missing),
+		Mismatched: methodNames(mismatched),
+		Stubs:      Stubs{Imports: []string{}},
+	}
+	if len(embeds) > 0 {
+		item.Incomplete = incompleteError(embeds).Error()
+	} else {
+		item.Stubs = generateStubs(ws, named, pointer, ifaceType, missing, zeroBody)
+	}
+	return item, true
+}
+
+// deref returns the element type of a pointer type
+// deref 返回指针类型的元素类型
+func deref(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+// MissingMethods describes an interface assertion that does not hold
+// MissingMethods 描述不成立的接口断言
+type MissingMethods struct {
+	Path       string   `json:"path"`
+	Start      Position `json:"start"` // start of the asserted value
+	End        Position `json:"end"`
+	Interface  string   `json:"interface"`
+	Type       string   `json:"type"`
+	Missing    []string `json:"missing"`    // methods the type does not declare
+	Mismatched []string `json:"mismatched"` // methods declared with a different signature or receiver
+	Stubs      Stubs    `json:"stubs"`      // stubs for the missing methods, empty when Incomplete is set
+	Incomplete string   `json:"incomplete"` // why the method set is unknown, e.g. an embedded interface outside the workspace
+}
@@ -0,0 +1,83 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+// closerFiles is a workspace whose interface embeds io.Closer, which is
+// outside the workspace and so type-checks as an empty placeholder
+var closerFiles = []SourceFile{
+	{Path: "/w/go.mod", Content: "module example.com/greet\n\ngo 1.21\n"},
+	{Path: "/w/greet.go", Content: `package greet
+
+import "io"
+
+type Greeter interface {
+	io.Closer
+	Greet(name string) string
+}
+
+type EnglishGreeter struct{}
+
+func (g *EnglishGreeter) Greet(name string) string { return "Hello " + name }
+
+var _ Greeter = (*EnglishGreeter)(nil)
+`},
+}
+
+const closerIncomplete = "incomplete: embeds io.Closer outside the workspace"
+
+func TestCheckAssertionsEmbedsOutsideWorkspace(t *testing.T) {
+	ws := newWorkspace(closerFiles)
+	pkg, f := ws.file("/w/greet.go")
+	ws.checkAll()
+
+	items := checkAssertions(ws, pkg, f)
+	if len(items) != 1 {
+		t.Fatalf("got %d assertions, want 1", len(items))
+	}
+	if items[0].Incomplete != closerIncomplete {
+		t.Errorf("incomplete = %q, want %q", items[0].Incomplete, closerIncomplete)
+	}
+	if items[0].Stubs.Text != "" {
+		t.Errorf("stubs generated for an incomplete method set:\n%s", items[0].Stubs.Text)
+	}
+}
+
+func TestGenerateStubsEmbedsOutsideWorkspace(t *testing.T) {
+	files, _ := json.Marshal(closerFiles)
+	result := GenerateStubs(js.Null(), []js.Value{
+		js.ValueOf(string(files)), js.ValueOf("/w/greet.go"), js.ValueOf("EnglishGreeter"), js.ValueOf("Greeter"),
+	})
+
+	var data struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(result.(string)), &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Error != closerIncomplete {
+		t.Errorf("error = %q, want %q", data.Error, closerIncomplete)
+	}
+}
+
+func TestGenerateMockEmbedsOutsideWorkspace(t *testing.T) {
+	ws := newWorkspace(closerFiles)
+	pkg, _ := ws.file("/w/greet.go")
+	ws.checkAll()
+	iface, ok := lookupInterface(ws, pkg, "Greeter")
+	if !ok {
+		t.Fatal("interface not found")
+	}
+
+	_, err := generateMock(ws, iface, pkg, "/w", "MockGreeter")
+	if err == nil || !strings.Contains(err.Error(), closerIncomplete) {
+		t.Errorf("err = %v, want %q", err, closerIncomplete)
+	}
+}
@@ -0,0 +1,147 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"sort"
+	"syscall/js"
+)
+
+// CompareInterfaces reports whether a value of one interface can be used
+// where another is expected, in both directions: A is assignable to B when
+// the method set of A, embedded interfaces included, has every method of B
+// with an identical signature. Interfaces assignable both ways are
+// equivalent and can be merged.
+// Args: workspace files (JSON array of {path, content}), the file the
+// interfaces are looked up from, interface A, interface B, each either
+// "Greeter" or qualified as "example.com/greet.Greeter".
+// 双向报告一个接口的值能否用在需要另一个接口的位置：A 的方法集（包括嵌入的接口）包含 B 的每个方法
+// 且签名相同时，A 可赋值给 B。双向可赋值的接口是等价的，可以合并
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）、查找接口的起始文件、接口 A、接口 B，
+// 均可以是 "Greeter" 或限定形式 "example.com/greet.Greeter"
+func CompareInterfaces(this js.Value, args []js.Value) any {
+	if len(args) < 4 {
+		return createErrorJSON("files, file and two interfaces are required")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	filePath := args[1].String()
+
+	ws := newWorkspace(files)
+	pkg, _ := ws.file(filePath)
+	if pkg == nil {
+		return createErrorJSON("file not found: " + filePath)
+	}
+	ws.checkAll()
+
+	var named [2]*types.Named
+	for i, name := range []string{args[2].String(), args[3].String()} {
+		var ok bool
+		if named[i], ok = lookupInterface(ws, pkg, name); !ok {
+			return createErrorJSON("interface not found: " + name)
+		}
+	}
+
+	assignability, err := compareInterfaces(ws, named[0], named[1], packageQualifier(pkg.types))
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(assignability)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// InterfaceAssignability is how two interfaces can be used for each other
+// InterfaceAssignability 表示两个接口能否相互替代使用
+type InterfaceAssignability struct {
+	A          string      `json:"a"`
+	B          string      `json:"b"`
+	AToB       bool        `json:"aToB"`       // a value of A can be used where B is expected
+	BToA       bool        `json:"bToA"`       // a value of B can be used where A is expected
+	Equivalent bool        `json:"equivalent"` // both, the method sets are the same
+	MissingInA []MethodGap `json:"missingInA"` // methods of B that keep A from being assignable to B
+	MissingInB []MethodGap `json:"missingInB"` // methods of A that keep B from being assignable to A
+	Unresolved []string    `json:"unresolved"` // embedded interfaces from packages outside the workspace, whose methods are unknown
+}
+
+// MethodGap is a method one interface expects and the other lacks or
+// declares with another signature
+// MethodGap 表示一个接口需要、而另一个接口缺少或以不同签名声明的方法
+type MethodGap struct {
+	Name string `json:"name"`
+	Want string `json:"want"` // e.g. Greet(name string) string
+	Have string `json:"have"` // signature of the method with the same name, empty when there is none
+	Path string `json:"path"` // file declaring the wanted method
+	Line int    `json:"line"`
+}
+
+// compareInterfaces relies on types.Implements with the interface types
+// themselves, which compares the complete method sets and so covers methods
+// promoted from embedded interfaces. Unexported methods only match methods of
+// the same package, as in assignments.
+// compareInterfaces 直接以接口类型调用 types.Implements，比较完整的方法集，因此包括从嵌入接口提升的方法。
+// 未导出的方法只匹配同一包中的方法，与赋值相同
+func compareInterfaces(ws *workspace, a, b *types.Named, qf types.Qualifier) (InterfaceAssignability, error) {
+	result := InterfaceAssignability{
+		A:          types.TypeString(a, qf),
+		B:          types.TypeString(b, qf),
+		Unresolved: []string{},
+	}
+
+	seen := make(map[string]bool)
+	for _, named := range []*types.Named{a, b} {
+		methodSet, err := interfaceMethodSet(ws, named)
+		if err != nil {
+			return InterfaceAssignability{}, err
+		}
+		for _, name := range methodSet.Unresolved {
+			if !seen[name] {
+				seen[name] = true
+				result.Unresolved = append(result.Unresolved, name)
+			}
+		}
+	}
+	sort.Strings(result.Unresolved)
+
+	aIface, bIface := a.Underlying().(*types.Interface), b.Underlying().(*types.Interface)
+	result.AToB = types.Implements(a, bIface)
+	result.BToA = types.Implements(b, aIface)
+	result.Equivalent = result.AToB && result.BToA
+	result.MissingInA = methodGaps(ws, a, bIface, qf)
+	result.MissingInB = methodGaps(ws, b, aIface, qf)
+	return result, nil
+}
+
+// methodGaps returns the methods of want that the interface have lacks or
+// declares with another signature, in the order of the method set of want
+// methodGaps 返回 want 中被 have 缺少或以不同签名声明的方法，按 want 方法集的顺序
+func methodGaps(ws *workspace, have *types.Named, want *types.Interface, qf types.Qualifier) []MethodGap {
+	gaps := []MethodGap{}
+	for i := 0; i < want.NumMethods(); i++ {
+		m := want.Method(i)
+		obj, _, _ := types.LookupFieldOrMethod(have, false, m.Pkg(), m.Name())
+		fn, ok := obj.(*types.Func)
+		if ok && types.Identical(fn.Type(), m.Type()) {
+			continue
+		}
+
+		gap := MethodGap{Name: m.Name(), Want: methodSignature(m, qf)}
+		if ok {
+			gap.Have = methodSignature(fn, qf)
+		}
+		gap.Path, gap.Line = ws.position(m.Pos())
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
@@ -0,0 +1,64 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+)
+
+// ParseModBatch parses several go.mod files in one call.
+// The argument is a JSON array of {path, content}; the result is a JSON array
+// in the same order where each entry holds either a result or an error.
+// 一次调用解析多个 go.mod 文件
+// 参数为 {path, content} 的 JSON 数组；结果为同序的 JSON 数组，每项包含解析结果或错误
+func ParseModBatch(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to unmarshal input: %s", err.Error()))
+	}
+
+	// A malformed file only fails its own entry
+	// 格式错误的文件只影响其自身的条目
+	results := make([]BatchResult, len(files))
+	for i, file := range files {
+		results[i].Path = file.Path
+
+		modInfo, err := parseModInfo(file.Content)
+		if err != nil {
+			results[i].Error = err.Error()
+			results[i].Errors = parseErrors(err)
+			continue
+		}
+		results[i].Result = modInfo
+	}
+
+	result, err := json.Marshal(results)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// SourceFile is a file passed in from the extension
+// SourceFile 表示扩展传入的文件
+type SourceFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// BatchResult is the parse result of one file in a batch
+// BatchResult 表示批量解析中单个文件的结果
+type BatchResult struct {
+	Path   string       `json:"path"`
+	Result *ModFile     `json:"result,omitempty"`
+	Error  string       `json:"error,omitempty"`
+	Errors []ParseError `json:"errors,omitempty"` // positions of the syntax errors, see createParseErrorJSON
+}
@@ -0,0 +1,169 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"strings"
+	"syscall/js"
+)
+
+// knownOS and knownArch are the GOOS and GOARCH values go/build recognises
+// in file names, including ports that no longer exist
+// knownOS 与 knownArch 是 go/build 在文件名中识别的 GOOS 与 GOARCH 值，包括已不存在的平台
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+		"illumos": true, "ios": true, "js": true, "linux": true, "nacl": true, "netbsd": true,
+		"openbsd": true, "plan9": true, "solaris": true, "wasip1": true, "windows": true, "zos": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+		"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true, "mips64le": true,
+		"mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true, "ppc64le": true,
+		"riscv": true, "riscv64": true, "s390": true, "s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// PackageBuildSet reports which files of a package are compiled for a
+// GOOS/GOARCH pair.
+// Args: files (JSON array of {path, content}, filename is accepted for path), GOOS, GOARCH, JSON array of
+// extra build tags (optional), whether _test.go files are built (optional).
+// 报告包中哪些文件会针对指定的 GOOS/GOARCH 编译
+// 参数: 文件（{path, content} 的 JSON 数组，path 也可写作 filename）、GOOS、GOARCH、额外构建标签的 JSON 数组（可选）、
+// 是否编译 _test.go 文件（可选）
+func PackageBuildSet(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("files, GOOS and GOARCH are required")
+	}
+
+	var input []struct {
+		SourceFile
+		Filename string `json:"filename"`
+	}
+	if err := json.Unmarshal([]byte(args[0].String()), &input); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	files := make([]SourceFile, 0, len(input))
+	for _, file := range input {
+		if file.Path == "" {
+			file.Path = file.Filename
+		}
+		files = append(files, file.SourceFile)
+	}
+	var tags []string
+	if len(args) > 3 && args[3].Truthy() {
+		if err := json.Unmarshal([]byte(args[3].String()), &tags); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse build tags: %s", err.Error()))
+		}
+	}
+	tests := len(args) > 4 && args[4].Truthy()
+
+	result, err := json.Marshal(packageBuildSet(files, args[1].String(), args[2].String(), tags, tests))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// BuildSet is the result of PackageBuildSet
+// BuildSet 是 PackageBuildSet 的结果
+type BuildSet struct {
+	GOOS     string      `json:"goos"`
+	GOARCH   string      `json:"goarch"`
+	Included []string    `json:"included"` // paths of the compiled files, in input order
+	Files    []BuildFile `json:"files"`
+}
+
+// BuildFile tells whether a file is compiled and why not
+// BuildFile 说明文件是否会被编译以及不编译的原因
+type BuildFile struct {
+	Path     string `json:"path"`
+	Included bool   `json:"included"`
+	Expr     string `json:"expr"`   // build constraint of the file, "" without one
+	Reason   string `json:"reason"` // why the file is excluded, "" when included
+}
+
+// packageBuildSet combines the file name and the build constraint of every
+// file with AND, as go/build does: foo_linux.go with //go:build amd64 is only
+// built for linux/amd64. Only .go files are considered.
+// packageBuildSet 与 go/build 一样将每个文件的文件名规则与构建约束以与关系组合：
+// 带有 //go:build amd64 的 foo_linux.go 只在 linux/amd64 下编译。只考虑 .go 文件
+func packageBuildSet(files []SourceFile, goos, goarch string, tags []string, tests bool) BuildSet {
+	set := BuildSet{GOOS: goos, GOARCH: goarch, Included: []string{}, Files: []BuildFile{}}
+	match := platformTags(goos, goarch, tags)
+	for _, file := range files {
+		name := path.Base(slashPath(file.Path))
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+
+		entry := BuildFile{Path: file.Path}
+		fset := token.NewFileSet()
+		if f, err := parser.ParseFile(fset, name, file.Content, parser.PackageClauseOnly|parser.ParseComments); f != nil {
+			constraints := parseBuildConstraints(fset, f, goos, goarch, tags)
+			entry.Expr = constraints.Expr
+			if !constraints.Matches {
+				entry.Reason = fmt.Sprintf("build constraint %s is not satisfied", constraints.Expr)
+			}
+		} else {
+			entry.Reason = fmt.Sprintf("failed to parse file: %s", err.Error())
+		}
+
+		// The file name is checked first since it is the cheaper reason to read
+		// 先检查文件名，它是更容易理解的原因
+		if reason := fileNameExcluded(name, match, tests); reason != "" {
+			entry.Reason = reason
+		}
+
+		entry.Included = entry.Reason == ""
+		if entry.Included {
+			set.Included = append(set.Included, file.Path)
+		}
+		set.Files = append(set.Files, entry)
+	}
+	return set
+}
+
+// fileNameExcluded applies the file name rules of go/build: names starting
+// with _ or . are ignored, _test.go files are only built for tests, and a
+// *_GOOS, *_GOARCH or *_GOOS_GOARCH suffix restricts the platform, with
+// the same implications as tags, so _linux.go files are built for android.
+// fileNameExcluded 应用 go/build 的文件名规则：以 _ 或 . 开头的文件被忽略，_test.go 文件只在测试时编译，
+// *_GOOS、*_GOARCH 或 *_GOOS_GOARCH 后缀限制平台，隐含关系与标签相同，因此 _linux.go 文件也会为 android 编译
+func fileNameExcluded(name string, match func(string) bool, tests bool) string {
+	if strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".") {
+		return "file names starting with _ or . are ignored"
+	}
+
+	// go/build ignores everything after the first dot: x_linux.pb.go is linux only
+	// go/build 忽略第一个点之后的内容：x_linux.pb.go 只在 linux 下编译
+	stem, _, _ := strings.Cut(name, ".")
+	if trimmed, ok := strings.CutSuffix(stem, "_test"); ok && strings.HasSuffix(name, "_test.go") {
+		if !tests {
+			return "_test.go files are only built by go test"
+		}
+		stem = trimmed
+	}
+
+	// The first element is the name itself, never a platform: linux.go is built everywhere
+	// 第一段是名称本身而非平台：linux.go 在所有平台上编译
+	parts := strings.Split(stem, "_")[1:]
+	n := len(parts)
+	if n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]] {
+		if !match(parts[n-2]) || !match(parts[n-1]) {
+			return fmt.Sprintf("file name suffix _%s_%s does not match", parts[n-2], parts[n-1])
+		}
+		return ""
+	}
+	if n >= 1 && (knownOS[parts[n-1]] || knownArch[parts[n-1]]) && !match(parts[n-1]) {
+		return fmt.Sprintf("file name suffix _%s does not match", parts[n-1])
+	}
+	return ""
+}
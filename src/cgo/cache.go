@@ -0,0 +1,56 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// resultCache is a bounded cache of marshaled results keyed by a hash of the
+// call arguments. The WASM instance lives as long as the extension, so the
+// cache lasts across calls; the oldest entry is evicted when it is full.
+// resultCache 是以调用参数哈希为键、容量有限的序列化结果缓存。WASM 实例与扩展的生命周期相同，
+// 因此缓存在多次调用之间保留；缓存满时淘汰最早的条目
+type resultCache struct {
+	size    int
+	order   []string // keys from oldest to newest
+	results map[string]string
+}
+
+// newResultCache creates a cache holding at most size results
+// newResultCache 创建最多保存 size 个结果的缓存
+func newResultCache(size int) *resultCache {
+	return &resultCache{size: size, results: make(map[string]string, size)}
+}
+
+// cacheKey hashes the arguments of a call. Arguments are separated by a NUL
+// byte, which go.mod content and JSON arguments cannot contain unescaped.
+// cacheKey 计算调用参数的哈希。参数之间以 NUL 字节分隔，go.mod 内容与 JSON 参数中不会出现未转义的 NUL
+func cacheKey(args ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns the cached result for a key
+// get 返回键对应的缓存结果
+func (c *resultCache) get(key string) (string, bool) {
+	result, ok := c.results[key]
+	return result, ok
+}
+
+// put stores a result, evicting the oldest one when the cache is full
+// put 保存结果，缓存满时淘汰最早的结果
+func (c *resultCache) put(key, result string) {
+	if _, ok := c.results[key]; ok {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.results, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.order = append(c.order, key)
+	c.results[key] = result
+}
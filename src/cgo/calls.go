@@ -0,0 +1,141 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"syscall/js"
+)
+
+// FindInterfaceCalls finds where a method of an interface is used through an
+// interface-typed value, such as g.SayHello() with g a Greeter; uses through
+// a concrete type such as *EnglishGreeter are left out.
+// Args: workspace files (JSON array of {path, content}), the file the
+// interface is looked up from, the interface, either "Greeter" or qualified
+// as "example.com/greet.Greeter", the method name.
+// 查找通过接口类型的值使用接口方法的位置，例如 g 为 Greeter 时的 g.SayHello()；
+// 通过 *EnglishGreeter 等具体类型的使用不包括在内
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）、查找接口的起始文件、接口，
+// 可以是 "Greeter" 或限定形式 "example.com/greet.Greeter"、方法名
+func FindInterfaceCalls(this js.Value, args []js.Value) any {
+	if len(args) < 4 {
+		return createErrorJSON("files, file, interface and method are required")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	filePath, ifaceName, methodName := args[1].String(), args[2].String(), args[3].String()
+
+	ws := newWorkspace(files)
+	pkg, _ := ws.file(filePath)
+	if pkg == nil {
+		return createErrorJSON("file not found: " + filePath)
+	}
+	ws.checkAll()
+
+	named, ok := lookupInterface(ws, pkg, ifaceName)
+	if !ok {
+		return createErrorJSON("interface not found: " + ifaceName)
+	}
+	obj, _, _ := types.LookupFieldOrMethod(named, false, named.Obj().Pkg(), methodName)
+	method, ok := obj.(*types.Func)
+	if !ok {
+		return createErrorJSON(fmt.Sprintf("interface %s has no method %s", ifaceName, methodName))
+	}
+
+	result, err := json.Marshal(findInterfaceCalls(ws, method))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// InterfaceCall is a use of an interface method through an interface value
+// InterfaceCall 表示通过接口值对接口方法的使用
+type InterfaceCall struct {
+	Path     string   `json:"path"`
+	Start    Position `json:"start"`    // start of the method name in the selector
+	End      Position `json:"end"`      // end of the method name
+	Receiver string   `json:"receiver"` // static type of the receiver, e.g. Greeter, Loud or T
+	Function string   `json:"function"` // enclosing function, e.g. Greet or (*Server).Run, "" at package level
+	Call     bool     `json:"call"`     // the method is called rather than taken as a value, e.g. f := g.SayHello
+}
+
+// findInterfaceCalls reports the selections of method whose receiver is an
+// interface: the interface itself, an interface embedding it or a type
+// parameter constrained by one. Embedded interface methods are the same
+// types.Func, so comparing objects is enough; a concrete receiver selects the
+// method of the concrete type, which is a different object. Method
+// expressions such as Greeter.SayHello count as uses that are not calls.
+// findInterfaceCalls 报告接收者为接口的 method 选择：接口本身、嵌入它的接口或以其为约束的类型参数。
+// 嵌入接口的方法是同一个 types.Func，因此比较对象即可；具体类型的接收者选择的是具体类型的方法，是不同的对象。
+// Greeter.SayHello 这样的方法表达式计为不是调用的使用
+func findInterfaceCalls(ws *workspace, method *types.Func) []InterfaceCall {
+	calls := []InterfaceCall{}
+	for _, pkg := range ws.sortedPackages() {
+		if pkg.info == nil {
+			continue
+		}
+		qf := packageQualifier(pkg.types)
+		for _, f := range pkg.files {
+			for _, decl := range f.Decls {
+				enclosing := ""
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					if fn, ok := pkg.info.Defs[fd.Name].(*types.Func); ok {
+						enclosing = funcName(fn, qf)
+					}
+				}
+				calls = append(calls, declInterfaceCalls(ws, pkg.info, qf, decl, method, enclosing)...)
+			}
+		}
+	}
+	sort.SliceStable(calls, func(i, j int) bool {
+		if calls[i].Path != calls[j].Path {
+			return calls[i].Path < calls[j].Path
+		}
+		if calls[i].Start.Line != calls[j].Start.Line {
+			return calls[i].Start.Line < calls[j].Start.Line
+		}
+		return calls[i].Start.Column < calls[j].Start.Column
+	})
+	return calls
+}
+
+// declInterfaceCalls finds the interface selections of method in a declaration
+// declInterfaceCalls 在声明中查找 method 的接口选择
+func declInterfaceCalls(ws *workspace, info *types.Info, qf types.Qualifier, decl ast.Decl, method *types.Func, enclosing string) []InterfaceCall {
+	var calls []InterfaceCall
+	called := make(map[*ast.SelectorExpr]bool)
+	ast.Inspect(decl, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if sel, ok := ast.Unparen(n.Fun).(*ast.SelectorExpr); ok {
+				called[sel] = true
+			}
+		case *ast.SelectorExpr:
+			sel, ok := info.Selections[n]
+			if !ok || sel.Obj() != method || !types.IsInterface(sel.Recv()) {
+				return true
+			}
+			start, end := ws.fset.Position(n.Sel.Pos()), ws.fset.Position(n.Sel.End())
+			calls = append(calls, InterfaceCall{
+				Path:     start.Filename,
+				Start:    Position{Line: start.Line, Column: start.Column},
+				End:      Position{Line: end.Line, Column: end.Column},
+				Receiver: types.TypeString(sel.Recv(), qf),
+				Function: enclosing,
+				Call:     sel.Kind() == types.MethodVal && called[n],
+			})
+		}
+		return true
+	})
+	return calls
+}
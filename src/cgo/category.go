@@ -0,0 +1,81 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"golang.org/x/mod/module"
+)
+
+// Categories of a module path reported in Mod.Category
+// Mod.Category 中报告的模块路径类别
+const (
+	CategoryExtendedStdlib = "extended-stdlib" // golang.org/x, maintained alongside the standard library
+	CategoryInternal       = "internal"        // the module itself or modules under its path
+	CategoryThirdParty     = "third-party"
+)
+
+// CategoryRule assigns a category to the module paths matching Prefix. The
+// prefix is matched by path elements and may contain globs, as GOPRIVATE does.
+// CategoryRule 为匹配 Prefix 的模块路径指定类别。前缀按路径元素匹配且可以包含通配符，与 GOPRIVATE 相同
+type CategoryRule struct {
+	Prefix   string `json:"prefix"`
+	Category string `json:"category"`
+}
+
+// defaultCategoryRules are checked after the module's own path
+// defaultCategoryRules 在模块自身路径之后检查
+var defaultCategoryRules = []CategoryRule{
+	{Prefix: "golang.org/x", Category: CategoryExtendedStdlib},
+}
+
+// parseCategoryRules reads the optional JSON array of category rules
+// following the go.mod content
+// parseCategoryRules 读取 go.mod 内容之后可选的类别规则 JSON 数组
+func parseCategoryRules(args []js.Value) ([]CategoryRule, error) {
+	var rules []CategoryRule
+	if len(args) < 2 || !args[1].Truthy() {
+		return nil, nil
+	}
+	if err := json.Unmarshal([]byte(args[1].String()), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse category rules: %s", err.Error())
+	}
+	return rules, nil
+}
+
+// categorizeMods sets the category of every require, exclude and tool.
+// Rules passed by the extension come first so they can override both the
+// module's own path and the defaults; the first matching rule wins.
+// categorizeMods 设置每个 require、exclude 与 tool 的类别。扩展传入的规则最先检查，
+// 因此可以覆盖模块自身路径与默认规则；使用第一条匹配的规则
+func categorizeMods(modInfo *ModFile, rules []CategoryRule) {
+	all := append([]CategoryRule{}, rules...)
+	if modInfo.Module != "" {
+		all = append(all, CategoryRule{Prefix: modInfo.Module, Category: CategoryInternal})
+	}
+	all = append(all, defaultCategoryRules...)
+
+	for _, mods := range [][]Mod{modInfo.Require, modInfo.Exclude} {
+		for i := range mods {
+			mods[i].Category = modCategory(mods[i].Path, all)
+		}
+	}
+	for i := range modInfo.Tool {
+		modInfo.Tool[i].Category = modCategory(modInfo.Tool[i].Path, all)
+	}
+}
+
+// modCategory returns the category of the first rule matching a module path
+// modCategory 返回第一条匹配模块路径的规则的类别
+func modCategory(path string, rules []CategoryRule) string {
+	for _, rule := range rules {
+		if rule.Prefix != "" && rule.Category != "" && module.MatchPrefixPatterns(rule.Prefix, path) {
+			return rule.Category
+		}
+	}
+	return CategoryThirdParty
+}
@@ -0,0 +1,158 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"syscall/js"
+	"unicode/utf8"
+)
+
+// Kinds of comments reported by ClassifyComments
+// ClassifyComments 报告的注释类型
+const (
+	CommentKindDoc      = "doc"      // doc comment of the package or a declaration, spec or field
+	CommentKindTrailing = "trailing" // end-of-line comment of a spec or field, e.g. a struct field
+	CommentKindInline   = "inline"   // comment inside a function body
+	CommentKindOther    = "other"    // free-floating comment outside functions, e.g. a license header
+)
+
+// ClassifyComments reports the kind of every comment of a Go file.
+// Args: file content.
+// 报告 Go 文件中每条注释的类型
+// 参数: 文件内容
+func ClassifyComments(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	src := args[0].String()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", src, parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	result, err := json.Marshal(classifyComments(fset, file, src))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// CommentInfo is a // or /* */ comment and its kind
+// CommentInfo 表示一条 // 或 /* */ 注释及其类型
+type CommentInfo struct {
+	Kind    string `json:"kind"`
+	Line    int    `json:"line"`    // 1-based line where the comment starts
+	EndLine int    `json:"endLine"` // 1-based line where the comment ends
+	// Anchor is right after the end of the comment, where an inlay hint
+	// can follow it without covering code placed after a /* */ comment
+	// Anchor 位于注释结束之后，内嵌提示放在此处不会覆盖 /* */ 注释之后的代码
+	Anchor Position `json:"anchor"`
+}
+
+// classifyComments attaches each comment group to its nearest node with
+// ast.CommentMap and classifies it from that node. A group inside a function
+// body is inline even when it ends a line, so `x++ // note` is inline while
+// the comment after a struct field is trailing; struct fields declared in a
+// function keep their doc and trailing comments.
+// classifyComments 使用 ast.CommentMap 将每个注释组关联到最近的节点，并根据该节点分类。
+// 函数体内的注释组即使位于行尾也视为 inline，因此 `x++ // note` 是 inline，
+// 而结构体字段后的注释是 trailing；函数内声明的结构体字段仍保留其文档注释与行尾注释
+func classifyComments(fset *token.FileSet, file *ast.File, src string) []CommentInfo {
+	var bodies []*ast.BlockStmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				bodies = append(bodies, n.Body)
+			}
+		case *ast.FuncLit:
+			bodies = append(bodies, n.Body)
+		}
+		return true
+	})
+	inBody := func(group *ast.CommentGroup) bool {
+		for _, body := range bodies {
+			if body.Lbrace < group.Pos() && group.End() <= body.Rbrace {
+				return true
+			}
+		}
+		return false
+	}
+
+	kinds := make(map[*ast.CommentGroup]string)
+	for node, groups := range ast.NewCommentMap(fset, file, file.Comments) {
+		for _, group := range groups {
+			doc, trailing := attachedComments(node)
+			_, isField := node.(*ast.Field)
+			switch {
+			case group == doc:
+				kinds[group] = CommentKindDoc
+			case group == trailing && (isField || !inBody(group)):
+				kinds[group] = CommentKindTrailing
+			case inBody(group):
+				kinds[group] = CommentKindInline
+			default:
+				kinds[group] = CommentKindOther
+			}
+		}
+	}
+
+	comments := []CommentInfo{}
+	for _, group := range file.Comments {
+		kind, ok := kinds[group]
+		if !ok {
+			kind = CommentKindOther
+		}
+		for _, comment := range group.List {
+			end := fset.Position(comment.End())
+			comments = append(comments, CommentInfo{
+				Kind:    kind,
+				Line:    fset.Position(comment.Pos()).Line,
+				EndLine: end.Line,
+				Anchor:  runePosition(fset, src, comment.End()),
+			})
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].Line < comments[j].Line })
+	return comments
+}
+
+// runePosition converts a position to a line and a 1-based rune column
+// runePosition 将位置转换为行号与从 1 开始的字符列号
+func runePosition(fset *token.FileSet, src string, pos token.Pos) Position {
+	position := fset.Position(pos)
+	lineStart := fset.Position(fset.File(pos).LineStart(position.Line)).Offset
+	return Position{Line: position.Line, Column: utf8.RuneCountInString(src[lineStart:position.Offset]) + 1}
+}
+
+// attachedComments returns the doc and trailing comment fields of a node
+// attachedComments 返回节点的文档注释与行尾注释字段
+func attachedComments(node ast.Node) (doc, trailing *ast.CommentGroup) {
+	switch n := node.(type) {
+	case *ast.File:
+		return n.Doc, nil
+	case *ast.FuncDecl:
+		return n.Doc, nil
+	case *ast.GenDecl:
+		return n.Doc, nil
+	case *ast.TypeSpec:
+		return n.Doc, n.Comment
+	case *ast.ValueSpec:
+		return n.Doc, n.Comment
+	case *ast.ImportSpec:
+		return n.Doc, n.Comment
+	case *ast.Field:
+		return n.Doc, n.Comment
+	}
+	return nil, nil
+}
@@ -0,0 +1,387 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+	"syscall/js"
+)
+
+// Warning kinds reported in BuildConstraints.Warnings
+// BuildConstraints.Warnings 中报告的警告类型
+const (
+	WarningInvalidConstraint  = "invalid-constraint"
+	WarningConstraintMismatch = "constraint-mismatch"
+)
+
+// platforms is the output of go tool dist list
+// platforms 为 go tool dist list 的输出
+var platforms = []string{
+	"aix/ppc64",
+	"android/386", "android/amd64", "android/arm", "android/arm64",
+	"darwin/amd64", "darwin/arm64",
+	"dragonfly/amd64",
+	"freebsd/386", "freebsd/amd64", "freebsd/arm", "freebsd/arm64",
+	"illumos/amd64",
+	"ios/amd64", "ios/arm64",
+	"js/wasm",
+	"linux/386", "linux/amd64", "linux/arm", "linux/arm64", "linux/loong64",
+	"linux/mips", "linux/mips64", "linux/mips64le", "linux/mipsle",
+	"linux/ppc64", "linux/ppc64le", "linux/riscv64", "linux/s390x",
+	"netbsd/386", "netbsd/amd64", "netbsd/arm", "netbsd/arm64",
+	"openbsd/386", "openbsd/amd64", "openbsd/arm", "openbsd/arm64", "openbsd/ppc64", "openbsd/riscv64",
+	"plan9/386", "plan9/amd64", "plan9/arm",
+	"solaris/amd64",
+	"wasip1/wasm",
+	"windows/386", "windows/amd64", "windows/arm64",
+}
+
+// unixOS is the set of GOOS values matched by the unix tag
+// unixOS 为 unix 标签匹配的 GOOS 集合
+var unixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+	"illumos": true, "ios": true, "linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+}
+
+// ParseBuildConstraints parses the //go:build and // +build lines of a Go
+// file and evaluates them for every GOOS/GOARCH pair.
+// Args: file content, GOOS, GOARCH, JSON array of extra build tags (optional).
+// 解析 Go 文件的 //go:build 与 // +build 行，并针对每个 GOOS/GOARCH 组合求值
+// 参数: 文件内容、GOOS、GOARCH、额外构建标签的 JSON 数组（可选）
+func ParseBuildConstraints(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("file content, GOOS and GOARCH are required")
+	}
+
+	var tags []string
+	if len(args) > 3 && args[3].Truthy() {
+		if err := json.Unmarshal([]byte(args[3].String()), &tags); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse build tags: %s", err.Error()))
+		}
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", args[0].String(), parser.PackageClauseOnly|parser.ParseComments)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	result, err := json.Marshal(parseBuildConstraints(fset, file, args[1].String(), args[2].String(), tags))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// BuildConstraints is the result of ParseBuildConstraints. Expr is empty
+// and every platform matches when the file has no constraint.
+// BuildConstraints 是 ParseBuildConstraints 的结果。文件没有构建约束时 Expr 为空且所有平台均匹配
+type BuildConstraints struct {
+	Expr           string    `json:"expr"`           // normalized expression, //go:build preferred
+	GoBuildLine    int       `json:"goBuildLine"`    // 1-based line of //go:build, 0 if absent
+	PlusBuildLines []int     `json:"plusBuildLines"` // 1-based lines of // +build
+	Platforms      []string  `json:"platforms"`      // GOOS/GOARCH pairs satisfying Expr
+	Matches        bool      `json:"matches"`        // satisfied by the given GOOS/GOARCH and tags
+	Warnings       []Warning `json:"warnings"`
+}
+
+// parseBuildConstraints only looks at the comments before the package
+// clause that are not its doc comment, as the go command does. With both
+// forms present //go:build wins, and the // +build lines are compared with
+// it by truth table rather than by text so that reordered terms still agree.
+// parseBuildConstraints 与 go 命令一样，只查看 package 子句之前、且不属于包文档注释的注释。
+// 两种形式同时存在时以 //go:build 为准，// +build 行通过真值表而非文本与之比较，
+// 因此仅调整顺序的写法仍视为一致
+func parseBuildConstraints(fset *token.FileSet, file *ast.File, goos, goarch string, tags []string) BuildConstraints {
+	result := BuildConstraints{PlusBuildLines: []int{}, Platforms: []string{}, Warnings: []Warning{}}
+
+	var goBuild, plusBuild constraint.Expr
+	for _, group := range file.Comments {
+		if group.End() >= file.Package {
+			break
+		}
+		if group == file.Doc {
+			continue
+		}
+		for _, comment := range group.List {
+			isGoBuild, isPlusBuild := constraint.IsGoBuild(comment.Text), constraint.IsPlusBuild(comment.Text)
+			if !isGoBuild && !isPlusBuild {
+				continue
+			}
+
+			line := fset.Position(comment.Pos()).Line
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				result.Warnings = append(result.Warnings, Warning{
+					Kind:    WarningInvalidConstraint,
+					Message: fmt.Sprintf("invalid build constraint %q: %s", comment.Text, err.Error()),
+					Line:    line,
+				})
+				continue
+			}
+
+			switch {
+			case isGoBuild && goBuild != nil:
+				result.Warnings = append(result.Warnings, Warning{
+					Kind:    WarningInvalidConstraint,
+					Message: "multiple //go:build lines",
+					Line:    line,
+				})
+			case isGoBuild:
+				goBuild = expr
+				result.GoBuildLine = line
+			case plusBuild == nil:
+				plusBuild = expr
+				result.PlusBuildLines = append(result.PlusBuildLines, line)
+			default:
+				// Several // +build lines are ANDed together
+				// 多个 // +build 行之间为与关系
+				plusBuild = &constraint.AndExpr{X: plusBuild, Y: expr}
+				result.PlusBuildLines = append(result.PlusBuildLines, line)
+			}
+		}
+	}
+
+	expr := goBuild
+	if expr == nil {
+		expr = plusBuild
+	}
+	if goBuild != nil && plusBuild != nil && !equivalentConstraints(goBuild, plusBuild) {
+		result.Warnings = append(result.Warnings, Warning{
+			Kind:    WarningConstraintMismatch,
+			Message: fmt.Sprintf("// +build lines (%s) do not match //go:build %s", plusBuild.String(), goBuild.String()),
+			Line:    result.PlusBuildLines[0],
+		})
+	}
+
+	if expr == nil {
+		result.Platforms = append(result.Platforms, platforms...)
+		result.Matches = true
+		return result
+	}
+
+	result.Expr = expr.String()
+	for _, platform := range platforms {
+		platformOS, platformArch, _ := strings.Cut(platform, "/")
+		if expr.Eval(platformTags(platformOS, platformArch, tags)) {
+			result.Platforms = append(result.Platforms, platform)
+		}
+	}
+	result.Matches = expr.Eval(platformTags(goos, goarch, tags))
+	return result
+}
+
+// platformTags reports whether a tag is satisfied when building for
+// goos/goarch with the extra tags, following go/build: android implies
+// linux, illumos implies solaris, ios implies darwin, and release tags up to
+// the Go version that built this module are set. cgo is only set when listed in tags.
+// platformTags 返回在 goos/goarch 与额外标签下判断标签是否满足的函数，规则与 go/build 一致：
+// android 隐含 linux，illumos 隐含 solaris，ios 隐含 darwin，并设置不超过构建本模块的 Go 版本的发布标签。
+// cgo 只有出现在 tags 中时才设置
+func platformTags(goos, goarch string, tags []string) func(string) bool {
+	return func(tag string) bool {
+		switch {
+		case tag == goos || tag == goarch || tag == "gc":
+			return true
+		case tag == "unix":
+			return unixOS[goos]
+		case tag == "linux":
+			return goos == "android"
+		case tag == "solaris":
+			return goos == "illumos"
+		case tag == "darwin":
+			return goos == "ios"
+		}
+		for _, release := range build.Default.ReleaseTags {
+			if tag == release {
+				return true
+			}
+		}
+		for _, t := range tags {
+			if tag == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// equivalentConstraints compares two expressions over every assignment of
+// their tags. Expressions with too many tags to enumerate are compared by
+// their normalized text.
+// equivalentConstraints 在标签的所有取值组合下比较两个表达式，
+// 标签过多无法枚举时比较规范化后的文本
+func equivalentConstraints(x, y constraint.Expr) bool {
+	seen := make(map[string]bool)
+	collect := func(tag string) bool {
+		seen[tag] = true
+		return false
+	}
+	x.Eval(collect)
+	y.Eval(collect)
+
+	names := make([]string, 0, len(seen))
+	for tag := range seen {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+	if len(names) > 12 {
+		return x.String() == y.String()
+	}
+
+	for mask := 0; mask < 1<<len(names); mask++ {
+		set := func(tag string) bool {
+			i := sort.SearchStrings(names, tag)
+			return mask&(1<<i) != 0
+		}
+		if x.Eval(set) != y.Eval(set) {
+			return false
+		}
+	}
+	return true
+}
+
+// Forms kept by ConvertBuildConstraints
+// ConvertBuildConstraints 保留的约束形式
+const (
+	KeepGoBuild   = "go:build"
+	KeepPlusBuild = "+build"
+)
+
+// ConvertBuildConstraints rewrites the build constraints of a Go file to a
+// single form, //go:build or // +build.
+// Args: file content, form to keep ("go:build" or "+build").
+// 将 Go 文件的构建约束改写为单一形式：//go:build 或 // +build
+// 参数: 文件内容、保留的形式（"go:build" 或 "+build"）
+func ConvertBuildConstraints(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("file content and form to keep are required")
+	}
+	keep := args[1].String()
+	if keep != KeepGoBuild && keep != KeepPlusBuild {
+		return createErrorJSON(fmt.Sprintf("unknown form %q, want %q or %q", keep, KeepGoBuild, KeepPlusBuild))
+	}
+
+	content := args[0].String()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", content, parser.PackageClauseOnly|parser.ParseComments)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	result, err := json.Marshal(convertBuildConstraints(content, parseBuildConstraints(fset, file, "", "", nil), keep))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ConstraintConversion is the result of ConvertBuildConstraints. Content is
+// the input unchanged when Warnings is not empty.
+// ConstraintConversion 是 ConvertBuildConstraints 的结果。Warnings 不为空时 Content 为原始输入
+type ConstraintConversion struct {
+	Content  string    `json:"content"`
+	Changed  bool      `json:"changed"`
+	Warnings []Warning `json:"warnings"`
+}
+
+// convertBuildConstraints leaves files with invalid or mismatched
+// constraints alone, since picking one of two disagreeing lines would
+// silently change which platforms build the file. The kept form replaces the
+// first line of the dropped one when it is missing, so the blank line that
+// must follow the constraint block and the package clause stay where they were.
+// convertBuildConstraints 不修改约束无效或不一致的文件，因为在两条不一致的行中任选其一会静默改变
+// 构建该文件的平台。保留的形式不存在时替换被删除形式的第一行，因此约束块之后必需的空行与
+// package 子句保持原位
+func convertBuildConstraints(content string, constraints BuildConstraints, keep string) ConstraintConversion {
+	conversion := ConstraintConversion{Content: content, Warnings: []Warning{}}
+	if len(constraints.Warnings) > 0 {
+		conversion.Warnings = constraints.Warnings
+		return conversion
+	}
+	if constraints.Expr == "" {
+		return conversion
+	}
+	expr, err := constraint.Parse("//go:build " + constraints.Expr)
+	if err != nil {
+		return conversion
+	}
+
+	var keptLine int
+	var dropped []int
+	var replacement []string
+	if keep == KeepGoBuild {
+		keptLine, dropped = constraints.GoBuildLine, constraints.PlusBuildLines
+		replacement = []string{"//go:build " + expr.String()}
+	} else {
+		if len(constraints.PlusBuildLines) > 0 {
+			keptLine = constraints.PlusBuildLines[0]
+		}
+		if constraints.GoBuildLine > 0 {
+			dropped = []int{constraints.GoBuildLine}
+		}
+		if replacement, err = constraint.PlusBuildLines(expr); err != nil {
+			conversion.Warnings = append(conversion.Warnings, Warning{
+				Kind:    WarningInvalidConstraint,
+				Message: fmt.Sprintf("//go:build %s cannot be written as // +build lines: %s", expr.String(), err.Error()),
+				Line:    constraints.GoBuildLine,
+			})
+			return conversion
+		}
+	}
+	if len(dropped) == 0 {
+		return conversion
+	}
+
+	lines := strings.Split(content, "\n")
+	drop := make(map[int]bool)
+	for _, line := range dropped {
+		drop[line-1] = true
+	}
+	first := slices.Min(dropped) - 1
+
+	var out []string
+	for i, line := range lines {
+		switch {
+		case i == first && keptLine == 0:
+			// Keep the line ending of the replaced line, CRLF included
+			// 保留被替换行的换行符，包括 CRLF
+			suffix := ""
+			if strings.HasSuffix(line, "\r") {
+				suffix = "\r"
+			}
+			for _, text := range replacement {
+				out = append(out, text+suffix)
+			}
+		case drop[i]:
+		case isBlankLine(line) && (len(out) == 0 || isBlankLine(out[len(out)-1])) && i > 0 && drop[i-1]:
+			// Removing the lines would leave the blank line after the
+			// constraint block doubled, or the file starting with one
+			// 删除这些行会使约束块后的空行重复，或使文件以空行开头
+		default:
+			out = append(out, line)
+		}
+	}
+
+	conversion.Content = strings.Join(out, "\n")
+	conversion.Changed = conversion.Content != content
+	return conversion
+}
+
+// isBlankLine reports whether a line only holds white space
+// isBlankLine 判断一行是否只包含空白字符
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
@@ -0,0 +1,125 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// DependencyTree resolves the dependency tree of a module from pre-fetched
+// go.mod files, since WASM cannot reach the network.
+// Args: JSON object mapping module@version to go.mod content, root module@version.
+// 根据预先获取的 go.mod 文件解析模块的依赖树（WASM 无法访问网络）
+// 参数: module@version 到 go.mod 内容的 JSON 对象、根模块 module@version
+func DependencyTree(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("go.mod contents and root module are required")
+	}
+
+	var mods map[string]string
+	if err := json.Unmarshal([]byte(args[0].String()), &mods); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse go.mod contents: %s", err.Error()))
+	}
+
+	root, ok := parseModuleVersion(args[1].String())
+	if !ok {
+		return createErrorJSON(fmt.Sprintf("invalid root module: %s", args[1].String()))
+	}
+
+	result, err := json.Marshal(dependencyTree(mods, root))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// DependencyNode is a module in the dependency tree. A module is expanded
+// only at its first occurrence: later occurrences are marked Duplicate, and
+// occurrences among their own ancestors are marked Cycle.
+// DependencyNode 表示依赖树中的模块。模块只在第一次出现时展开：之后的出现标记为 Duplicate，
+// 出现在自身祖先中时标记为 Cycle
+type DependencyNode struct {
+	Path      string            `json:"path"`
+	Version   string            `json:"version"`
+	Indirect  bool              `json:"indirect"`  // required with "// indirect" by its parent
+	Missing   bool              `json:"missing"`   // go.mod was not supplied
+	Error     string            `json:"error"`     // go.mod could not be parsed
+	Cycle     bool              `json:"cycle"`     // already an ancestor, not expanded
+	Duplicate bool              `json:"duplicate"` // expanded elsewhere in the tree
+	Children  []*DependencyNode `json:"children"`
+}
+
+// dependencyTree builds the tree breadth-first with an explicit queue, so
+// deep graphs cannot overflow the stack and every go.mod is parsed once
+// dependencyTree 使用显式队列广度优先构建依赖树，深层依赖图不会导致栈溢出，且每个 go.mod 只解析一次
+func dependencyTree(mods map[string]string, root module.Version) *DependencyNode {
+	type item struct {
+		node      *DependencyNode
+		ancestors map[module.Version]bool
+	}
+
+	rootNode := &DependencyNode{Path: root.Path, Version: root.Version, Children: []*DependencyNode{}}
+	expanded := map[module.Version]bool{root: true}
+	queue := []item{{node: rootNode, ancestors: map[module.Version]bool{root: true}}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		node := current.node
+
+		content, ok := mods[node.Path+"@"+node.Version]
+		if !ok {
+			node.Missing = true
+			continue
+		}
+		modFile, err := modfile.ParseLax("go.mod", []byte(content), nil)
+		if err != nil {
+			node.Error = err.Error()
+			continue
+		}
+
+		for _, req := range modFile.Require {
+			child := &DependencyNode{
+				Path:     req.Mod.Path,
+				Version:  req.Mod.Version,
+				Indirect: req.Indirect,
+				Children: []*DependencyNode{},
+			}
+			node.Children = append(node.Children, child)
+
+			switch {
+			case current.ancestors[req.Mod]:
+				child.Cycle = true
+			case expanded[req.Mod]:
+				child.Duplicate = true
+			default:
+				expanded[req.Mod] = true
+				ancestors := make(map[module.Version]bool, len(current.ancestors)+1)
+				for v := range current.ancestors {
+					ancestors[v] = true
+				}
+				ancestors[req.Mod] = true
+				queue = append(queue, item{node: child, ancestors: ancestors})
+			}
+		}
+	}
+	return rootNode
+}
+
+// parseModuleVersion splits module@version
+// parseModuleVersion 拆分 module@version
+func parseModuleVersion(s string) (module.Version, bool) {
+	path, version, ok := strings.Cut(s, "@")
+	if !ok || path == "" || version == "" {
+		return module.Version{}, false
+	}
+	return module.Version{Path: path, Version: version}, true
+}
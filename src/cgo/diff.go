@@ -0,0 +1,148 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DiffMod compares two go.mod files and reports which requirements were
+// added, removed or changed, plus changes to the go and toolchain directives.
+// Args: old go.mod content, new go.mod content.
+// 比较两个 go.mod 文件，报告新增、删除和变更的依赖，以及 go 和 toolchain 指令的变化
+// 参数: 旧的 go.mod 内容、新的 go.mod 内容
+func DiffMod(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("old and new go.mod contents are required")
+	}
+
+	oldFile, err := parseModContent(args[0].String())
+	if err != nil {
+		return createErrorJSON("old: " + err.Error())
+	}
+	newFile, err := parseModContent(args[1].String())
+	if err != nil {
+		return createErrorJSON("new: " + err.Error())
+	}
+
+	result, err := json.Marshal(diffMod(oldFile, newFile))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ModDiff is the result of DiffMod. Go and Toolchain are nil when the
+// directive did not change.
+// ModDiff 是 DiffMod 的结果，指令未变化时 Go 和 Toolchain 为 nil
+type ModDiff struct {
+	Added     []RequireChange  `json:"added"`
+	Removed   []RequireChange  `json:"removed"`
+	Changed   []RequireChange  `json:"changed"`
+	Go        *DirectiveChange `json:"go,omitempty"`
+	Toolchain *DirectiveChange `json:"toolchain,omitempty"`
+}
+
+// RequireChange is a require entry that differs between two go.mod files
+// RequireChange 表示两个 go.mod 文件之间不同的 require 条目
+type RequireChange struct {
+	Path            string `json:"path"`
+	OldVersion      string `json:"oldVersion"`      // empty for added entries
+	NewVersion      string `json:"newVersion"`      // empty for removed entries
+	Indirect        bool   `json:"indirect"`        // indirect on every side it appears, safe to filter out
+	IndirectChanged bool   `json:"indirectChanged"` // the "// indirect" marker was added or removed
+}
+
+// DirectiveChange is the old and new value of a directive, empty when absent
+// DirectiveChange 表示指令的旧值和新值，不存在时为空
+type DirectiveChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// diffMod compares the requirements and directives of two parsed go.mod
+// files. Entries are sorted by module path; for duplicated requirements the
+// first entry of each path is compared.
+// diffMod 比较两个已解析 go.mod 文件的依赖和指令，条目按模块路径排序；
+// 重复的依赖只比较每个路径的第一个条目
+func diffMod(oldFile, newFile *modfile.File) ModDiff {
+	diff := ModDiff{
+		Added:   []RequireChange{},
+		Removed: []RequireChange{},
+		Changed: []RequireChange{},
+	}
+
+	oldReqs, newReqs := requireByPath(oldFile), requireByPath(newFile)
+	for path, oldReq := range oldReqs {
+		newReq, ok := newReqs[path]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, RequireChange{
+				Path:       path,
+				OldVersion: oldReq.Mod.Version,
+				Indirect:   oldReq.Indirect,
+			})
+		case oldReq.Mod.Version != newReq.Mod.Version || oldReq.Indirect != newReq.Indirect:
+			diff.Changed = append(diff.Changed, RequireChange{
+				Path:            path,
+				OldVersion:      oldReq.Mod.Version,
+				NewVersion:      newReq.Mod.Version,
+				Indirect:        oldReq.Indirect && newReq.Indirect,
+				IndirectChanged: oldReq.Indirect != newReq.Indirect,
+			})
+		}
+	}
+	for path, newReq := range newReqs {
+		if _, ok := oldReqs[path]; !ok {
+			diff.Added = append(diff.Added, RequireChange{
+				Path:       path,
+				NewVersion: newReq.Mod.Version,
+				Indirect:   newReq.Indirect,
+			})
+		}
+	}
+	for _, changes := range [][]RequireChange{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	}
+
+	var oldGo, newGo, oldToolchain, newToolchain string
+	if oldFile.Go != nil {
+		oldGo = oldFile.Go.Version
+	}
+	if newFile.Go != nil {
+		newGo = newFile.Go.Version
+	}
+	if oldFile.Toolchain != nil {
+		oldToolchain = oldFile.Toolchain.Name
+	}
+	if newFile.Toolchain != nil {
+		newToolchain = newFile.Toolchain.Name
+	}
+	if oldGo != newGo {
+		diff.Go = &DirectiveChange{Old: oldGo, New: newGo}
+	}
+	if oldToolchain != newToolchain {
+		diff.Toolchain = &DirectiveChange{Old: oldToolchain, New: newToolchain}
+	}
+	return diff
+}
+
+// requireByPath indexes the require entries of a go.mod file by module
+// path, keeping the first entry of duplicated paths
+// requireByPath 按模块路径索引 go.mod 的 require 条目，重复路径保留第一个条目
+func requireByPath(modFile *modfile.File) map[string]*modfile.Require {
+	reqs := make(map[string]*modfile.Require, len(modFile.Require))
+	for _, req := range modFile.Require {
+		if _, ok := reqs[req.Mod.Path]; !ok {
+			reqs[req.Mod.Path] = req
+		}
+	}
+	return reqs
+}
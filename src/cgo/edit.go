@@ -0,0 +1,555 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// FormatMod formats go.mod content and returns the canonical text
+// 格式化 go.mod 内容并返回规范化后的文本
+func FormatMod(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+
+	return formatModFile(modFile)
+}
+
+// AddRequire adds or updates a require directive and returns the formatted go.mod.
+// Args: go.mod content, module path, version.
+// 添加或更新 require 指令并返回格式化后的 go.mod
+// 参数: go.mod 内容、模块路径、版本
+func AddRequire(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 3 || args[1].String() == "" || args[2].String() == "" {
+		return createErrorJSON("module path and version are required")
+	}
+
+	// AddRequire updates an existing entry in place, so adding the same
+	// path twice only changes its version
+	// AddRequire 会原地更新已存在的条目，重复添加同一路径只会更新版本
+	if err := modFile.AddRequire(args[1].String(), args[2].String()); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to add require: %s", err.Error()))
+	}
+
+	return formatModFile(modFile)
+}
+
+// DropRequire removes every require directive for a module path and returns
+// the formatted go.mod. The content is returned unchanged when the path is absent.
+// Args: go.mod content, module path.
+// 删除模块路径的所有 require 指令并返回格式化后的 go.mod，路径不存在时原样返回内容
+// 参数: go.mod 内容、模块路径
+func DropRequire(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 2 || args[1].String() == "" {
+		return createErrorJSON("module path is required")
+	}
+	path := args[1].String()
+
+	// Return the content unchanged if the module is not required
+	// 如果模块不在依赖中，原样返回内容
+	found := false
+	for _, req := range modFile.Require {
+		if req.Mod.Path == path {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return args[0].String()
+	}
+
+	// DropRequire removes all occurrences, including ones in the indirect block
+	// DropRequire 会删除所有出现的条目，包括间接依赖块中的条目
+	if err := modFile.DropRequire(path); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to drop require: %s", err.Error()))
+	}
+
+	return formatModFile(modFile)
+}
+
+// AddExclude adds an exclude directive for a module version and returns the
+// formatted go.mod with warnings. Adding an exclude that is already present
+// leaves the content unchanged.
+// Args: go.mod content, module path, version.
+// 为模块版本添加 exclude 指令，返回格式化后的 go.mod 与警告。exclude 已存在时内容保持不变
+// 参数: go.mod 内容、模块路径、版本
+func AddExclude(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 3 || args[1].String() == "" || args[2].String() == "" {
+		return createErrorJSON("module path and version are required")
+	}
+	path, version := args[1].String(), args[2].String()
+
+	// AddExclude returns early when the exclude exists
+	// exclude 已存在时 AddExclude 会直接返回
+	if err := modFile.AddExclude(path, version); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to add exclude: %s", err.Error()))
+	}
+
+	edit := ExcludeEdit{Content: formatModFile(modFile), Warnings: checkExcludedRequire(modFile, path, version)}
+	return marshalExcludeEdit(edit)
+}
+
+// DropExclude removes the exclude directive of a module version and returns
+// the formatted go.mod. The content is returned unchanged when the exclude is absent.
+// Args: go.mod content, module path, version.
+// 删除模块版本的 exclude 指令并返回格式化后的 go.mod，exclude 不存在时原样返回内容
+// 参数: go.mod 内容、模块路径、版本
+func DropExclude(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 3 || args[1].String() == "" || args[2].String() == "" {
+		return createErrorJSON("module path and version are required")
+	}
+	path, version := args[1].String(), args[2].String()
+
+	// Return the content unchanged if the version is not excluded
+	// 如果版本未被排除，原样返回内容
+	found := false
+	for _, exc := range modFile.Exclude {
+		if exc.Mod.Path == path && exc.Mod.Version == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return marshalExcludeEdit(ExcludeEdit{Content: args[0].String(), Warnings: []Warning{}})
+	}
+
+	if err := modFile.DropExclude(path, version); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to drop exclude: %s", err.Error()))
+	}
+
+	return marshalExcludeEdit(ExcludeEdit{Content: formatModFile(modFile), Warnings: []Warning{}})
+}
+
+// ExcludeEdit is the result of AddExclude and DropExclude
+// ExcludeEdit 是 AddExclude 与 DropExclude 的结果
+type ExcludeEdit struct {
+	Content  string    `json:"content"`  // formatted go.mod
+	Warnings []Warning `json:"warnings"` // excluded versions that are required
+}
+
+// marshalExcludeEdit marshals the result of an exclude edit
+// marshalExcludeEdit 序列化 exclude 编辑的结果
+func marshalExcludeEdit(edit ExcludeEdit) string {
+	result, err := json.Marshal(edit)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+	return string(result)
+}
+
+// checkExcludedRequire warns when the excluded version is the one go.mod
+// requires: the go command then moves to the next higher version that is not
+// excluded, whose API may differ and break the build.
+// checkExcludedRequire 在被排除的版本正是 go.mod 所需版本时发出警告：go 命令随后会改用下一个未被排除的更高版本，
+// 其 API 可能不同从而导致构建失败
+func checkExcludedRequire(modFile *modfile.File, path, version string) []Warning {
+	warnings := []Warning{}
+	for _, req := range modFile.Require {
+		if req.Mod.Path != path || req.Mod.Version != version {
+			continue
+		}
+		start, _ := linePosition(req.Syntax)
+		warnings = append(warnings, Warning{
+			Kind: WarningExcludedRequire,
+			Message: fmt.Sprintf("%s %s is required at line %d, the go command will use the next higher version instead, which may break the build",
+				path, version, start.Line),
+			Line: start.Line,
+		})
+	}
+	return warnings
+}
+
+// PromoteIndirect drops the // indirect mark of requires, making them direct,
+// and returns the formatted go.mod.
+// Args: go.mod content, JSON array of module paths (optional, every indirect require by default).
+// 去掉 require 的 // indirect 标记使其成为直接依赖，并返回格式化后的 go.mod
+// 参数: go.mod 内容、模块路径的 JSON 数组（可选，默认为所有间接依赖）
+func PromoteIndirect(this js.Value, args []js.Value) any {
+	return setIndirectArgs(args, false)
+}
+
+// DemoteDirect marks requires as // indirect, the inverse of PromoteIndirect,
+// and returns the formatted go.mod.
+// Args: go.mod content, JSON array of module paths (optional, every direct require by default).
+// 为 require 添加 // indirect 标记，与 PromoteIndirect 相反，并返回格式化后的 go.mod
+// 参数: go.mod 内容、模块路径的 JSON 数组（可选，默认为所有直接依赖）
+func DemoteDirect(this js.Value, args []js.Value) any {
+	return setIndirectArgs(args, true)
+}
+
+// setIndirectArgs parses the arguments of PromoteIndirect and DemoteDirect
+// setIndirectArgs 解析 PromoteIndirect 与 DemoteDirect 的参数
+func setIndirectArgs(args []js.Value, indirect bool) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+
+	var paths []string
+	if len(args) > 1 && args[1].Truthy() {
+		if err := json.Unmarshal([]byte(args[1].String()), &paths); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse module paths: %s", err.Error()))
+		}
+	}
+	if err := setIndirect(modFile, paths, indirect); err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	return formatModFile(modFile)
+}
+
+// setIndirect sets the indirect mark of the given requires, all of them
+// without paths. The mark is changed on each line in place, the way modfile
+// does, so lines keep their order and block, and line comments other than
+// "indirect" are kept. A path required more than once is changed on every
+// line.
+// setIndirect 设置给定 require 的 indirect 标记，未给出路径时设置全部。与 modfile 相同地在每一行上原地修改标记，
+// 因此各行保持原来的顺序和所在的块，"indirect" 以外的行注释也会保留。重复 require 的路径在每一行上都会修改
+func setIndirect(modFile *modfile.File, paths []string, indirect bool) error {
+	required := map[string]bool{}
+	for _, req := range modFile.Require {
+		required[req.Mod.Path] = true
+	}
+	for _, p := range paths {
+		if !required[p] {
+			return fmt.Errorf("%s is not required", p)
+		}
+	}
+
+	for _, req := range modFile.Require {
+		if len(paths) > 0 && !slices.Contains(paths, req.Mod.Path) {
+			continue
+		}
+		if indirect {
+			markIndirect(req)
+		} else {
+			clearIndirect(req)
+		}
+	}
+	return nil
+}
+
+// markIndirect adds the // indirect mark to a require in place, the way
+// modfile does: a line comment "// note" becomes "// indirect; note"
+// markIndirect 与 modfile 相同地原地为 require 添加 // indirect 标记：行注释 "// note" 变为 "// indirect; note"
+func markIndirect(req *modfile.Require) {
+	if req.Indirect {
+		return
+	}
+	req.Indirect = true
+	line := req.Syntax
+	if line == nil {
+		return
+	}
+	if len(line.Suffix) == 0 {
+		line.Suffix = []modfile.Comment{{Token: "// indirect", Suffix: true}}
+		return
+	}
+	if text := strings.TrimSpace(strings.TrimPrefix(line.Suffix[0].Token, "//")); text != "" {
+		line.Suffix[0].Token = "// indirect; " + text
+	} else {
+		line.Suffix[0].Token = "// indirect"
+	}
+}
+
+// clearIndirect makes a require direct in place, the way modfile does:
+// "// indirect" is removed and "// indirect; note" becomes "// note"
+// clearIndirect 与 modfile 相同地原地将 require 改为直接依赖：删除 "// indirect"，"// indirect; note" 变为 "// note"
+func clearIndirect(req *modfile.Require) {
+	if !req.Indirect {
+		return
+	}
+	req.Indirect = false
+	line := req.Syntax
+	if line == nil || len(line.Suffix) == 0 {
+		return
+	}
+	text := strings.TrimSpace(strings.TrimPrefix(line.Suffix[0].Token, "//"))
+	if text == "indirect" {
+		line.Suffix = nil
+	} else if rest, ok := strings.CutPrefix(text, "indirect;"); ok {
+		line.Suffix[0].Token = "// " + strings.TrimSpace(rest)
+	}
+}
+
+// SetGoVersion sets the go directive and returns the formatted go.mod.
+// Lowering the version is refused since it may break code using newer
+// language features. A toolchain line no newer than the new version no
+// longer selects a toolchain and is removed, as the go command does.
+// Args: go.mod content, Go version (e.g. 1.22 or go1.22).
+// 设置 go 指令并返回格式化后的 go.mod。降低版本可能破坏使用了新语言特性的代码，因此会被拒绝。
+// 不高于新版本的 toolchain 行不再起作用，与 go 命令一样将其删除
+// 参数: go.mod 内容、Go 版本（如 1.22 或 go1.22）
+func SetGoVersion(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 2 {
+		return createErrorJSON("go version is required")
+	}
+
+	version := strings.TrimPrefix(args[1].String(), "go")
+	if !modfile.GoVersionRE.MatchString(version) {
+		return createErrorJSON(fmt.Sprintf("invalid go version: %s", args[1].String()))
+	}
+	if modFile.Go != nil && compareGoVersions(version, modFile.Go.Version) < 0 {
+		return createErrorJSON(fmt.Sprintf("refusing to downgrade go version from %s to %s", modFile.Go.Version, version))
+	}
+
+	if err := modFile.AddGoStmt(version); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to set go version: %s", err.Error()))
+	}
+
+	// "default" and invalid names have no version and are left alone
+	// "default" 和无效名称没有版本，保持不变
+	if modFile.Toolchain != nil {
+		toolchain := toolchainVersion(modFile.Toolchain.Name)
+		if toolchain != "" && compareGoVersions(toolchain, version) <= 0 {
+			modFile.DropToolchainStmt()
+		}
+	}
+
+	return formatModFile(modFile)
+}
+
+// RenameModule changes the module path, for example after forking, and
+// rewrites the replaces referring to the module or its sub-paths. Requires
+// are left alone: a sub-path module of the old path keeps its versions,
+// which do not exist under the new path. Returns the formatted go.mod.
+// Import paths in Go files are not changed.
+// Args: go.mod content, old module path, new module path.
+// 修改模块路径（例如 fork 之后），并改写引用该模块或其子路径的 replace。require 保持不变：
+// 旧路径下子路径模块的版本在新路径下并不存在。返回格式化后的 go.mod，不修改 Go 文件中的导入路径
+// 参数: go.mod 内容、旧模块路径、新模块路径
+func RenameModule(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 3 || args[1].String() == "" || args[2].String() == "" {
+		return createErrorJSON("old and new module paths are required")
+	}
+	oldPath, newPath := args[1].String(), args[2].String()
+
+	if modFile.Module == nil || modFile.Module.Mod.Path != oldPath {
+		return createErrorJSON(fmt.Sprintf("module path is not %s", oldPath))
+	}
+	if err := module.CheckPath(newPath); err != nil {
+		return createErrorJSON(fmt.Sprintf("invalid module path: %s", err.Error()))
+	}
+	if err := modFile.AddModuleStmt(newPath); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to set module path: %s", err.Error()))
+	}
+
+	rename := func(path string) (string, bool) {
+		if path == oldPath {
+			return newPath, true
+		}
+		if suffix, ok := strings.CutPrefix(path, oldPath+"/"); ok {
+			return newPath + "/" + suffix, true
+		}
+		return path, false
+	}
+	for _, rep := range modFile.Replace {
+		renameReplace(rep, rename)
+	}
+
+	return formatModFile(modFile)
+}
+
+// renameReplace rewrites the module paths of a replace with rename, which
+// keeps the suffix of sub-paths: oldpath/internal becomes newpath/internal.
+// modfile has no setter for the paths of an existing replace, so the tokens
+// of its line are rewritten in place, which keeps the line where it is.
+// Local directory targets are file paths and left alone.
+// renameReplace 使用 rename 改写 replace 的模块路径，子路径保留后缀：oldpath/internal 变为
+// newpath/internal。modfile 没有修改已有 replace 路径的方法，因此原地改写该行的词法单元，
+// 行的位置保持不变。本地目录目标是文件路径，不做修改
+func renameReplace(rep *modfile.Replace, rename func(string) (string, bool)) {
+	tokens := lineTokens(rep.Syntax)
+	arrow := slices.Index(tokens, "=>")
+	if arrow < 1 || arrow+1 >= len(tokens) {
+		return
+	}
+
+	if renamed, ok := rename(rep.Old.Path); ok {
+		rep.Old.Path = renamed
+		tokens[0] = modfile.AutoQuote(renamed)
+	}
+	if rep.New.Version == "" {
+		return
+	}
+	if renamed, ok := rename(rep.New.Path); ok {
+		rep.New.Path = renamed
+		tokens[arrow+1] = modfile.AutoQuote(renamed)
+	}
+}
+
+// lineTokens returns the tokens of a directive line after its verb, which
+// only lines outside a block repeat
+// lineTokens 返回指令行中动词之后的词法单元，只有块外的行才会带有动词
+func lineTokens(line *modfile.Line) []string {
+	if line.InBlock {
+		return line.Token
+	}
+	return line.Token[1:]
+}
+
+// TidyFormat sorts the entries of every require block and returns the
+// formatted go.mod: the formatting part of go mod tidy, without network
+// access. Blocks are kept as they are, so direct and indirect requires stay
+// separated, and comments move with their entries.
+// 对每个 require 块中的条目排序并返回格式化后的 go.mod：相当于 go mod tidy 的格式化部分，
+// 不需要网络访问。块结构保持不变，直接依赖与间接依赖仍然分开，注释随条目一起移动
+func TidyFormat(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+
+	for _, stmt := range modFile.Syntax.Stmt {
+		block, ok := stmt.(*modfile.LineBlock)
+		if !ok || len(block.Token) == 0 || block.Token[0] != "require" {
+			continue
+		}
+		sort.SliceStable(block.Line, func(i, j int) bool {
+			return requireLineLess(block.Line[i], block.Line[j])
+		})
+	}
+
+	return formatModFile(modFile)
+}
+
+// NormalizeReplacePaths rewrites the backslashes of local replace targets,
+// such as ..\shared written on Windows, to forward slashes, which every
+// platform accepts, and returns the formatted go.mod. Module targets are left
+// alone.
+// 将本地 replace 目标中的反斜杠（例如 Windows 上写入的 ..\shared）改写为所有平台都接受的正斜杠，
+// 并返回格式化后的 go.mod。模块目标保持不变
+func NormalizeReplacePaths(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	// modfile.Parse rejects backslashes in replacement directories outside
+	// Windows, so the lines are rewritten in the syntax of a lax parse, which
+	// skips replace directives, and the result is checked by a strict parse
+	// modfile.Parse 在 Windows 以外的系统上拒绝替换目录中的反斜杠，因此在宽松解析（跳过 replace 指令）
+	// 得到的语法树中改写这些行，再用严格解析检查结果
+	modFile, err := modfile.ParseLax("go.mod", []byte(args[0].String()), nil)
+	if err != nil {
+		return createParseErrorJSON(fmt.Errorf("failed to parse go.mod: %w", err))
+	}
+	for _, stmt := range modFile.Syntax.Stmt {
+		switch stmt := stmt.(type) {
+		case *modfile.Line:
+			if len(stmt.Token) > 0 && stmt.Token[0] == "replace" {
+				normalizeReplaceTarget(stmt.Token[1:])
+			}
+		case *modfile.LineBlock:
+			if len(stmt.Token) == 1 && stmt.Token[0] == "replace" {
+				for _, line := range stmt.Line {
+					normalizeReplaceTarget(line.Token)
+				}
+			}
+		}
+	}
+
+	content := string(modfile.Format(modFile.Syntax))
+	if _, err := parseModContent(content); err != nil {
+		return createParseErrorJSON(err)
+	}
+	return content
+}
+
+// normalizeReplaceTarget rewrites the target of a replace line in place. A
+// target is local when it has no version and modfile recognizes it as a
+// directory in Unix or Windows syntax: ./ or ../ prefixed, rooted or with a
+// drive letter. Quoted targets that do not unquote are left for the strict
+// parse to report.
+// normalizeReplaceTarget 原地改写 replace 行的目标。目标没有版本且被 modfile 识别为 Unix 或 Windows 语法的目录
+// （以 ./ 或 ../ 开头、以根开头或带盘符）时为本地目标。无法去掉引号的目标保持不变，由严格解析报告
+func normalizeReplaceTarget(tokens []string) {
+	arrow := slices.Index(tokens, "=>")
+	if arrow < 1 || arrow+2 != len(tokens) {
+		return
+	}
+
+	target := tokens[arrow+1]
+	if strings.HasPrefix(target, `"`) {
+		unquoted, err := strconv.Unquote(target)
+		if err != nil {
+			return
+		}
+		target = unquoted
+	}
+	if !modfile.IsDirectoryPath(target) || !strings.Contains(target, `\`) {
+		return
+	}
+	tokens[arrow+1] = modfile.AutoQuote(strings.ReplaceAll(target, `\`, "/"))
+}
+
+// requireLineLess orders require lines by module path, then by semantic
+// version. Malformed lines stay after well-formed ones.
+// requireLineLess 按模块路径、再按语义化版本对 require 行排序，格式错误的行排在正常行之后
+func requireLineLess(a, b *modfile.Line) bool {
+	if len(a.Token) < 2 || len(b.Token) < 2 {
+		return len(a.Token) >= 2 && len(b.Token) < 2
+	}
+	if a.Token[0] != b.Token[0] {
+		return a.Token[0] < b.Token[0]
+	}
+	return semver.Compare(a.Token[1], b.Token[1]) < 0
+}
+
+// parseModArg parses the go.mod content passed as the first argument.
+// On failure the second return value holds the error JSON.
+// parseModArg 解析第一个参数传入的 go.mod 内容，失败时第二个返回值为错误 JSON
+func parseModArg(args []js.Value) (*modfile.File, string) {
+	if len(args) == 0 {
+		return nil, createErrorJSON("no content provided")
+	}
+
+	modFile, err := parseModContent(args[0].String())
+	if err != nil {
+		return nil, createParseErrorJSON(err)
+	}
+	return modFile, ""
+}
+
+// formatModFile cleans up removed entries and formats the file
+// formatModFile 清理已删除的条目并格式化文件
+func formatModFile(modFile *modfile.File) string {
+	modFile.Cleanup()
+	return string(modfile.Format(modFile.Syntax))
+}
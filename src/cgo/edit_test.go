@@ -0,0 +1,72 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import "testing"
+
+func TestSetIndirectKeepsLines(t *testing.T) {
+	const content = `module example.com/a
+
+go 1.21
+
+require (
+	example.com/z v1.0.0
+	example.com/x v1.0.0 // pinned
+)
+
+require (
+	example.com/y v1.0.0 // indirect
+	example.com/w v1.0.0 // indirect; needed by y
+)
+`
+	tests := []struct {
+		name     string
+		paths    []string
+		indirect bool
+		want     string
+	}{
+		{"promote", nil, false, `module example.com/a
+
+go 1.21
+
+require (
+	example.com/z v1.0.0
+	example.com/x v1.0.0 // pinned
+)
+
+require (
+	example.com/y v1.0.0
+	example.com/w v1.0.0 // needed by y
+)
+`},
+		{"demote", []string{"example.com/z", "example.com/x"}, true, `module example.com/a
+
+go 1.21
+
+require (
+	example.com/z v1.0.0 // indirect
+	example.com/x v1.0.0 // indirect; pinned
+)
+
+require (
+	example.com/y v1.0.0 // indirect
+	example.com/w v1.0.0 // indirect; needed by y
+)
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modFile, err := parseModContent(content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := setIndirect(modFile, tt.paths, tt.indirect); err != nil {
+				t.Fatal(err)
+			}
+			if got := formatModFile(modFile); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
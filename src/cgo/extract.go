@@ -0,0 +1,243 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"syscall/js"
+)
+
+// ExtractInterface generates an interface declaring the exported methods of
+// a concrete type, such as a Greeter interface from EnglishGreeter.
+// Args: workspace files (JSON array of {path, content}), the file declaring
+// the type, the type name, interface name (optional, the type name +
+// "Interface" by default), file receiving the interface (optional, above the
+// type by default).
+// 根据具体类型的导出方法生成接口，例如从 EnglishGreeter 提取 Greeter 接口
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）、声明类型的文件、类型名称、接口名称（可选，默认为类型名加 "Interface"）、
+// 接收接口的文件（可选，默认放在类型上方）
+func ExtractInterface(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("files, type file and type name are required")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	typePath, typeName := args[1].String(), args[2].String()
+	ifaceName := typeName + "Interface"
+	if len(args) > 3 && args[3].Truthy() {
+		ifaceName = args[3].String()
+	}
+	if !token.IsIdentifier(ifaceName) {
+		return createErrorJSON(fmt.Sprintf("invalid interface name: %s", ifaceName))
+	}
+	targetPath := ""
+	if len(args) > 4 && args[4].Truthy() {
+		targetPath = args[4].String()
+	}
+
+	ws := newWorkspace(files)
+	pkg, _ := ws.file(typePath)
+	if pkg == nil {
+		return createErrorJSON(fmt.Sprintf("file not found: %s", typePath))
+	}
+	ws.checkAll()
+
+	named, ok := lookupNamed(pkg.types, typeName)
+	if !ok || !isWorkspaceType(ws, named) || types.IsInterface(named) {
+		return createErrorJSON(fmt.Sprintf("type not found: %s", typeName))
+	}
+
+	extracted, err := extractInterface(ws, named, ifaceName, targetPath)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(extracted)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ExtractedInterface is an interface declaration generated from a type
+// ExtractedInterface 表示根据类型生成的接口声明
+type ExtractedInterface struct {
+	Path     string             `json:"path"`    // file receiving the interface
+	Line     int                `json:"line"`    // 1-based line to insert the text before, 0 to append it to the file
+	Text     string             `json:"text"`    // interface declaration with its doc comment
+	Imports  []string           `json:"imports"` // import paths the file is missing
+	Methods  []string           `json:"methods"` // names of the extracted methods
+	Warnings []ExtractedWarning `json:"warnings"`
+}
+
+// ExtractedWarning is an extracted method whose signature uses unexported
+// types: other packages cannot implement the interface nor call the method
+// with values of their own
+// ExtractedWarning 表示签名中使用未导出类型的提取方法：其他包无法实现该接口，
+// 也无法用自己的值调用该方法
+type ExtractedWarning struct {
+	Method  string   `json:"method"`
+	Types   []string `json:"types"` // unexported types in the signature, e.g. config or state
+	Message string   `json:"message"`
+	Line    int      `json:"line"` // line of the method declaration
+}
+
+// extractInterface collects the exported methods of the method set of *T,
+// which holds the value and pointer receiver methods as well as the ones
+// promoted from embedded fields, so *T always implements the interface.
+// Without a target file the declaration goes above the type, doc comment
+// included. In another package the unexported types of the type's package
+// cannot be written at all and are rejected instead of reported.
+// extractInterface 收集 *T 方法集中的导出方法，其中包含值接收者与指针接收者的方法以及从嵌入字段提升的方法，
+// 因此 *T 总是实现该接口。未指定目标文件时声明放在类型（及其文档注释）上方。
+// 在其他包中无法引用类型所在包的未导出类型，因此直接拒绝而不是报告警告
+func extractInterface(ws *workspace, named *types.Named, ifaceName, targetPath string) (ExtractedInterface, error) {
+	obj := named.Obj()
+	typePath, typeLine := ws.position(obj.Pos())
+	extracted := ExtractedInterface{Path: targetPath, Imports: []string{}, Methods: []string{}, Warnings: []ExtractedWarning{}}
+	if targetPath == "" {
+		extracted.Path, extracted.Line = typePath, typeDeclLine(ws, obj, typeLine)
+	}
+
+	target, _ := ws.file(extracted.Path)
+	if target == nil {
+		return extracted, fmt.Errorf("file not found: %s", extracted.Path)
+	}
+	if target.types.Scope().Lookup(ifaceName) != nil {
+		return extracted, fmt.Errorf("%s is already declared in package %s", ifaceName, target.name)
+	}
+	otherPackage := target.types != obj.Pkg()
+	qf := stubQualifier(ws, extracted.Path, target.types, &extracted.Imports)
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "// %s is the set of exported methods of %s.\n", ifaceName, types.TypeString(named, qf))
+	fmt.Fprintf(&buf, "type %s interface {\n", ifaceName)
+	methodSet := types.NewMethodSet(types.NewPointer(named))
+	for i := 0; i < methodSet.Len(); i++ {
+		fn := methodSet.At(i).Obj().(*types.Func)
+		if !fn.Exported() {
+			continue
+		}
+		sig := fn.Type().(*types.Signature)
+
+		if unexported := unexportedTypes(sig, packageQualifier(obj.Pkg())); len(unexported) > 0 {
+			if otherPackage {
+				return extracted, fmt.Errorf("method %s uses unexported types %s, which package %s cannot refer to",
+					fn.Name(), strings.Join(unexported, ", "), target.name)
+			}
+			_, line := ws.position(fn.Pos())
+			extracted.Warnings = append(extracted.Warnings, ExtractedWarning{
+				Method: fn.Name(),
+				Types:  unexported,
+				Message: fmt.Sprintf("%s uses unexported types %s: other packages cannot implement %s or pass their own values to it",
+					fn.Name(), strings.Join(unexported, ", "), ifaceName),
+				Line: line,
+			})
+		}
+
+		extracted.Methods = append(extracted.Methods, fn.Name())
+		fmt.Fprintf(&buf, "\t%s%s\n", fn.Name(), strings.TrimPrefix(types.TypeString(sig, qf), "func"))
+	}
+	buf.WriteString("}\n")
+	if len(extracted.Methods) == 0 {
+		return extracted, fmt.Errorf("%s has no exported methods", obj.Name())
+	}
+
+	text, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return extracted, fmt.Errorf("failed to format interface: %s", err.Error())
+	}
+	extracted.Text = string(text)
+	if extracted.Line > 0 {
+		extracted.Text += "\n"
+	} else {
+		extracted.Text = "\n" + extracted.Text
+	}
+	return extracted, nil
+}
+
+// typeDeclLine returns the first line of the declaration of a type, its doc
+// comment included, so the interface is not inserted between the two
+// typeDeclLine 返回类型声明（包括文档注释）的第一行，避免把接口插到注释与类型之间
+func typeDeclLine(ws *workspace, obj *types.TypeName, line int) int {
+	pkg, _ := ws.file(ws.fset.Position(obj.Pos()).Filename)
+	for _, f := range pkg.files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE || obj.Pos() < gen.Pos() || obj.Pos() >= gen.End() {
+				continue
+			}
+			start := gen.Pos()
+			if gen.Doc != nil {
+				start = gen.Doc.Pos()
+			}
+			_, line = ws.position(start)
+			return line
+		}
+	}
+	return line
+}
+
+// unexportedTypes returns the unexported named types a signature refers to,
+// sorted, looking through pointers, composite types and type arguments
+// unexportedTypes 返回签名引用的未导出具名类型（已排序），会查看指针、复合类型与类型实参内部
+func unexportedTypes(sig *types.Signature, qf types.Qualifier) []string {
+	found := make(map[string]bool)
+	var walk func(t types.Type)
+	walk = func(t types.Type) {
+		switch t := types.Unalias(t).(type) {
+		case *types.Named:
+			if obj := t.Obj(); obj.Pkg() != nil && !obj.Exported() {
+				found[types.TypeString(t, qf)] = true
+			}
+			for i := 0; i < t.TypeArgs().Len(); i++ {
+				walk(t.TypeArgs().At(i))
+			}
+		case *types.Pointer:
+			walk(t.Elem())
+		case *types.Slice:
+			walk(t.Elem())
+		case *types.Array:
+			walk(t.Elem())
+		case *types.Chan:
+			walk(t.Elem())
+		case *types.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				walk(t.Field(i).Type())
+			}
+		case *types.Interface:
+			for i := 0; i < t.NumMethods(); i++ {
+				walk(t.Method(i).Type())
+			}
+		case *types.Signature:
+			for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+				for i := 0; i < tuple.Len(); i++ {
+					walk(tuple.At(i).Type())
+				}
+			}
+		}
+	}
+	walk(sig)
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,194 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+	"syscall/js"
+)
+
+// DiffInterfaces compares the method sets of the interfaces of two versions
+// of a Go file, such as the saved and the edited content, and reports the
+// added, removed and signature-changed methods.
+// Args: old file content, new file content, file path, interface name
+// (optional, every exported interface of the old version by default).
+// 比较 Go 文件两个版本（例如已保存与编辑中的内容）中接口的方法集，报告新增、删除与签名变化的方法
+// 参数: 旧文件内容、新文件内容、文件路径、接口名称（可选，默认为旧版本中所有导出的接口）
+func DiffInterfaces(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("old content, new content and file path are required")
+	}
+	name := ""
+	if len(args) > 3 && args[3].Truthy() {
+		name = args[3].String()
+	}
+
+	filePath := args[2].String()
+	oldWs, oldPkg := checkSingleFile(filePath, args[0].String())
+	newWs, newPkg := checkSingleFile(filePath, args[1].String())
+	if oldPkg == nil || newPkg == nil {
+		return createErrorJSON("file has no package clause")
+	}
+
+	names := []string{name}
+	if name == "" {
+		names = exportedInterfaces(oldPkg.types)
+	} else if _, ok := lookupNamed(oldPkg.types, name); !ok {
+		return createErrorJSON("interface not found in the old version: " + name)
+	}
+
+	diffs := []InterfaceDiff{}
+	for _, n := range names {
+		diff, err := diffInterface(oldWs, oldPkg, newWs, newPkg, n)
+		if err != nil {
+			return createErrorJSON(err.Error())
+		}
+		if diff.Deleted || len(diff.Added)+len(diff.Removed)+len(diff.Changed) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	result, err := json.Marshal(diffs)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// InterfaceDiff is the change of an interface between two versions. Every
+// change breaks someone: an added method breaks the implementations, a
+// removed one the callers, a changed signature both.
+// InterfaceDiff 表示接口在两个版本之间的变化。每种变化都会破坏某些代码：新增方法破坏实现，
+// 删除方法破坏调用方，签名变化则两者都会破坏
+type InterfaceDiff struct {
+	Interface string            `json:"interface"`
+	Deleted   bool              `json:"deleted"` // the new version no longer declares the interface
+	Line      int               `json:"line"`    // line in the new version, 0 when deleted
+	Added     []InterfaceMethod `json:"added"`   // lines of the new version
+	Removed   []InterfaceMethod `json:"removed"` // lines of the old version
+	Changed   []ChangedMethod   `json:"changed"`
+	// embedded interfaces from packages outside the file, in either version:
+	// their methods are unknown, so moving a method in or out of them shows
+	// up as added or removed
+	Unresolved []string `json:"unresolved"`
+}
+
+// ChangedMethod is a method whose signature changed
+// ChangedMethod 表示签名发生变化的方法
+type ChangedMethod struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`  // e.g. Greet(name string) string
+	New  string `json:"new"`  // e.g. Greet(name string, loud bool) string
+	Line int    `json:"line"` // line in the new version
+}
+
+// checkSingleFile type-checks one version of a file on its own
+// checkSingleFile 单独对文件的一个版本进行类型检查
+func checkSingleFile(filePath, content string) (*workspace, *wsPackage) {
+	ws := newWorkspace([]SourceFile{{Path: filePath, Content: content}})
+	pkg, _ := ws.file(filePath)
+	if pkg == nil {
+		return ws, nil
+	}
+	ws.checkAll()
+	return ws, pkg
+}
+
+// exportedInterfaces returns the names of the exported interfaces of a package, sorted
+// exportedInterfaces 返回包中导出接口的名称，已排序
+func exportedInterfaces(pkg *types.Package) []string {
+	var names []string
+	for _, name := range pkg.Scope().Names() {
+		if named, ok := lookupNamed(pkg, name); ok && ast.IsExported(name) && types.IsInterface(named) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// diffInterface compares the expanded method sets of an interface, so a
+// method moved into an embedded interface is not a change. Signatures are
+// compared by the types of their parameters and results, printed relative
+// to the package: renaming a parameter is not a change, while changing a
+// parameter type, the variadic marker or the number of results is.
+// diffInterface 比较接口展开后的方法集，因此移入嵌入接口的方法不算变化。签名按参数与返回值的类型比较，
+// 类型相对于所在包打印：重命名参数不算变化，而修改参数类型、可变参数标记或返回值个数算作变化
+func diffInterface(oldWs *workspace, oldPkg *wsPackage, newWs *workspace, newPkg *wsPackage, name string) (InterfaceDiff, error) {
+	diff := InterfaceDiff{Interface: name, Added: []InterfaceMethod{}, Removed: []InterfaceMethod{}, Changed: []ChangedMethod{}, Unresolved: []string{}}
+	oldNamed, _ := lookupNamed(oldPkg.types, name)
+	oldSet, err := interfaceMethodSet(oldWs, oldNamed)
+	if err != nil {
+		return diff, err
+	}
+
+	newNamed, ok := lookupNamed(newPkg.types, name)
+	if !ok || !types.IsInterface(newNamed) {
+		diff.Deleted = true
+		diff.Removed = oldSet.Methods
+		diff.Unresolved = oldSet.Unresolved
+		return diff, nil
+	}
+	newSet, err := interfaceMethodSet(newWs, newNamed)
+	if err != nil {
+		return diff, err
+	}
+	diff.Line = newSet.Line
+	diff.Unresolved = oldSet.Unresolved
+	for _, u := range newSet.Unresolved {
+		if !slices.Contains(diff.Unresolved, u) {
+			diff.Unresolved = append(diff.Unresolved, u)
+		}
+	}
+
+	oldMethods := make(map[string]InterfaceMethod)
+	for _, m := range oldSet.Methods {
+		oldMethods[m.Name] = m
+	}
+	newMethods := make(map[string]bool)
+	for _, m := range newSet.Methods {
+		newMethods[m.Name] = true
+		old, ok := oldMethods[m.Name]
+		if !ok {
+			diff.Added = append(diff.Added, m)
+			continue
+		}
+		if signatureTypes := func(named *types.Named) string {
+			obj, _, _ := types.LookupFieldOrMethod(named, false, named.Obj().Pkg(), m.Name)
+			return signatureShape(obj.(*types.Func).Type().(*types.Signature), packageQualifier(named.Obj().Pkg()))
+		}; signatureTypes(oldNamed) != signatureTypes(newNamed) {
+			diff.Changed = append(diff.Changed, ChangedMethod{Name: m.Name, Old: old.Signature, New: m.Signature, Line: m.Line})
+		}
+	}
+	for _, m := range oldSet.Methods {
+		if !newMethods[m.Name] {
+			diff.Removed = append(diff.Removed, m)
+		}
+	}
+	return diff, nil
+}
+
+// signatureShape prints the parameter and result types of a signature
+// without their names, e.g. (string, ...int) (bool, error)
+// signatureShape 打印签名中不带名称的参数与返回值类型，例如 (string, ...int) (bool, error)
+func signatureShape(sig *types.Signature, qf types.Qualifier) string {
+	tuple := func(t *types.Tuple, variadic bool) string {
+		parts := make([]string, t.Len())
+		for i := range parts {
+			typ := t.At(i).Type()
+			if variadic && i == t.Len()-1 {
+				parts[i] = "..." + types.TypeString(typ.(*types.Slice).Elem(), qf)
+				continue
+			}
+			parts[i] = types.TypeString(typ, qf)
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+	return tuple(sig.Params(), sig.Variadic()) + " " + tuple(sig.Results(), false)
+}
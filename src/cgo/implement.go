@@ -0,0 +1,190 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"strings"
+	"syscall/js"
+)
+
+// FindImplementations indexes Go source files and reports which concrete
+// types implement each interface. Input is a JSON array of {path, content};
+// go.mod files may be included so that imports between workspace packages
+// resolve.
+// FindImplementations 索引 Go 源文件并报告每个接口的具体实现类型。
+// 输入为 {path, content} 的 JSON 数组；可以包含 go.mod 文件以解析工作空间内包之间的导入
+func FindImplementations(this js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return createErrorJSON("no files provided")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+
+	idx := newImplIndex()
+	idx.update(files, nil)
+
+	jsonData, err := json.Marshal(idx.sorted())
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+	return string(jsonData)
+}
+
+// newInterfaceImplementations creates the entry of an interface without
+// implementers. The complete method set includes methods of embedded
+// interfaces, positioned at their original declaration.
+// newInterfaceImplementations 创建尚无实现类型的接口条目。完整方法集包含嵌入接口的方法，位置为其原始声明处
+func newInterfaceImplementations(ws *workspace, obj *types.TypeName) *InterfaceImplementations {
+	iface := obj.Type().Underlying().(*types.Interface)
+	path, line := ws.position(obj.Pos())
+	item := &InterfaceImplementations{
+		Interface:       obj.Name(),
+		Package:         obj.Pkg().Path(),
+		Path:            path,
+		Line:            line,
+		Methods:         make([]MethodInfo, 0, iface.NumMethods()),
+		Implementations: []Implementation{},
+		Partial:         []Implementation{},
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		item.Methods = append(item.Methods, newMethodInfo(ws, iface.Method(i)))
+	}
+	return item
+}
+
+// matchImplementations matches concrete types against an interface and
+// appends the implementers and partial implementers to its entry; a type
+// whose only matches are ambiguous promotions counts as partial
+// matchImplementations 将具体类型与接口匹配，并将实现类型和部分实现类型追加到接口条目中；只有歧义提升方法的类型也算部分实现
+func matchImplementations(ws *workspace, item *InterfaceImplementations, obj *types.TypeName, concretes []*types.TypeName) {
+	iface := obj.Type().Underlying().(*types.Interface)
+	for _, concrete := range concretes {
+		impl, ok := implementationOf(ws, concrete, iface)
+		switch {
+		case ok:
+			item.Implementations = append(item.Implementations, impl)
+		case len(impl.Methods) > 0 || len(impl.Ambiguous) > 0:
+			item.Partial = append(item.Partial, impl)
+		}
+	}
+}
+
+// implementationOf reports whether a type implements the interface. When
+// only the pointer type does (pointer receivers), the pointer type is
+// reported. Types that declare only some of the methods are returned with
+// ok false and the methods they do declare.
+// implementationOf 判断类型是否实现了接口。当只有指针类型实现时（指针接收者），报告指针类型。
+// 只声明了部分方法的类型返回 ok 为 false，并附带已声明的方法
+func implementationOf(ws *workspace, obj *types.TypeName, iface *types.Interface) (Implementation, bool) {
+	path, line := ws.position(obj.Pos())
+	impl := Implementation{
+		Name:    obj.Name(),
+		Package: obj.Pkg().Path(),
+		Path:    path,
+		Line:    line,
+	}
+
+	var typ types.Type = obj.Type()
+	ok := true
+	switch {
+	case types.Implements(typ, iface):
+	case types.Implements(types.NewPointer(typ), iface):
+		typ = types.NewPointer(typ)
+		impl.Name = "*" + impl.Name
+		impl.Pointer = true
+	default:
+		typ, ok = types.NewPointer(typ), false
+	}
+
+	// Record the concrete method that satisfies each interface method. The
+	// method set includes methods promoted from embedded fields, struct and
+	// interface ones alike; a name promoted from two fields at the same depth
+	// is not in the method set, and such a type does not implement the interface.
+	// 记录满足每个接口方法的具体方法。方法集包含从嵌入字段（结构体与接口）提升的方法；
+	// 在同一深度由两个字段提升的同名方法不在方法集中，这样的类型不实现该接口
+	methodSet := types.NewMethodSet(typ)
+	impl.Methods = make([]MethodInfo, 0, iface.NumMethods())
+	impl.Ambiguous = []string{}
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		sel := methodSet.Lookup(m.Pkg(), m.Name())
+		if sel == nil {
+			if found, index, _ := types.LookupFieldOrMethod(typ, false, m.Pkg(), m.Name()); found == nil && index != nil {
+				impl.Ambiguous = append(impl.Ambiguous, m.Name())
+			}
+			continue
+		}
+		info := newMethodInfo(ws, sel.Obj().(*types.Func))
+		info.Via = embeddingPath(typ, sel.Index())
+		impl.Methods = append(impl.Methods, info)
+	}
+	return impl, ok
+}
+
+// embeddingPath names the embedded fields a method is promoted through,
+// e.g. Loud.EnglishGreeter, from the index of its selection; "" for a
+// method declared on the type itself
+// embeddingPath 根据选择的索引给出方法提升所经过的嵌入字段，例如 Loud.EnglishGreeter；类型自身声明的方法返回 ""
+func embeddingPath(typ types.Type, index []int) string {
+	var fields []string
+	for _, i := range index[:len(index)-1] {
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		st, ok := typ.Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		field := st.Field(i)
+		fields = append(fields, field.Name())
+		typ = field.Type()
+	}
+	return strings.Join(fields, ".")
+}
+
+// newMethodInfo describes a method declaration
+// newMethodInfo 描述方法声明
+func newMethodInfo(ws *workspace, fn *types.Func) MethodInfo {
+	path, line := ws.position(fn.Pos())
+	return MethodInfo{Name: fn.Name(), Path: path, Line: line}
+}
+
+// InterfaceImplementations lists the implementers of an interface
+// InterfaceImplementations 列出接口的实现类型
+type InterfaceImplementations struct {
+	Interface       string           `json:"interface"`
+	Package         string           `json:"package"`
+	Path            string           `json:"path"`
+	Line            int              `json:"line"`
+	Methods         []MethodInfo     `json:"methods"` // complete method set, embedded interfaces included
+	Implementations []Implementation `json:"implementations"`
+	Partial         []Implementation `json:"partial"` // types declaring only some of the methods
+}
+
+// Implementation is a concrete type implementing an interface
+// Implementation 表示实现接口的具体类型
+type Implementation struct {
+	Name      string       `json:"name"`    // type name, prefixed with * for pointer receivers
+	Package   string       `json:"package"` // import path of the declaring package
+	Pointer   bool         `json:"pointer"` // only the pointer type implements the interface
+	Path      string       `json:"path"`
+	Line      int          `json:"line"`
+	Methods   []MethodInfo `json:"methods"`   // methods satisfying the interface methods
+	Ambiguous []string     `json:"ambiguous"` // interface methods promoted from several embedded fields at the same depth
+}
+
+// MethodInfo is the position of a method declaration
+// MethodInfo 表示方法声明的位置
+type MethodInfo struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Via  string `json:"via"` // embedded fields the method is promoted through, e.g. Loud.EnglishGreeter
+}
@@ -0,0 +1,106 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/module"
+)
+
+// ResolveImportPath returns the import path of the package in a directory
+// from the module path and the module root.
+// Args: module path, module root directory, directory of the file.
+// 根据模块路径与模块根目录返回目录中包的导入路径
+// 参数: 模块路径、模块根目录、文件所在目录
+func ResolveImportPath(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("module path, module root and directory are required")
+	}
+
+	importPath, err := resolveImportPath(args[0].String(), args[1].String(), args[2].String())
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(importPath)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ImportPath is the import path of a package directory
+// ImportPath 表示包目录的导入路径
+type ImportPath struct {
+	ImportPath string `json:"importPath"`
+	Internal   bool   `json:"internal"` // the path has an internal element
+	// Only packages under this path may import an internal package, e.g.
+	// example.com/m/a for example.com/m/a/internal/b; "" when not internal
+	// 只有此路径下的包可以导入 internal 包，例如 example.com/m/a/internal/b 对应 example.com/m/a；非 internal 时为 ""
+	ImportableFrom string `json:"importableFrom"`
+}
+
+// resolveImportPath joins the module path with the directory relative to the
+// module root. Windows separators and drive letter case are normalized, and
+// directories the go command ignores, testdata and names starting with . or
+// _, are rejected since they hold no importable package.
+// resolveImportPath 将模块路径与目录相对模块根目录的路径拼接。Windows 分隔符与盘符大小写会被规范化，
+// go 命令忽略的目录（testdata 以及以 . 或 _ 开头的名称）会被拒绝，因为其中没有可导入的包
+func resolveImportPath(modulePath, root, dir string) (ImportPath, error) {
+	if err := module.CheckImportPath(modulePath); err != nil {
+		return ImportPath{}, fmt.Errorf("invalid module path: %s", err.Error())
+	}
+
+	root, dir = cleanDir(root), cleanDir(dir)
+	rel := ""
+	switch {
+	case dir == root:
+	case strings.HasPrefix(dir, strings.TrimSuffix(root, "/")+"/"):
+		rel = strings.TrimPrefix(dir, strings.TrimSuffix(root, "/")+"/")
+	default:
+		return ImportPath{}, fmt.Errorf("%s is not inside module root %s", dir, root)
+	}
+
+	result := ImportPath{ImportPath: modulePath}
+	if rel != "" {
+		for _, elem := range strings.Split(rel, "/") {
+			if elem == "testdata" || strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+				return ImportPath{}, fmt.Errorf("directory %s is ignored by the go command", elem)
+			}
+		}
+		result.ImportPath = modulePath + "/" + rel
+	}
+	if err := module.CheckImportPath(result.ImportPath); err != nil {
+		return ImportPath{}, fmt.Errorf("invalid import path: %s", err.Error())
+	}
+
+	// The last internal element decides, as with the go command
+	// 与 go 命令一样，由最后一个 internal 元素决定
+	elems := strings.Split(result.ImportPath, "/")
+	for i := len(elems) - 1; i >= 0; i-- {
+		if elems[i] == "internal" {
+			result.Internal = true
+			result.ImportableFrom = strings.Join(elems[:i], "/")
+			break
+		}
+	}
+	return result, nil
+}
+
+// cleanDir converts a directory to a clean slash path with a lower-case
+// drive letter, so c:\mod and C:/mod compare equal
+// cleanDir 将目录转换为干净的斜杠路径并将盘符转为小写，使 c:\mod 与 C:/mod 相等
+func cleanDir(dir string) string {
+	dir = path.Clean(slashPath(dir))
+	if len(dir) >= 2 && dir[1] == ':' {
+		dir = strings.ToLower(dir[:1]) + dir[1:]
+	}
+	return dir
+}
@@ -0,0 +1,303 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+)
+
+// CheckIndirectImports reports indirect requires whose packages are
+// imported directly by the project, which go mod tidy would make direct.
+// Args: go.mod content, JSON array of import paths used by the project's source.
+// 报告被项目直接导入包的间接依赖，go mod tidy 会将其变为直接依赖
+// 参数: go.mod 内容、项目源码使用的导入路径 JSON 数组
+func CheckIndirectImports(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 2 {
+		return createErrorJSON("import paths are required")
+	}
+
+	var imports []string
+	if err := json.Unmarshal([]byte(args[1].String()), &imports); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse import paths: %s", err.Error()))
+	}
+
+	modInfo := createModInfo(modFile)
+	result, err := json.Marshal(directIndirects(modInfo, imports))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// DirectImport is an indirect require imported directly by the project
+// DirectImport 表示被项目直接导入的间接依赖
+type DirectImport struct {
+	Path    string   `json:"path"`
+	Version string   `json:"version"`
+	Imports []string `json:"imports"` // imported packages provided by the module
+	Start   Position `json:"start"`   // start of the require line
+	End     Position `json:"end"`     // end of the require line
+}
+
+// directIndirects assigns each import path to the module providing it: the
+// longest module path that equals the import path or is a prefix of it at a
+// path-segment boundary, so example.com/a does not own example.com/ab and a
+// nested module example.com/a/sub owns example.com/a/sub/pkg. Imports of the
+// main module belong to no require.
+// directIndirects 将每个导入路径归属到提供它的模块：与导入路径相同、或在路径段边界上是其前缀的
+// 最长模块路径，因此 example.com/a 不拥有 example.com/ab，而嵌套模块 example.com/a/sub
+// 拥有 example.com/a/sub/pkg。主模块的导入不属于任何依赖
+func directIndirects(modInfo *ModFile, imports []string) []DirectImport {
+	owned := ownedImports(modInfo, imports)
+
+	results := []DirectImport{}
+	for _, req := range modInfo.Require {
+		pkgs, ok := owned[req.Path]
+		if !req.Indirect || !ok {
+			continue
+		}
+		sort.Strings(pkgs)
+		results = append(results, DirectImport{
+			Path:    req.Path,
+			Version: req.Version,
+			Imports: pkgs,
+			Start:   req.Start,
+			End:     req.End,
+		})
+	}
+	return results
+}
+
+// ownedImports groups import paths by the require providing them
+// ownedImports 按提供导入路径的依赖对其分组
+func ownedImports(modInfo *ModFile, imports []string) map[string][]string {
+	owned := make(map[string][]string)
+	for _, imp := range imports {
+		owner := modInfo.Module
+		if !isPathPrefix(owner, imp) {
+			owner = ""
+		}
+		for _, req := range modInfo.Require {
+			if len(req.Path) > len(owner) && isPathPrefix(req.Path, imp) {
+				owner = req.Path
+			}
+		}
+		if owner != "" && owner != modInfo.Module && !slices.Contains(owned[owner], imp) {
+			owned[owner] = append(owned[owner], imp)
+		}
+	}
+	return owned
+}
+
+// FindUnusedRequires reports direct requires that provide none of the
+// packages imported by the project, which are candidates for removal.
+// Args: go.mod content, JSON array of import paths used by the project's source,
+// JSON object mapping file path to content of the files excluded by build
+// constraints (optional), whose imports count as used too.
+// 报告不提供项目导入的任何包的直接依赖，这些依赖可以考虑移除
+// 参数: go.mod 内容、项目源码使用的导入路径 JSON 数组、被构建约束排除的文件路径到内容的 JSON 对象（可选），
+// 这些文件的导入同样视为已使用
+func FindUnusedRequires(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 2 {
+		return createErrorJSON("import paths are required")
+	}
+
+	var imports []string
+	if err := json.Unmarshal([]byte(args[1].String()), &imports); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse import paths: %s", err.Error()))
+	}
+
+	if len(args) > 2 && args[2].Truthy() {
+		var files map[string]string
+		if err := json.Unmarshal([]byte(args[2].String()), &files); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+		}
+		imports = append(imports, fileImports(files)...)
+	}
+
+	result, err := json.Marshal(unusedRequires(modFile, imports))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// UnusedRequire is a direct require that no import uses
+// UnusedRequire 表示没有被任何导入使用的直接依赖
+type UnusedRequire struct {
+	Path    string   `json:"path"`
+	Version string   `json:"version"`
+	Start   Position `json:"start"` // start of the require line
+	End     Position `json:"end"`   // end of the require line
+}
+
+// unusedRequires skips indirect requires, which may only be there to raise
+// a version in the module graph, and modules providing a tool directive,
+// which go run and go tool need although nothing imports them.
+// unusedRequires 跳过间接依赖（它们可能只是为了提升模块图中的版本），
+// 以及提供 tool 指令的模块（go run 与 go tool 需要它们，尽管没有代码导入）
+func unusedRequires(modFile *modfile.File, imports []string) []UnusedRequire {
+	modInfo := createModInfo(modFile)
+	for _, tool := range modFile.Tool {
+		imports = append(imports, tool.Path)
+	}
+	owned := ownedImports(modInfo, imports)
+
+	results := []UnusedRequire{}
+	for _, req := range modInfo.Require {
+		if _, ok := owned[req.Path]; ok || req.Indirect {
+			continue
+		}
+		results = append(results, UnusedRequire{
+			Path:    req.Path,
+			Version: req.Version,
+			Start:   req.Start,
+			End:     req.End,
+		})
+	}
+	return results
+}
+
+// Usages reported by ClassifyRequireUsage
+// ClassifyRequireUsage 报告的使用方式
+const (
+	UsageTestOnly = "test-only" // imported from _test.go files only
+	UsageBuild    = "build"     // imported from non-test files only
+	UsageBoth     = "both"      // imported from both
+)
+
+// ClassifyRequireUsage labels each require by where its packages are
+// imported: only from tests, which keeps it out of production binaries,
+// only from the build, or both.
+// Args: go.mod content, JSON array of import paths of non-test files, JSON
+// array of import paths of _test.go files.
+// 按 require 的包被导入的位置为其分类：只在测试中，不会进入生产构建；只在构建中；或两者都有
+// 参数: go.mod 内容、非测试文件导入路径的 JSON 数组、_test.go 文件导入路径的 JSON 数组
+func ClassifyRequireUsage(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 3 {
+		return createErrorJSON("build and test import paths are required")
+	}
+
+	var buildImports, testImports []string
+	if err := json.Unmarshal([]byte(args[1].String()), &buildImports); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse build import paths: %s", err.Error()))
+	}
+	if err := json.Unmarshal([]byte(args[2].String()), &testImports); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse test import paths: %s", err.Error()))
+	}
+
+	result, err := json.Marshal(requireUsages(createModInfo(modFile), buildImports, testImports))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// RequireUsage is where the packages of a require are imported
+// RequireUsage 表示 require 的包被导入的位置
+type RequireUsage struct {
+	Path    string   `json:"path"`
+	Version string   `json:"version"`
+	Usage   string   `json:"usage"`   // test-only, build or both
+	Imports []string `json:"imports"` // imported packages provided by the module, sorted
+	Start   Position `json:"start"`   // start of the require line
+	End     Position `json:"end"`     // end of the require line
+}
+
+// requireUsages assigns imports to requires the way directIndirects does, so
+// a nested module owns its own packages. Requires none of the imports
+// belong to are left out; FindUnusedRequires reports them.
+// requireUsages 与 directIndirects 一样将导入归属到 require，因此嵌套模块拥有自己的包。
+// 没有任何导入的 require 不包括在内，由 FindUnusedRequires 报告
+func requireUsages(modInfo *ModFile, buildImports, testImports []string) []RequireUsage {
+	built, tested := ownedImports(modInfo, buildImports), ownedImports(modInfo, testImports)
+
+	results := []RequireUsage{}
+	for _, req := range modInfo.Require {
+		buildPkgs, inBuild := built[req.Path]
+		testPkgs, inTests := tested[req.Path]
+		usage := UsageBoth
+		switch {
+		case !inBuild && !inTests:
+			continue
+		case !inBuild:
+			usage = UsageTestOnly
+		case !inTests:
+			usage = UsageBuild
+		}
+
+		pkgs := slices.Clone(buildPkgs)
+		for _, pkg := range testPkgs {
+			if !slices.Contains(pkgs, pkg) {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+		sort.Strings(pkgs)
+		results = append(results, RequireUsage{
+			Path:    req.Path,
+			Version: req.Version,
+			Usage:   usage,
+			Imports: pkgs,
+			Start:   req.Start,
+			End:     req.End,
+		})
+	}
+	return results
+}
+
+// fileImports returns the import paths of Go files. Syntax errors after the
+// imports do not matter, so only files failing before them are skipped.
+// fileImports 返回 Go 文件的导入路径。导入之后的语法错误不影响结果，因此只跳过在导入之前就解析失败的文件
+func fileImports(files map[string]string) []string {
+	var imports []string
+	fset := token.NewFileSet()
+	for path, content := range files {
+		file, _ := parser.ParseFile(fset, path, content, parser.ImportsOnly)
+		if file == nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			if imp, err := strconv.Unquote(spec.Path.Value); err == nil {
+				imports = append(imports, imp)
+			}
+		}
+	}
+	return imports
+}
+
+// isPathPrefix reports whether the import path is prefix itself or a
+// package below it
+// isPathPrefix 判断导入路径是否为 prefix 本身或其下的包
+func isPathPrefix(prefix, path string) bool {
+	if prefix == "" {
+		return false
+	}
+	rest, ok := strings.CutPrefix(path, prefix)
+	return ok && (rest == "" || rest[0] == '/')
+}
@@ -0,0 +1,240 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"syscall/js"
+)
+
+// implementationIndex is kept between UpdateImplementations calls, the WASM
+// module staying loaded for the lifetime of the extension
+// implementationIndex 在多次 UpdateImplementations 调用之间保留，WASM 模块在扩展的生命周期内一直加载
+var implementationIndex *implIndex
+
+// UpdateImplementations applies changed and removed files to the
+// incremental implementation index and returns all interfaces with their
+// implementers, in the same form as FindImplementations.
+// Args: JSON array of changed {path, content}, JSON array of removed paths,
+// whether to discard the index first.
+// 将变化和删除的文件应用到增量实现索引，并以与 FindImplementations 相同的形式返回所有接口及其实现
+// 参数: 变化文件 {path, content} 的 JSON 数组、删除路径的 JSON 数组、是否先丢弃索引
+func UpdateImplementations(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("changed and removed files are required")
+	}
+
+	var changed []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &changed); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse changed files: %s", err.Error()))
+	}
+	var removed []string
+	if err := json.Unmarshal([]byte(args[1].String()), &removed); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse removed files: %s", err.Error()))
+	}
+
+	if implementationIndex == nil || (len(args) > 2 && args[2].Truthy()) {
+		implementationIndex = newImplIndex()
+	}
+	implementationIndex.update(changed, removed)
+
+	result, err := json.Marshal(implementationIndex.sorted())
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// implIndex caches the parsed files, type-checked packages and matched
+// interface/implementer pairs of a workspace. An update re-checks only the
+// packages of changed files plus the packages importing them, and re-matches
+// only the pairs with a side in those packages, so the cost of an edit is
+// bounded by the packages it can affect rather than by the workspace.
+// implIndex 缓存工作空间已解析的文件、已类型检查的包以及已匹配的接口/实现对。更新时只重新检查变化文件所在的包
+// 及导入它们的包，且只重新匹配有一方位于这些包中的接口/实现对，因此一次编辑的开销取决于它能影响的包，而不是整个工作空间
+type implIndex struct {
+	ws       *workspace
+	contents map[string]string                    // indexed file path -> content
+	results  map[string]*InterfaceImplementations // package.Interface -> implementers
+}
+
+// newImplIndex creates an empty index
+// newImplIndex 创建空索引
+func newImplIndex() *implIndex {
+	return &implIndex{
+		ws:       newWorkspace(nil),
+		contents: make(map[string]string),
+		results:  make(map[string]*InterfaceImplementations),
+	}
+}
+
+// update applies file changes. Files whose content is unchanged are
+// skipped. A changed go.mod can move every package to a new import path, so
+// it rebuilds the whole index.
+// update 应用文件变化，内容未变的文件会被跳过。go.mod 变化可能改变所有包的导入路径，因此会重建整个索引
+func (idx *implIndex) update(changed []SourceFile, removed []string) {
+	var dirty []string
+	rebuild := false
+	markDirty := func(pkg *wsPackage) {
+		if pkg != nil {
+			dirty = append(dirty, pkg.importPath)
+		}
+	}
+
+	for _, filePath := range removed {
+		if _, ok := idx.contents[filePath]; !ok {
+			continue
+		}
+		delete(idx.contents, filePath)
+		if path.Base(slashPath(filePath)) == "go.mod" {
+			rebuild = true
+			continue
+		}
+		markDirty(idx.ws.removeFile(filePath))
+	}
+
+	for _, file := range changed {
+		if old, ok := idx.contents[file.Path]; ok && old == file.Content {
+			continue
+		}
+		idx.contents[file.Path] = file.Content
+		if path.Base(slashPath(file.Path)) == "go.mod" {
+			rebuild = true
+			continue
+		}
+		if strings.HasSuffix(file.Path, ".go") {
+			markDirty(idx.ws.removeFile(file.Path))
+			markDirty(idx.ws.addFile(file))
+		}
+	}
+
+	if rebuild {
+		files := make([]SourceFile, 0, len(idx.contents))
+		for filePath, content := range idx.contents {
+			files = append(files, SourceFile{Path: filePath, Content: content})
+		}
+		idx.ws = newWorkspace(files)
+		idx.results = make(map[string]*InterfaceImplementations)
+		dirty = dirty[:0]
+		for importPath := range idx.ws.packages {
+			dirty = append(dirty, importPath)
+		}
+	}
+	if len(dirty) > 0 {
+		idx.recheck(idx.ws.importers(dirty))
+	}
+}
+
+// recheck type-checks the affected packages again and re-matches the pairs
+// involving them: interfaces of affected packages against every type, and
+// the other interfaces against the types of affected packages only
+// recheck 重新类型检查受影响的包并重新匹配涉及它们的接口/实现对：受影响包中的接口与所有类型匹配，
+// 其他接口只与受影响包中的类型匹配
+func (idx *implIndex) recheck(affected map[string]bool) {
+	inAffected := func(impl Implementation) bool { return affected[impl.Package] }
+	for key, item := range idx.results {
+		if affected[item.Package] {
+			delete(idx.results, key)
+			continue
+		}
+		item.Implementations = slices.DeleteFunc(item.Implementations, inAffected)
+		item.Partial = slices.DeleteFunc(item.Partial, inAffected)
+	}
+
+	for importPath := range affected {
+		if pkg, ok := idx.ws.packages[importPath]; ok {
+			pkg.types, pkg.info = nil, nil
+		}
+	}
+	idx.ws.checkAll()
+
+	var concretes, affectedConcretes, affectedInterfaces []*types.TypeName
+	for _, pkg := range idx.ws.sortedPackages() {
+		interfaces, pkgConcretes := packageTypes(pkg)
+		concretes = append(concretes, pkgConcretes...)
+		if affected[pkg.importPath] {
+			affectedInterfaces = append(affectedInterfaces, interfaces...)
+			affectedConcretes = append(affectedConcretes, pkgConcretes...)
+		}
+	}
+
+	for _, obj := range affectedInterfaces {
+		item := newInterfaceImplementations(idx.ws, obj)
+		matchImplementations(idx.ws, item, obj, concretes)
+		idx.results[item.Package+"."+item.Interface] = item
+	}
+	for _, item := range idx.results {
+		if affected[item.Package] {
+			continue
+		}
+		obj := idx.ws.packages[item.Package].types.Scope().Lookup(item.Interface).(*types.TypeName)
+		matchImplementations(idx.ws, item, obj, affectedConcretes)
+		sortImplementations(item.Implementations)
+		sortImplementations(item.Partial)
+	}
+}
+
+// sorted returns the interfaces ordered by package and name
+// sorted 返回按包和名称排序的接口
+func (idx *implIndex) sorted() []InterfaceImplementations {
+	result := make([]InterfaceImplementations, 0, len(idx.results))
+	for _, item := range idx.results {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Package != result[j].Package {
+			return result[i].Package < result[j].Package
+		}
+		return result[i].Interface < result[j].Interface
+	})
+	return result
+}
+
+// packageTypes returns the non-empty interfaces and the concrete types
+// declared in a package. Generic types are skipped since they have no
+// method set until instantiated.
+// packageTypes 返回包中声明的非空接口和具体类型。泛型类型在实例化之前没有方法集，因此会被跳过
+func packageTypes(pkg *wsPackage) (interfaces, concretes []*types.TypeName) {
+	if pkg.types == nil {
+		return nil, nil
+	}
+	scope := pkg.types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		if iface, ok := named.Underlying().(*types.Interface); ok {
+			if iface.NumMethods() > 0 {
+				interfaces = append(interfaces, obj)
+			}
+			continue
+		}
+		concretes = append(concretes, obj)
+	}
+	return interfaces, concretes
+}
+
+// sortImplementations orders implementers by package and type name, the
+// order a full rebuild produces
+// sortImplementations 按包和类型名称对实现类型排序，与完整重建的顺序一致
+func sortImplementations(impls []Implementation) {
+	sort.SliceStable(impls, func(i, j int) bool {
+		if impls[i].Package != impls[j].Package {
+			return impls[i].Package < impls[j].Package
+		}
+		return strings.TrimPrefix(impls[i].Name, "*") < strings.TrimPrefix(impls[j].Name, "*")
+	})
+}
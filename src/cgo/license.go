@@ -0,0 +1,115 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+// JoinLicenses parses a go.mod file and attaches the license of each
+// require, which the extension finds by scanning the module cache. Requires
+// without license information and requires whose license is flagged, such as
+// GPL or AGPL, are reported in the warnings.
+// Args: go.mod content, JSON object mapping module path to license, JSON
+// array of flagged licenses (optional).
+// 解析 go.mod 文件并附加每个 require 的许可证，许可证由扩展扫描模块缓存得到。
+// 没有许可证信息以及许可证被标记（例如 GPL 或 AGPL）的依赖在警告中报告
+// 参数: go.mod 内容、模块路径到许可证的 JSON 对象、被标记许可证的 JSON 数组（可选）
+func JoinLicenses(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("go.mod content and licenses are required")
+	}
+
+	var licenses map[string]string
+	if err := json.Unmarshal([]byte(args[1].String()), &licenses); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse licenses: %s", err.Error()))
+	}
+	var flagged []string
+	if len(args) > 2 && args[2].Truthy() {
+		if err := json.Unmarshal([]byte(args[2].String()), &flagged); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse flagged licenses: %s", err.Error()))
+		}
+	}
+
+	modInfo, err := parseModInfo(args[0].String())
+	if err != nil {
+		return createParseErrorJSON(err)
+	}
+	joinLicenses(modInfo, licenses, flagged)
+
+	result, err := json.Marshal(modInfo)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// joinLicenses sets the license of every require. Requires replaced by a
+// local directory are not in the module cache, so a missing license is only
+// reported for the others.
+// joinLicenses 设置每个 require 的许可证。被替换为本地目录的依赖不在模块缓存中，
+// 因此只对其他依赖报告缺少许可证
+func joinLicenses(modInfo *ModFile, licenses map[string]string, flagged []string) {
+	local := map[string]bool{}
+	for _, rep := range modInfo.Replace {
+		if rep.Kind == "local" {
+			local[rep.OldPath] = true
+		}
+	}
+
+	for i := range modInfo.Require {
+		req := &modInfo.Require[i]
+		req.License = strings.TrimSpace(licenses[req.Path])
+		if req.License == "" {
+			if !local[req.Path] {
+				modInfo.Warnings = append(modInfo.Warnings, Warning{
+					Kind:    WarningMissingLicense,
+					Message: fmt.Sprintf("no license information for %s", req.Path),
+					Line:    req.Start.Line,
+				})
+			}
+			continue
+		}
+		if name := flaggedLicense(req.License, flagged); name != "" {
+			modInfo.Warnings = append(modInfo.Warnings, Warning{
+				Kind:    WarningFlaggedLicense,
+				Message: fmt.Sprintf("%s is licensed under %s, which is flagged as %s", req.Path, req.License, name),
+				Line:    req.Start.Line,
+			})
+		}
+	}
+}
+
+// flaggedLicense returns the flagged license a license matches. The license
+// may be an SPDX expression such as "MIT OR GPL-2.0" and each identifier is
+// checked; a flagged name matches an identifier case-insensitively, either
+// exactly or followed by a version or variant, so GPL matches GPL-3.0-only
+// but not LGPL-2.1.
+// flaggedLicense 返回许可证匹配的被标记许可证。许可证可以是 "MIT OR GPL-2.0" 这样的 SPDX 表达式，
+// 逐个检查其中的标识符；被标记的名称不区分大小写地匹配标识符本身或其后跟版本或变体的形式，
+// 因此 GPL 匹配 GPL-3.0-only，但不匹配 LGPL-2.1
+func flaggedLicense(license string, flagged []string) string {
+	ids := strings.FieldsFunc(license, func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')' || r == ',' || r == '/'
+	})
+	for _, name := range flagged {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		for _, id := range ids {
+			if strings.EqualFold(id, name) {
+				return name
+			}
+			if len(id) > len(name) && strings.EqualFold(id[:len(name)], name) && strings.ContainsRune("-+.v", rune(id[len(name)])) {
+				return name
+			}
+		}
+	}
+	return ""
+}
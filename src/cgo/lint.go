@@ -0,0 +1,143 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Issue kinds reported by LintMod next to the warning kinds
+// LintMod 在警告类型之外报告的问题类型
+const (
+	IssueParseError       = "parse-error"
+	IssueInvalidPath      = "invalid-path"
+	IssueInvalidVersion   = "invalid-version"
+	IssueInvalidGoVersion = "invalid-go-version"
+)
+
+// Issue severities, matching the names of vscode.DiagnosticSeverity
+// 问题的严重程度，与 vscode.DiagnosticSeverity 的名称一致
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// errorWarnings are the warning kinds the go command refuses to load, the
+// rest only make the module graph differ from what the file suggests
+// errorWarnings 是 go 命令拒绝加载的警告类型，其余类型只会让模块图与文件的字面含义不同
+var errorWarnings = map[string]bool{
+	WarningMalformedGodebug: true,
+	WarningInvalidToolchain: true,
+	WarningToolchainBelowGo: true,
+	WarningInvalidExclude:   true,
+}
+
+// LintMod runs all the checks of a go.mod file at once: parse errors,
+// invalid module paths and versions, duplicate requires, toolchain and go
+// mismatches, replace cycles and a missing go directive.
+// Args: go.mod content.
+// 一次性运行 go.mod 文件的所有检查：解析错误、不合法的模块路径与版本、重复的 require、
+// 工具链与 go 版本不匹配、replace 循环以及缺少 go 指令
+// 参数: go.mod 内容
+func LintMod(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	result, err := json.Marshal(ModLint{Issues: lintMod(args[0].String())})
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ModLint is the result of LintMod
+// ModLint 是 LintMod 的结果
+type ModLint struct {
+	Issues []LintIssue `json:"issues"` // sorted by line
+}
+
+// LintIssue is a problem found in go.mod
+// LintIssue 表示 go.mod 中发现的问题
+type LintIssue struct {
+	Kind     string `json:"kind"`     // a warning kind or one of the Issue kinds
+	Message  string `json:"message"`  // problem description
+	Severity string `json:"severity"` // error or warning
+	Line     int    `json:"line"`     // 1-based line, 0 for the whole file
+}
+
+// lintMod collects the issues of go.mod content. A file modfile rejects
+// only yields its parse errors, since the other checks need the parsed file;
+// the lines parseModInfo recovers from are reported with the warnings.
+// lintMod 收集 go.mod 内容中的问题。被 modfile 拒绝的文件只产生解析错误，因为其他检查需要解析后的文件；
+// parseModInfo 可恢复的行随警告一起报告
+func lintMod(content string) []LintIssue {
+	issues := []LintIssue{}
+	modInfo, err := parseModInfo(content)
+	if err != nil {
+		return parseIssues(err)
+	}
+
+	for _, req := range modInfo.Require {
+		if req.Invalid {
+			issues = append(issues, LintIssue{
+				Kind:     IssueInvalidPath,
+				Message:  fmt.Sprintf("invalid module path %s: %s", req.Path, req.InvalidReason),
+				Severity: SeverityError,
+				Line:     req.Start.Line,
+			})
+		}
+	}
+	for _, w := range modInfo.Warnings {
+		severity := SeverityWarning
+		if errorWarnings[w.Kind] {
+			severity = SeverityError
+		}
+		issues = append(issues, LintIssue{Kind: w.Kind, Message: w.Message, Severity: severity, Line: w.Line})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// parseIssues converts the errors of a rejected file into issues. Errors
+// about the version of a require, replace or exclude line and about the go
+// version are reported as invalid versions, the rest as parse errors.
+// parseIssues 将被拒绝文件的错误转换为问题。require、replace 或 exclude 行的版本错误以及 go 版本错误
+// 报告为无效版本，其余报告为解析错误
+func parseIssues(err error) []LintIssue {
+	var errs modfile.ErrorList
+	if !errors.As(err, &errs) {
+		var single *modfile.Error
+		if !errors.As(err, &single) {
+			return []LintIssue{{Kind: IssueParseError, Message: err.Error(), Severity: SeverityError}}
+		}
+		errs = modfile.ErrorList{*single}
+	}
+
+	issues := []LintIssue{}
+	for _, e := range errs {
+		kind, message := IssueParseError, e.Err.Error()
+		var inner *modfile.Error
+		if errors.As(e.Err, &inner) {
+			message = inner.Err.Error()
+			if inner.Verb != "" && strings.Contains(message, "version") {
+				kind = IssueInvalidVersion
+			}
+		}
+		if strings.HasPrefix(message, "invalid go version") {
+			kind = IssueInvalidGoVersion
+		}
+		issues = append(issues, LintIssue{Kind: kind, Message: message, Severity: SeverityError, Line: e.Pos.Line})
+	}
+	return issues
+}
@@ -0,0 +1,385 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// workspace type-checks Go packages from source files passed in by the
+// extension. Files are grouped by directory and package clause; import paths
+// are derived from go.mod files included in the input, and imports of
+// packages outside the input resolve to empty placeholder packages.
+// workspace 对扩展传入的源文件进行类型检查。文件按目录和 package 子句分组；
+// 导入路径根据输入中包含的 go.mod 文件推导，输入之外的包解析为空的占位包
+type workspace struct {
+	fset     *token.FileSet
+	modules  map[string]string // module root dir -> module path
+	packages map[string]*wsPackage
+	fakes    map[string]*types.Package
+	foreign  map[string]map[string]bool // import path -> type names referenced from it
+}
+
+// wsPackage is a package in the workspace
+// wsPackage 表示工作空间中的一个包
+type wsPackage struct {
+	dir        string
+	importPath string
+	name       string
+	files      []*ast.File
+	paths      []string // paths of files, parallel to files
+	types      *types.Package
+	info       *types.Info
+	checking   bool
+}
+
+// newWorkspace parses the given files and groups them into packages
+// newWorkspace 解析给定文件并将其分组为包
+func newWorkspace(files []SourceFile) *workspace {
+	ws := &workspace{
+		fset:     token.NewFileSet(),
+		modules:  make(map[string]string),
+		packages: make(map[string]*wsPackage),
+		fakes:    make(map[string]*types.Package),
+		foreign:  make(map[string]map[string]bool),
+	}
+
+	// Collect module roots first so package import paths can be resolved
+	// 先收集模块根目录，以便解析包的导入路径
+	for _, file := range files {
+		if path.Base(slashPath(file.Path)) != "go.mod" {
+			continue
+		}
+		if modulePath := modfile.ModulePath([]byte(file.Content)); modulePath != "" {
+			ws.modules[path.Dir(slashPath(file.Path))] = modulePath
+		}
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Path, ".go") {
+			ws.addFile(file)
+		}
+	}
+	return ws
+}
+
+// addFile parses a Go file and adds it to its package, returning the
+// package or nil when the file has no package clause
+// addFile 解析 Go 文件并将其加入所属的包，返回该包；文件没有 package 子句时返回 nil
+func (ws *workspace) addFile(file SourceFile) *wsPackage {
+	// Keep partially parsed files so that one syntax error does not hide
+	// the rest of the file
+	// 保留部分解析的文件，避免一个语法错误导致整个文件不可用
+	f, _ := parser.ParseFile(ws.fset, file.Path, file.Content, parser.ParseComments)
+	if f == nil || f.Name == nil {
+		return nil
+	}
+
+	dir := path.Dir(slashPath(file.Path))
+	importPath := ws.importPath(dir)
+	if strings.HasSuffix(f.Name.Name, "_test") && strings.HasSuffix(file.Path, "_test.go") {
+		importPath += "_test" // external test package
+	}
+
+	pkg, ok := ws.packages[importPath]
+	if !ok {
+		pkg = &wsPackage{dir: dir, importPath: importPath, name: f.Name.Name}
+		ws.packages[importPath] = pkg
+	}
+	pkg.files = append(pkg.files, f)
+	pkg.paths = append(pkg.paths, file.Path)
+	ws.collectForeignTypes(f)
+	return pkg
+}
+
+// removeFile removes a Go file from its package and returns the package,
+// which is dropped from the workspace once it has no files left
+// removeFile 从所属的包中移除 Go 文件并返回该包，包中没有文件时将其从工作空间删除
+func (ws *workspace) removeFile(filePath string) *wsPackage {
+	pkg, f := ws.file(filePath)
+	if pkg == nil {
+		return nil
+	}
+
+	i := slices.Index(pkg.paths, filePath)
+	pkg.files = slices.Delete(pkg.files, i, i+1)
+	pkg.paths = slices.Delete(pkg.paths, i, i+1)
+	if tokenFile := ws.fset.File(f.Pos()); tokenFile != nil {
+		ws.fset.RemoveFile(tokenFile)
+	}
+	if len(pkg.files) == 0 {
+		delete(ws.packages, pkg.importPath)
+	}
+	return pkg
+}
+
+// importers returns the given packages together with every package that
+// imports them directly or transitively: the packages whose type-checking
+// depends on them
+// importers 返回给定的包以及直接或间接导入它们的所有包，即类型检查依赖于它们的包
+func (ws *workspace) importers(importPaths []string) map[string]bool {
+	reverse := make(map[string][]string)
+	for _, pkg := range ws.packages {
+		for _, f := range pkg.files {
+			for _, spec := range f.Imports {
+				if imp, err := strconv.Unquote(spec.Path.Value); err == nil {
+					reverse[imp] = append(reverse[imp], pkg.importPath)
+				}
+			}
+		}
+	}
+
+	affected := make(map[string]bool)
+	queue := importPaths
+	for len(queue) > 0 {
+		importPath := queue[0]
+		queue = queue[1:]
+		if affected[importPath] {
+			continue
+		}
+		affected[importPath] = true
+		queue = append(queue, reverse[importPath]...)
+	}
+	return affected
+}
+
+// collectForeignTypes records qualified identifiers used in type positions,
+// such as context.Context, so placeholder packages can declare them
+// collectForeignTypes 记录类型位置上使用的限定标识符（如 context.Context），
+// 以便占位包可以声明它们
+func (ws *workspace) collectForeignTypes(f *ast.File) {
+	imports := make(map[string]string) // import name -> import path
+	for _, spec := range f.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := guessPackageName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+
+	var visit func(expr ast.Expr)
+	visit = func(expr ast.Expr) {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := e.X.(*ast.Ident); ok {
+				if importPath, ok := imports[x.Name]; ok {
+					ws.addForeignType(importPath, e.Sel.Name)
+				}
+			}
+		case *ast.StarExpr:
+			visit(e.X)
+		case *ast.ParenExpr:
+			visit(e.X)
+		case *ast.ArrayType:
+			visit(e.Elt)
+		case *ast.MapType:
+			visit(e.Key)
+			visit(e.Value)
+		case *ast.ChanType:
+			visit(e.Value)
+		case *ast.Ellipsis:
+			visit(e.Elt)
+		case *ast.IndexExpr:
+			visit(e.X)
+		case *ast.IndexListExpr:
+			visit(e.X)
+		}
+	}
+
+	// Nested struct, interface and func types are reached through their fields
+	// 嵌套的结构体、接口和函数类型通过其字段访问
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			visit(n.Type)
+		case *ast.ValueSpec:
+			visit(n.Type)
+		case *ast.TypeSpec:
+			visit(n.Type)
+		case *ast.CompositeLit:
+			visit(n.Type)
+		case *ast.TypeAssertExpr:
+			visit(n.Type)
+		}
+		return true
+	})
+}
+
+// addForeignType records a type referenced from an import path. A
+// placeholder package created before is extended so that files added later
+// see the type as well.
+// addForeignType 记录从导入路径引用的类型。已创建的占位包会被扩充，使之后加入的文件也能看到该类型
+func (ws *workspace) addForeignType(importPath, name string) {
+	if ws.foreign[importPath] == nil {
+		ws.foreign[importPath] = make(map[string]bool)
+	}
+	if ws.foreign[importPath][name] {
+		return
+	}
+	ws.foreign[importPath][name] = true
+	if fake, ok := ws.fakes[importPath]; ok {
+		declarePlaceholder(fake, name)
+	}
+}
+
+// importPath derives the import path of a directory from the closest module root
+// importPath 根据最近的模块根目录推导目录的导入路径
+func (ws *workspace) importPath(dir string) string {
+	root, modulePath := "", ""
+	for r, m := range ws.modules {
+		if (dir == r || strings.HasPrefix(dir, r+"/")) && len(r) > len(root) {
+			root, modulePath = r, m
+		}
+	}
+	if modulePath == "" {
+		return dir // no module, use the directory so paths stay unique
+	}
+	return modulePath + strings.TrimPrefix(dir, root)
+}
+
+// sortedPackages returns the workspace packages ordered by import path
+// sortedPackages 返回按导入路径排序的工作空间包
+func (ws *workspace) sortedPackages() []*wsPackage {
+	pkgs := make([]*wsPackage, 0, len(ws.packages))
+	for _, pkg := range ws.packages {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].importPath < pkgs[j].importPath })
+	return pkgs
+}
+
+// check type-checks a package, tolerating type errors
+// check 对包进行类型检查，容忍类型错误
+func (ws *workspace) check(pkg *wsPackage) *types.Package {
+	if pkg.types != nil || pkg.checking {
+		return pkg.types
+	}
+	pkg.checking = true
+	defer func() { pkg.checking = false }()
+
+	pkg.info = &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	conf := types.Config{
+		Importer: importerFunc(ws.importPackage),
+		Error:    func(error) {}, // keep going on errors
+	}
+	pkg.types, _ = conf.Check(pkg.importPath, ws.fset, pkg.files, pkg.info)
+	return pkg.types
+}
+
+// checkAll type-checks every package in the workspace
+// checkAll 对工作空间中的所有包进行类型检查
+func (ws *workspace) checkAll() {
+	for _, pkg := range ws.sortedPackages() {
+		ws.check(pkg)
+	}
+}
+
+// importPackage resolves an import to a workspace package or a placeholder
+// importPackage 将导入解析为工作空间包或占位包
+func (ws *workspace) importPackage(importPath string) (*types.Package, error) {
+	if pkg, ok := ws.packages[importPath]; ok && !pkg.checking {
+		if checked := ws.check(pkg); checked != nil {
+			return checked, nil
+		}
+	}
+
+	// Placeholders are shared so that every importer sees the same package.
+	// Referenced types are declared as empty interfaces so that signatures
+	// using them stay printable and anything can be assigned to them.
+	// 占位包是共享的，所有导入方看到的是同一个包。被引用的类型声明为空接口，
+	// 使用它们的签名仍可打印，且任何值都可以赋给它们
+	fake, ok := ws.fakes[importPath]
+	if !ok {
+		fake = types.NewPackage(importPath, guessPackageName(importPath))
+		for name := range ws.foreign[importPath] {
+			declarePlaceholder(fake, name)
+		}
+		fake.MarkComplete()
+		ws.fakes[importPath] = fake
+	}
+	return fake, nil
+}
+
+// declarePlaceholder declares a type of a placeholder package as an empty interface
+// declarePlaceholder 在占位包中将类型声明为空接口
+func declarePlaceholder(fake *types.Package, name string) {
+	obj := types.NewTypeName(token.NoPos, fake, name, nil)
+	types.NewNamed(obj, types.NewInterfaceType(nil, nil), nil)
+	fake.Scope().Insert(obj)
+}
+
+// file finds the package and syntax tree of a file by path
+// file 根据路径查找文件所属的包和语法树
+func (ws *workspace) file(filePath string) (*wsPackage, *ast.File) {
+	for _, pkg := range ws.packages {
+		for i, p := range pkg.paths {
+			if p == filePath {
+				return pkg, pkg.files[i]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// position returns the file path and 1-based line of a position
+// position 返回位置对应的文件路径和从 1 开始的行号
+func (ws *workspace) position(pos token.Pos) (string, int) {
+	p := ws.fset.Position(pos)
+	return p.Filename, p.Line
+}
+
+// importerFunc adapts a function to types.Importer
+// importerFunc 将函数适配为 types.Importer
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// guessPackageName guesses the package name of an import path the way
+// goimports does: the last element without a major version suffix, a
+// "go-" prefix or a "-go" suffix
+// guessPackageName 按 goimports 的方式猜测导入路径的包名：去掉主版本后缀、
+// "go-" 前缀和 "-go" 后缀的最后一个路径元素
+func guessPackageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2] // example.com/foo/v2
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i] // gopkg.in/yaml.v3
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// slashPath converts Windows separators so paths from any platform group correctly
+// slashPath 转换 Windows 分隔符，使任意平台的路径都能正确分组
+func slashPath(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
@@ -5,37 +5,69 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
 	"syscall/js"
+	"time"
+	"unicode/utf8"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
+// parseModCache holds recent ParseMod results, so that reparsing an
+// unchanged go.mod while the user types elsewhere skips modfile.Parse
+// parseModCache 保存最近的 ParseMod 结果，用户在其他位置输入时重新解析未改变的 go.mod 可跳过 modfile.Parse
+var parseModCache = newResultCache(16)
+
 // ParseMod parses a go.mod file and puts result into global buffer
+// Args: go.mod content, JSON array of category rules (optional).
 // 解析 go.mod 文件并将结果存入全局缓冲区
+// 参数: go.mod 内容、类别规则的 JSON 数组（可选）
 func ParseMod(this js.Value, args []js.Value) any {
-	var content string
 	if len(args) == 0 {
 		return createErrorJSON("no path provided")
 	}
-	content = args[0].String()
 
-	// Check if file is empty
-	// 检查文件是否为空
-	if len(content) == 0 {
-		return createErrorJSON("go.mod file is empty")
+	// Return the previous result when the content and rules are unchanged
+	// 内容与规则均未改变时返回之前的结果
+	rules := ""
+	if len(args) > 1 && args[1].Truthy() {
+		rules = args[1].String()
+	}
+	key := cacheKey(args[0].String(), rules)
+	if result, ok := parseModCache.get(key); ok {
+		return result
 	}
 
-	// Parse go.mod file
-	// 解析 go.mod 文件
-	modFile, err := modfile.Parse("go.mod", []byte(content), nil)
+	result := parseMod(args)
+	parseModCache.put(key, result)
+	return result
+}
+
+// parseMod parses go.mod content and marshals the result, errors included
+// parseMod 解析 go.mod 内容并序列化结果，包括错误
+func parseMod(args []js.Value) string {
+	// Parse go.mod file and create result structure
+	// 解析 go.mod 文件并创建结果结构
+	modInfo, err := parseModInfo(args[0].String())
 	if err != nil {
-		return createErrorJSON(fmt.Sprintf("failed to parse go.mod: %s", err.Error()))
+		return createParseErrorJSON(err)
 	}
 
-	// Create result structure
-	// 创建结果结构
-	modInfo := createModInfo(modFile)
+	// Re-categorize the modules with the rules of the extension settings
+	// 使用扩展设置中的规则重新为模块分类
+	rules, err := parseCategoryRules(args)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+	if rules != nil {
+		categorizeMods(modInfo, rules)
+	}
 
 	// Marshal result to JSON
 	// 将结果序列化为 JSON
@@ -47,6 +79,123 @@ func ParseMod(this js.Value, args []js.Value) any {
 	return string(result)
 }
 
+// parseModContent parses go.mod content, rejecting empty input
+// parseModContent 解析 go.mod 内容，拒绝空输入
+func parseModContent(content string) (*modfile.File, error) {
+	// Check if file is empty
+	// 检查文件是否为空
+	if len(content) == 0 {
+		return nil, errors.New("go.mod file is empty")
+	}
+
+	modFile, err := modfile.Parse("go.mod", []byte(content), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+	return modFile, nil
+}
+
+// recoverableErrors maps the modfile.Parse errors of lines that do not
+// affect the module graph to the warning kind reported for them. modfile
+// does not set Error.Verb for these lines, so errors are matched by message.
+// recoverableErrors 将不影响模块图的行的 modfile.Parse 错误映射为报告的警告类型。
+// modfile 不会为这些行设置 Error.Verb，因此按错误信息匹配
+var recoverableErrors = map[string]string{
+	"usage: godebug":            WarningMalformedGodebug,
+	"invalid toolchain version": WarningInvalidToolchain,
+}
+
+// parseModInfo parses go.mod content into a ModFile. Malformed godebug and
+// toolchain lines and excludes with invalid versions fail modfile.Parse
+// although they do not affect the module graph, so they are blanked out and
+// reported as warnings instead; any other error fails the whole file.
+// parseModInfo 将 go.mod 内容解析为 ModFile。格式错误的 godebug 与 toolchain 行以及版本无效的 exclude
+// 虽然不影响模块图，但会导致 modfile.Parse 失败，因此将其清空并报告为警告；其他错误仍使整个文件解析失败
+func parseModInfo(content string) (*ModFile, error) {
+	modFile, err := parseModContent(content)
+	if err == nil {
+		return createModInfo(modFile), nil
+	}
+
+	var errs modfile.ErrorList
+	if !errors.As(err, &errs) {
+		return nil, err
+	}
+	lines := strings.Split(content, "\n")
+	var warnings []Warning
+	toolchainLine := 0
+	for _, e := range errs {
+		kind := recoverableKind(e)
+		if kind == "" || e.Pos.Line < 1 || e.Pos.Line > len(lines) {
+			return nil, err
+		}
+		text := strings.TrimSpace(lines[e.Pos.Line-1])
+		message := fmt.Sprintf("malformed godebug line: %s", text)
+		switch kind {
+		case WarningInvalidToolchain:
+			toolchainLine = e.Pos.Line
+			message = fmt.Sprintf("invalid toolchain name: %s", strings.TrimSpace(strings.TrimPrefix(text, "toolchain")))
+		case WarningInvalidExclude:
+			message = invalidExcludeMessage(excludeErrorPath(e), text)
+		}
+		warnings = append(warnings, Warning{Kind: kind, Message: message, Line: e.Pos.Line})
+		lines[e.Pos.Line-1] = ""
+	}
+
+	// Blanking keeps the line numbers of the remaining entries
+	// 清空行可保持其余条目的行号不变
+	original := strings.Split(content, "\n")
+	modFile, err = parseModContent(strings.Join(lines, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	modInfo := createModInfo(modFile)
+	modInfo.Warnings = append(warnings, modInfo.Warnings...)
+
+	// Keep the invalid toolchain name so it can still be shown
+	// 保留无效的工具链名称以便仍可显示
+	if toolchainLine > 0 {
+		line := original[toolchainLine-1]
+		modInfo.Toolchain = strings.TrimSpace(strings.TrimSpace(line)[len("toolchain"):])
+		indent := utf8.RuneCountInString(line) - utf8.RuneCountInString(strings.TrimLeft(line, " \t"))
+		modInfo.ToolchainStart = Position{Line: toolchainLine, Column: indent + 1}
+		modInfo.ToolchainEnd = Position{Line: toolchainLine, Column: utf8.RuneCountInString(strings.TrimRight(line, " \t\r")) + 1}
+	}
+	return modInfo, nil
+}
+
+// recoverableKind returns the warning kind of a recoverable parse error,
+// or "" when the error must fail the file
+// recoverableKind 返回可恢复解析错误的警告类型，错误必须使文件解析失败时返回 ""
+func recoverableKind(e modfile.Error) string {
+	if e.Err == nil {
+		return ""
+	}
+	// An exclude with an invalid version excludes nothing. The verb is set
+	// on the wrapped error of the line
+	// 版本无效的 exclude 不会排除任何版本。动词设置在该行被包装的错误上
+	if excludeErrorPath(e) != "" {
+		return WarningInvalidExclude
+	}
+	for prefix, kind := range recoverableErrors {
+		if strings.HasPrefix(e.Err.Error(), prefix) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// excludeErrorPath returns the module path of an exclude line error, or ""
+// for errors of other lines
+// excludeErrorPath 返回 exclude 行错误的模块路径，其他行的错误返回 ""
+func excludeErrorPath(e modfile.Error) string {
+	var inner *modfile.Error
+	if errors.As(e.Err, &inner) && inner.Verb == "exclude" {
+		return inner.ModPath
+	}
+	return ""
+}
+
 // Encapsulate modFile parsing into a separate function to improve readability
 // 将 modFile 解析封装到单独的函数中以提高可读性
 func createModInfo(modFile *modfile.File) *ModFile {
@@ -57,58 +206,459 @@ func createModInfo(modFile *modfile.File) *ModFile {
 	}
 
 	modInfo := &ModFile{
-		Module: modFile.Module.Mod.Path,
+		Module:       modFile.Module.Mod.Path,
+		MajorVersion: majorVersion(modFile.Module.Mod.Path),
 	}
 
 	// Set Go version if available
 	// 设置 Go 版本（如果可用）
 	if modFile.Go != nil {
 		modInfo.Go = modFile.Go.Version
+		modInfo.GoVersionValid = modfile.GoVersionRE.MatchString(modFile.Go.Version)
+		modInfo.GoStart, modInfo.GoEnd = linePosition(modFile.Go.Syntax)
+	} else {
+		// Without a go directive the go command assumes go 1.16, so newer
+		// language features and module graph pruning are off
+		// 没有 go 指令时 go 命令假定为 go 1.16，较新的语言特性与模块图裁剪都不会启用
+		start, _ := linePosition(modFile.Module.Syntax)
+		modInfo.Warnings = append(modInfo.Warnings, Warning{
+			Kind:    WarningMissingGo,
+			Message: "go.mod has no go directive, the go command assumes go 1.16",
+			Line:    start.Line,
+		})
 	}
 
 	// Set toolchain if available
 	// 设置工具链（如果可用）
 	if modFile.Toolchain != nil {
 		modInfo.Toolchain = modFile.Toolchain.Name
+		modInfo.ToolchainVersion = toolchainVersion(modFile.Toolchain.Name)
+		modInfo.ToolchainStart, modInfo.ToolchainEnd = linePosition(modFile.Toolchain.Syntax)
+		if modInfo.ToolchainVersion == "" && modFile.Toolchain.Name != "default" {
+			modInfo.Warnings = append(modInfo.Warnings, Warning{
+				Kind:    WarningInvalidToolchain,
+				Message: fmt.Sprintf("invalid toolchain name: %s", modFile.Toolchain.Name),
+				Line:    modInfo.ToolchainStart.Line,
+			})
+		}
+		// The go command rejects a toolchain older than the go version
+		// go 命令拒绝低于 go 版本的工具链
+		if modInfo.ToolchainVersion != "" && modInfo.GoVersionValid && compareGoVersions(modInfo.ToolchainVersion, modInfo.Go) < 0 {
+			modInfo.Warnings = append(modInfo.Warnings, Warning{
+				Kind:    WarningToolchainBelowGo,
+				Message: fmt.Sprintf("toolchain %s is older than go %s, the toolchain must be at least the go version", modFile.Toolchain.Name, modInfo.Go),
+				Line:    modInfo.ToolchainStart.Line,
+			})
+		}
+	}
+
+	// Process godebug settings
+	// 处理 godebug 设置
+	for _, g := range modFile.Godebug {
+		start, end := linePosition(g.Syntax)
+		modInfo.Godebug = append(modInfo.Godebug, GodebugInfo{Key: g.Key, Value: g.Value, Start: start, End: end})
 	}
 
 	// Process required modules
 	// 处理所需模块
+	requireBlocks := requireBlockIndexes(modFile.Syntax)
+	localReplaced := localReplacedPaths(modFile.Replace)
 	for _, req := range modFile.Require {
+		start, end := linePosition(req.Syntax)
+		pseudoTime, pseudoRev := pseudoVersionInfo(req.Mod.Version)
+		invalidReason := checkModulePath(req.Mod.Path, localReplaced[req.Mod.Path])
 		modInfo.Require = append(modInfo.Require, Mod{
-			Path:     req.Mod.Path,
-			Version:  req.Mod.Version,
-			Indirect: req.Indirect,
+			Path:          req.Mod.Path,
+			Version:       req.Mod.Version,
+			Indirect:      req.Indirect,
+			Block:         requireBlocks[req.Syntax],
+			Comment:       lineComment(req.Syntax),
+			PseudoTime:    pseudoTime,
+			PseudoRev:     pseudoRev,
+			Incompatible:  semver.Build(req.Mod.Version) == "+incompatible",
+			Invalid:       invalidReason != "",
+			InvalidReason: invalidReason,
+			Start:         start,
+			End:           end,
 		})
 	}
 
 	// Process replaced modules
 	// 处理替换的模块
 	for _, rep := range modFile.Replace {
-		modInfo.Replace = append(modInfo.Replace, Mod{
-			Path:    rep.New.Path,
-			Version: rep.New.Version,
-		})
+		modInfo.Replace = append(modInfo.Replace, newReplaceInfo(rep))
 	}
 
 	// Process excluded modules
 	// 处理排除的模块
 	for _, exc := range modFile.Exclude {
+		start, end := linePosition(exc.Syntax)
 		modInfo.Exclude = append(modInfo.Exclude, Mod{
 			Path:    exc.Mod.Path,
 			Version: exc.Mod.Version,
+			Start:   start,
+			End:     end,
+		})
+	}
+
+	// Process retracted versions
+	// 处理撤回的版本
+	for _, ret := range modFile.Retract {
+		start, end := linePosition(ret.Syntax)
+		modInfo.Retract = append(modInfo.Retract, RetractInfo{
+			Low:       ret.Low,
+			High:      ret.High,
+			Rationale: ret.Rationale,
+			Start:     start,
+			End:       end,
 		})
 	}
 
 	// Process tools
 	// 处理工具
 	for _, tool := range modFile.Tool {
-		modInfo.Tool = append(modInfo.Tool, Mod{Path: tool.Path})
+		start, end := linePosition(tool.Syntax)
+		modInfo.Tool = append(modInfo.Tool, ToolInfo{Path: tool.Path, Name: toolName(tool.Path), Line: start.Line, Start: start, End: end})
+	}
+
+	// Process ignored directories, keeping relative paths as written
+	// 处理被忽略的目录，相对路径保持原样
+	for _, ignore := range modFile.Ignore {
+		modInfo.Ignore = append(modInfo.Ignore, ignore.Path)
 	}
 
+	modInfo.BlockComments = requireBlockComments(modFile.Syntax)
+	joinExcludes(modInfo)
+
+	// Collect warnings
+	// 收集警告
+	modInfo.Warnings = append(modInfo.Warnings, checkDuplicateRequires(modFile)...)
+	modInfo.Warnings = append(modInfo.Warnings, checkReplaceCycles(modFile)...)
+	modInfo.Warnings = append(modInfo.Warnings, checkReplaceDowngrades(modFile)...)
+	modInfo.Warnings = append(modInfo.Warnings, checkDirectiveVersions(modFile)...)
+
+	categorizeMods(modInfo, nil)
 	return modInfo
 }
 
+// linePosition returns the start and end positions of a syntax line
+// linePosition 返回语法行的起始和结束位置
+func linePosition(line *modfile.Line) (Position, Position) {
+	if line == nil {
+		return Position{}, Position{}
+	}
+	return Position{Line: line.Start.Line, Column: line.Start.LineRune},
+		Position{Line: line.End.Line, Column: line.End.LineRune}
+}
+
+// toolchainVersion returns the Go version of a toolchain name:
+// go1.22.3 → 1.22.3, where suffixes after "-" or "+" (go1.22.3-custom)
+// are ignored as the go command does. It returns "" for "default" and for
+// names whose version does not match modfile.GoVersionRE.
+// toolchainVersion 返回工具链名称中的 Go 版本：go1.22.3 → 1.22.3，与 go 命令一样忽略 "-" 或 "+"
+// 之后的后缀（go1.22.3-custom）。对 "default" 以及版本不匹配 modfile.GoVersionRE 的名称返回 ""
+func toolchainVersion(name string) string {
+	version, ok := strings.CutPrefix(name, "go")
+	if !ok {
+		return ""
+	}
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if !modfile.GoVersionRE.MatchString(version) {
+		return ""
+	}
+	return version
+}
+
+// majorVersion returns the major version carried by a module path suffix:
+// 3 for example.com/m/v3 and gopkg.in/yaml.v3, 1 when there is no suffix.
+// Paths whose last element only looks like a version (/v1, /v02, /v2.1)
+// carry no valid suffix and are reported as 1.
+// majorVersion 返回模块路径后缀中的主版本号：example.com/m/v3 与 gopkg.in/yaml.v3 为 3，
+// 没有后缀时为 1。最后一个元素只是形似版本（/v1、/v02、/v2.1）的路径没有合法后缀，报告为 1
+func majorVersion(path string) int {
+	_, pathMajor, ok := module.SplitPathVersion(path)
+	if !ok || pathMajor == "" {
+		return 1
+	}
+
+	// PathMajorPrefix drops the gopkg.in "-unstable" suffix: .v2-unstable → v2
+	// PathMajorPrefix 去掉 gopkg.in 的 "-unstable" 后缀：.v2-unstable → v2
+	n, err := strconv.Atoi(strings.TrimPrefix(module.PathMajorPrefix(pathMajor), "v"))
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// pseudoVersionInfo returns the commit time (RFC 3339, UTC) and revision
+// encoded in a pseudo-version such as v0.0.0-20230101120000-abcdef123456.
+// Both are empty for other versions.
+// pseudoVersionInfo 返回伪版本（如 v0.0.0-20230101120000-abcdef123456）中编码的
+// 提交时间（RFC 3339，UTC）和修订版本，其他版本均返回空
+func pseudoVersionInfo(version string) (string, string) {
+	if !module.IsPseudoVersion(version) {
+		return "", ""
+	}
+
+	t, err := module.PseudoVersionTime(version)
+	if err != nil {
+		return "", ""
+	}
+	rev, err := module.PseudoVersionRev(version)
+	if err != nil {
+		return "", ""
+	}
+	return t.UTC().Format(time.RFC3339), rev
+}
+
+// Replace target kinds
+// replace 目标类型
+const (
+	ReplaceKindLocal  = "local"  // ../foo, ./foo or an absolute path
+	ReplaceKindModule = "module" // module path with a version
+)
+
+// newReplaceInfo converts a replace directive into ReplaceInfo
+// newReplaceInfo 将 replace 指令转换为 ReplaceInfo
+func newReplaceInfo(rep *modfile.Replace) ReplaceInfo {
+	start, end := linePosition(rep.Syntax)
+	kind := replaceKind(rep)
+
+	// Local directories are not module paths; only module targets are checked
+	// 本地目录不是模块路径，只检查模块类型的替换目标
+	invalidReason := checkModulePath(rep.Old.Path, kind == ReplaceKindLocal)
+	if invalidReason == "" && kind == ReplaceKindModule {
+		invalidReason = checkModulePath(rep.New.Path, false)
+	}
+
+	return ReplaceInfo{
+		OldPath:       rep.Old.Path,
+		OldVersion:    rep.Old.Version,
+		NewPath:       rep.New.Path,
+		NewVersion:    rep.New.Version,
+		Kind:          kind,
+		Invalid:       invalidReason != "",
+		InvalidReason: invalidReason,
+		Start:         start,
+		End:           end,
+	}
+}
+
+// checkModulePath validates a module path and returns the reason it is
+// invalid, or "" for a valid path. Paths replaced by a local directory never
+// reach a module proxy, so they only need to be valid import paths: this
+// keeps dot-less paths such as "mymod" => ../mymod from being flagged.
+// checkModulePath 校验模块路径，返回不合法的原因，合法时返回 ""。
+// 被本地目录替换的路径不会经过模块代理，只需是合法的导入路径，
+// 因此 "mymod" => ../mymod 这类首元素不含点的路径不会被标记
+func checkModulePath(path string, localReplaced bool) string {
+	var err error
+	if localReplaced {
+		err = module.CheckImportPath(path)
+	} else {
+		err = module.CheckPath(path)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// joinExcludes groups the excludes by module path and attaches the versions
+// to every require of the path; paths that are not required go to ExcludeOnly
+// joinExcludes 按模块路径分组 exclude，并将版本附加到该路径的每个 require 上；未被 require 的路径放入 ExcludeOnly
+func joinExcludes(modInfo *ModFile) {
+	excluded := map[string][]string{}
+	var paths []string
+	for _, exc := range modInfo.Exclude {
+		if _, ok := excluded[exc.Path]; !ok {
+			paths = append(paths, exc.Path)
+		}
+		excluded[exc.Path] = append(excluded[exc.Path], exc.Version)
+	}
+
+	required := map[string]bool{}
+	for i := range modInfo.Require {
+		req := &modInfo.Require[i]
+		required[req.Path] = true
+		req.ExcludedVersions = append([]string{}, excluded[req.Path]...)
+	}
+
+	modInfo.ExcludeOnly = []ExcludeGroup{}
+	for _, p := range paths {
+		if !required[p] {
+			modInfo.ExcludeOnly = append(modInfo.ExcludeOnly, ExcludeGroup{Path: p, Versions: excluded[p]})
+		}
+	}
+}
+
+// toolName returns the command name of a tool package the way the go command
+// derives executable names: the last path element, or the one before it when
+// the last is a major version suffix, so example.com/mycmd/v2 is mycmd
+// toolName 按 go 命令推导可执行文件名的方式返回工具包的命令名称：取最后一个路径元素，
+// 最后一个元素是主版本后缀时取前一个，因此 example.com/mycmd/v2 为 mycmd
+func toolName(pkgPath string) string {
+	dir, elem := path.Split(strings.TrimSuffix(pkgPath, "/"))
+	if dir != "" && isVersionElement(elem) {
+		_, elem = path.Split(strings.TrimSuffix(dir, "/"))
+	}
+	return elem
+}
+
+// isVersionElement reports whether a path element is a major version suffix
+// v2 or above; v0 and v1 never appear in import paths
+// isVersionElement 判断路径元素是否为 v2 及以上的主版本后缀；v0 与 v1 不会出现在导入路径中
+func isVersionElement(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' || elem[1] == '0' || elem == "v1" {
+		return false
+	}
+	for _, r := range elem[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// localReplacedPaths returns the module paths replaced by a local directory
+// localReplacedPaths 返回被本地目录替换的模块路径
+func localReplacedPaths(replaces []*modfile.Replace) map[string]bool {
+	paths := make(map[string]bool)
+	for _, rep := range replaces {
+		if replaceKind(rep) == ReplaceKindLocal {
+			paths[rep.Old.Path] = true
+		}
+	}
+	return paths
+}
+
+// replaceKind classifies the replacement target of a replace directive
+// replaceKind 对 replace 指令的替换目标进行分类
+func replaceKind(rep *modfile.Replace) string {
+	if modfile.IsDirectoryPath(rep.New.Path) {
+		return ReplaceKindLocal
+	}
+	return ReplaceKindModule
+}
+
+// lineComment returns the comments attached to a line: the lines above it
+// followed by the end-of-line comment, without "//" and the indirect marker.
+// lineComment 返回附加在行上的注释：先是上方的注释行，再是行尾注释，
+// 去掉 "//" 和 indirect 标记
+func lineComment(line *modfile.Line) string {
+	if line == nil {
+		return ""
+	}
+
+	var texts []string
+	for _, c := range line.Before {
+		if text := commentText(c.Token); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	for _, c := range line.Suffix {
+		text := commentText(c.Token)
+
+		// Skip the "// indirect" marker maintained by the go command
+		// 跳过 go 命令维护的 "// indirect" 标记
+		if text == "indirect" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(text, "indirect;"); ok {
+			text = strings.TrimSpace(rest)
+		}
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// commentText strips the comment marker and surrounding whitespace
+// commentText 去掉注释标记和首尾空白
+func commentText(token string) string {
+	return strings.TrimSpace(strings.TrimPrefix(token, "//"))
+}
+
+// requireBlockComments collects the comments above each require statement
+// and after the open paren of a block, numbering the blocks as
+// requireBlockIndexes does. The comment of a single-line require describes
+// the module and is reported as Mod.Comment instead.
+// requireBlockComments 收集每个 require 语句上方以及块左括号之后的注释，块的编号与 requireBlockIndexes 一致。
+// 单行 require 的行尾注释描述的是模块，通过 Mod.Comment 报告
+func requireBlockComments(syntax *modfile.FileSyntax) map[int]BlockComment {
+	comments := make(map[int]BlockComment)
+	if syntax == nil {
+		return comments
+	}
+
+	tokens := func(list []modfile.Comment) []string {
+		result := []string{}
+		for _, comment := range list {
+			result = append(result, comment.Token)
+		}
+		return result
+	}
+
+	block := 0
+	for _, stmt := range syntax.Stmt {
+		var comment BlockComment
+		switch stmt := stmt.(type) {
+		case *modfile.Line:
+			if len(stmt.Token) == 0 || stmt.Token[0] != "require" {
+				continue
+			}
+			comment = BlockComment{Line: stmt.Start.Line, Before: tokens(stmt.Before), Suffix: []string{}}
+		case *modfile.LineBlock:
+			if len(stmt.Token) == 0 || stmt.Token[0] != "require" {
+				continue
+			}
+			comment = BlockComment{Line: stmt.Start.Line, Before: tokens(stmt.Before), Suffix: tokens(stmt.LParen.Suffix)}
+		default:
+			continue
+		}
+
+		if len(comment.Before) > 0 || len(comment.Suffix) > 0 {
+			comments[block] = comment
+		}
+		block++
+	}
+	return comments
+}
+
+// requireBlockIndexes maps each require line to the index of the require
+// statement it belongs to, counted in file order. A single-line require is
+// its own block.
+// requireBlockIndexes 将每个 require 行映射到其所属 require 语句的序号（按文件顺序计数），
+// 单行 require 单独算作一个块
+func requireBlockIndexes(syntax *modfile.FileSyntax) map[*modfile.Line]int {
+	indexes := make(map[*modfile.Line]int)
+	if syntax == nil {
+		return indexes
+	}
+
+	block := 0
+	for _, stmt := range syntax.Stmt {
+		switch stmt := stmt.(type) {
+		case *modfile.Line:
+			if len(stmt.Token) > 0 && stmt.Token[0] == "require" {
+				indexes[stmt] = block
+				block++
+			}
+		case *modfile.LineBlock:
+			if len(stmt.Token) > 0 && stmt.Token[0] == "require" {
+				for _, line := range stmt.Line {
+					indexes[line] = block
+				}
+				block++
+			}
+		}
+	}
+	return indexes
+}
+
 // createErrorJSON creates a JSON string containing error information
 // createErrorJSON 创建包含错误信息的 JSON 字符串
 func createErrorJSON(message string) string {
@@ -117,26 +667,234 @@ func createErrorJSON(message string) string {
 	return string(errorJSON)
 }
 
+// ParseError is a go.mod syntax error at a position
+// ParseError 表示 go.mod 中某个位置的语法错误
+type ParseError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"` // rune offset within the line, 0 when unknown
+	Message string `json:"message"`
+}
+
+// createParseErrorJSON creates the error JSON of a failed parse. Errors
+// from modfile carry a position per offending line, which is added as
+// "errors" next to the single-string "error".
+// createParseErrorJSON 创建解析失败时的错误 JSON。modfile 的错误为每个出错行携带位置，
+// 这些位置作为 "errors" 与单个字符串 "error" 一起返回
+func createParseErrorJSON(err error) string {
+	errs := parseErrors(err)
+	if len(errs) == 0 {
+		return createErrorJSON(err.Error())
+	}
+
+	errorJSON, _ := json.Marshal(struct {
+		Error  string       `json:"error"`
+		Errors []ParseError `json:"errors"`
+	}{err.Error(), errs})
+	return string(errorJSON)
+}
+
+// parseErrors returns the positional errors of a modfile error, nil when
+// it has no position
+// parseErrors 返回 modfile 错误中带位置的错误，没有位置时返回 nil
+func parseErrors(err error) []ParseError {
+	var errs modfile.ErrorList
+	if !errors.As(err, &errs) {
+		var single *modfile.Error
+		if !errors.As(err, &single) {
+			return nil
+		}
+		errs = modfile.ErrorList{*single}
+	}
+
+	var result []ParseError
+	for _, e := range errs {
+		if e.Pos.Line == 0 {
+			continue
+		}
+		message := e.Err.Error()
+		// The wrapped error of a directive repeats the position
+		// 指令的包装错误会重复位置信息
+		var inner *modfile.Error
+		if errors.As(e.Err, &inner) {
+			message = inner.Err.Error()
+		}
+		result = append(result, ParseError{Line: e.Pos.Line, Column: e.Pos.LineRune, Message: message})
+	}
+	return result
+}
+
 // Keep these types unchanged
 // 保持这些类型不变
 type Mod struct {
-	Path     string `json:"path"`
-	Version  string `json:"version"`
-	Indirect bool   `json:"indirect"` // has "// indirect" comment
+	Path             string   `json:"path"`
+	Version          string   `json:"version"`
+	Indirect         bool     `json:"indirect"`         // has "// indirect" comment
+	Block            int      `json:"block"`            // index of the require block this entry belongs to
+	Comment          string   `json:"comment"`          // comments attached to the line, without "//"
+	PseudoTime       string   `json:"pseudoTime"`       // commit time of a pseudo-version, RFC 3339
+	PseudoRev        string   `json:"pseudoRev"`        // commit hash prefix of a pseudo-version
+	Incompatible     bool     `json:"incompatible"`     // +incompatible version, pseudo-versions included: the module has no go.mod for its major version
+	Invalid          bool     `json:"invalid"`          // module path fails module.CheckPath
+	InvalidReason    string   `json:"invalidReason"`    // why the module path is invalid
+	Category         string   `json:"category"`         // extended-stdlib, internal or third-party, see CategoryRule
+	ExcludedVersions []string `json:"excludedVersions"` // versions excluded by exclude directives in file order, requires only
+	License          string   `json:"license"`          // license attached by JoinLicenses, requires only
+	Start            Position `json:"start"`            // start of the directive line
+	End              Position `json:"end"`              // end of the directive line
+}
+
+// Position is a 1-based line/column location in go.mod
+// Position 是 go.mod 中从 1 开始的行/列位置
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"` // rune offset within the line
+}
+
+// ReplaceInfo is a replace directive: Old => New
+// ReplaceInfo 表示 replace 指令: Old => New
+type ReplaceInfo struct {
+	OldPath       string   `json:"oldPath"`
+	OldVersion    string   `json:"oldVersion"` // empty for a wildcard replace
+	NewPath       string   `json:"newPath"`
+	NewVersion    string   `json:"newVersion"`    // empty for a local replace
+	Kind          string   `json:"kind"`          // local or module
+	Invalid       bool     `json:"invalid"`       // old or module target path is malformed
+	InvalidReason string   `json:"invalidReason"` // why the path is invalid
+	Start         Position `json:"start"`
+	End           Position `json:"end"`
+}
+
+// ToolInfo is a tool directive and the name of its command
+// ToolInfo 表示 tool 指令及其命令名称
+type ToolInfo struct {
+	Path     string   `json:"path"`     // package path, e.g. google.golang.org/grpc/cmd/protoc-gen-go-grpc
+	Name     string   `json:"name"`     // command name for go tool, e.g. protoc-gen-go-grpc
+	Category string   `json:"category"` // extended-stdlib, internal or third-party, see CategoryRule
+	Line     int      `json:"line"`     // 1-based line of the directive
+	Start    Position `json:"start"`    // start of the directive line
+	End      Position `json:"end"`      // end of the directive line
+}
+
+// GodebugInfo is a godebug key=value setting
+// GodebugInfo 表示 godebug key=value 设置
+type GodebugInfo struct {
+	Key   string   `json:"key"`   // e.g. panicnil
+	Value string   `json:"value"` // e.g. 1
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// RetractInfo is a retracted version range; Low == High for a single version
+// RetractInfo 表示撤回的版本区间；单个版本时 Low == High
+type RetractInfo struct {
+	Low       string   `json:"low"`
+	High      string   `json:"high"`
+	Rationale string   `json:"rationale"` // comment explaining the retraction
+	Start     Position `json:"start"`
+	End       Position `json:"end"`
 }
 
 type ModFile struct {
-	Module    string `json:"module"`    // module github.com/example/project
-	Go        string `json:"go"`        // go 1.21
-	Toolchain string `json:"toolchain"` // toolchain go1.21
-	Require   []Mod  `json:"require"`   // require github.com/example/dependency v1.0.0
-	Replace   []Mod  `json:"replace"`
-	Exclude   []Mod  `json:"exclude"`
-	Tool      []Mod  `json:"tool"` // google.golang.org/grpc/cmd/protoc-gen-go-grpc
+	Module           string         `json:"module"`           // module github.com/example/project
+	MajorVersion     int            `json:"majorVersion"`     // major version from the module path suffix, 1 without one
+	Go               string         `json:"go"`               // go 1.21
+	GoVersionValid   bool           `json:"goVersionValid"`   // go version matches modfile.GoVersionRE
+	GoStart          Position       `json:"goStart"`          // start of the go directive line
+	GoEnd            Position       `json:"goEnd"`            // end of the go directive line
+	Toolchain        string         `json:"toolchain"`        // toolchain go1.21
+	ToolchainVersion string         `json:"toolchainVersion"` // Go version of the toolchain, empty when invalid or default
+	ToolchainStart   Position       `json:"toolchainStart"`   // start of the toolchain directive line
+	ToolchainEnd     Position       `json:"toolchainEnd"`     // end of the toolchain directive line
+	Godebug          []GodebugInfo  `json:"godebug"`          // godebug panicnil=1
+	Require          []Mod          `json:"require"`          // require github.com/example/dependency v1.0.0
+	Replace          []ReplaceInfo  `json:"replace"`
+	Exclude          []Mod          `json:"exclude"`
+	ExcludeOnly      []ExcludeGroup `json:"excludeOnly"` // excluded modules that are not required, in file order
+	Tool             []ToolInfo     `json:"tool"`        // google.golang.org/grpc/cmd/protoc-gen-go-grpc
+	Ignore           []string       `json:"ignore"`      // ignore ./node_modules, paths as written
+	Retract          []RetractInfo  `json:"retract"`     // retract [v1.0.0, v1.0.5]
+	Warnings         []Warning      `json:"warnings"`    // problems that do not prevent parsing
+	// Comments of require blocks keyed by Mod.Block, only blocks with comments
+	// require 块的注释，以 Mod.Block 为键，只包含带注释的块
+	BlockComments map[int]BlockComment `json:"blockComments"`
+}
+
+// ExcludeGroup is the excluded versions of a module that is not required
+// ExcludeGroup 表示未被 require 的模块被排除的版本
+type ExcludeGroup struct {
+	Path     string   `json:"path"`
+	Versions []string `json:"versions"`
+}
+
+// BlockComment holds the comments of a require block as written, // included
+// BlockComment 保存 require 块的注释原文，包括 //
+type BlockComment struct {
+	Line   int      `json:"line"`   // 1-based line of the require keyword
+	Before []string `json:"before"` // comment lines above the block
+	Suffix []string `json:"suffix"` // comment after "require (", empty for single-line requires
 }
 
 func main() {
 	done := make(chan int, 0)
 	js.Global().Set("ParseModFunc", js.FuncOf(ParseMod))
+	js.Global().Set("ParseModBatchFunc", js.FuncOf(ParseModBatch))
+	js.Global().Set("ParseWorkFunc", js.FuncOf(ParseWork))
+	js.Global().Set("FindWorkspaceCyclesFunc", js.FuncOf(FindWorkspaceCycles))
+	js.Global().Set("SplitWorkspaceFunc", js.FuncOf(SplitWorkspace))
+	js.Global().Set("ParseVendorModulesFunc", js.FuncOf(ParseVendorModules))
+	js.Global().Set("LintModFunc", js.FuncOf(LintMod))
+	js.Global().Set("FormatModFunc", js.FuncOf(FormatMod))
+	js.Global().Set("AddRequireFunc", js.FuncOf(AddRequire))
+	js.Global().Set("DropRequireFunc", js.FuncOf(DropRequire))
+	js.Global().Set("AddExcludeFunc", js.FuncOf(AddExclude))
+	js.Global().Set("DropExcludeFunc", js.FuncOf(DropExclude))
+	js.Global().Set("PromoteIndirectFunc", js.FuncOf(PromoteIndirect))
+	js.Global().Set("DemoteDirectFunc", js.FuncOf(DemoteDirect))
+	js.Global().Set("RenameModuleFunc", js.FuncOf(RenameModule))
+	js.Global().Set("ResolveImportPathFunc", js.FuncOf(ResolveImportPath))
+	js.Global().Set("TidyFormatFunc", js.FuncOf(TidyFormat))
+	js.Global().Set("NormalizeReplacePathsFunc", js.FuncOf(NormalizeReplacePaths))
+	js.Global().Set("RenderModMarkdownFunc", js.FuncOf(RenderModMarkdown))
+	js.Global().Set("SetGoVersionFunc", js.FuncOf(SetGoVersion))
+	js.Global().Set("CheckGoVersionFunc", js.FuncOf(CheckGoVersion))
+	js.Global().Set("MinGoVersionFunc", js.FuncOf(MinGoVersion))
+	js.Global().Set("CompareVersionsFunc", js.FuncOf(CompareVersions))
+	js.Global().Set("FilterExcludedVersionsFunc", js.FuncOf(FilterExcludedVersions))
+	js.Global().Set("JoinLicensesFunc", js.FuncOf(JoinLicenses))
+	js.Global().Set("SortModsFunc", js.FuncOf(SortMods))
+	js.Global().Set("DiffModFunc", js.FuncOf(DiffMod))
+	js.Global().Set("MergeModFunc", js.FuncOf(MergeMod))
+	js.Global().Set("CheckSumFunc", js.FuncOf(CheckSum))
+	js.Global().Set("CheckIndirectImportsFunc", js.FuncOf(CheckIndirectImports))
+	js.Global().Set("FindUnusedRequiresFunc", js.FuncOf(FindUnusedRequires))
+	js.Global().Set("ClassifyRequireUsageFunc", js.FuncOf(ClassifyRequireUsage))
+	js.Global().Set("DependencyTreeFunc", js.FuncOf(DependencyTree))
+	js.Global().Set("ExplainRequireFunc", js.FuncOf(ExplainRequire))
+	js.Global().Set("FindImplementationsFunc", js.FuncOf(FindImplementations))
+	js.Global().Set("UpdateImplementationsFunc", js.FuncOf(UpdateImplementations))
+	js.Global().Set("ImplementationMatrixFunc", js.FuncOf(ImplementationMatrix))
+	js.Global().Set("UnimplementedInterfacesFunc", js.FuncOf(UnimplementedInterfaces))
+	js.Global().Set("CheckAssertionsFunc", js.FuncOf(CheckAssertions))
+	js.Global().Set("FindShadowedMethodsFunc", js.FuncOf(FindShadowedMethods))
+	js.Global().Set("CheckNilInterfacesFunc", js.FuncOf(CheckNilInterfaces))
+	js.Global().Set("GenerateStubsFunc", js.FuncOf(GenerateStubs))
+	js.Global().Set("GenerateMockFunc", js.FuncOf(GenerateMock))
+	js.Global().Set("ExtractInterfaceFunc", js.FuncOf(ExtractInterface))
+	js.Global().Set("InterfaceMethodSetFunc", js.FuncOf(InterfaceMethodSet))
+	js.Global().Set("CompareInterfacesFunc", js.FuncOf(CompareInterfaces))
+	js.Global().Set("FindInterfaceCallsFunc", js.FuncOf(FindInterfaceCalls))
+	js.Global().Set("DiffInterfacesFunc", js.FuncOf(DiffInterfaces))
+	js.Global().Set("FindTestsFunc", js.FuncOf(FindTests))
+	js.Global().Set("CheckTestPlacementFunc", js.FuncOf(CheckTestPlacement))
+	js.Global().Set("GenerateStructTagsFunc", js.FuncOf(GenerateStructTags))
+	js.Global().Set("AlignStructFieldsFunc", js.FuncOf(AlignStructFields))
+	js.Global().Set("ParseBuildConstraintsFunc", js.FuncOf(ParseBuildConstraints))
+	js.Global().Set("ConvertBuildConstraintsFunc", js.FuncOf(ConvertBuildConstraints))
+	js.Global().Set("PackageBuildSetFunc", js.FuncOf(PackageBuildSet))
+	js.Global().Set("FileOutlineFunc", js.FuncOf(FileOutline))
+	js.Global().Set("PackageNameFunc", js.FuncOf(PackageName))
+	js.Global().Set("ClassifyCommentsFunc", js.FuncOf(ClassifyComments))
+	js.Global().Set("ScanTodosFunc", js.FuncOf(ScanTodos))
+	js.Global().Set("DocSynopsisFunc", js.FuncOf(DocSynopsis))
 	<-done
 }
@@ -0,0 +1,102 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+// RenderModMarkdown renders an overview of the module dependencies as
+// markdown, for a DEPENDENCIES.md file.
+// Args: go.mod content.
+// 将模块依赖概览渲染为 markdown，用于 DEPENDENCIES.md 文件
+// 参数: go.mod 内容
+func RenderModMarkdown(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	modInfo, err := parseModInfo(args[0].String())
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	return renderModMarkdown(modInfo)
+}
+
+// renderModMarkdown emits the module path as the heading, then a table per
+// group. Sections without entries are left out.
+// renderModMarkdown 以模块路径作为标题，每组依赖输出一个表格，省略没有条目的部分
+func renderModMarkdown(modInfo *ModFile) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", modInfo.Module)
+	if modInfo.Go != "" {
+		fmt.Fprintf(&b, "Go %s", modInfo.Go)
+		if modInfo.Toolchain != "" {
+			fmt.Fprintf(&b, " (toolchain %s)", modInfo.Toolchain)
+		}
+		b.WriteString("\n\n")
+	}
+
+	var direct, indirect [][]string
+	for _, req := range modInfo.Require {
+		row := []string{markdownCode(req.Path), req.Version}
+		if req.Indirect {
+			indirect = append(indirect, row)
+		} else {
+			direct = append(direct, row)
+		}
+	}
+	writeMarkdownTable(&b, "Direct dependencies", []string{"Module", "Version"}, direct)
+	writeMarkdownTable(&b, "Indirect dependencies", []string{"Module", "Version"}, indirect)
+
+	var replaces [][]string
+	for _, rep := range modInfo.Replace {
+		oldVersion := rep.OldVersion
+		if oldVersion == "" {
+			oldVersion = "*"
+		}
+		replaces = append(replaces, []string{markdownCode(rep.OldPath), oldVersion, markdownCode(rep.NewPath), rep.NewVersion})
+	}
+	writeMarkdownTable(&b, "Replace", []string{"Module", "Version", "Replacement", "Version"}, replaces)
+
+	var excludes [][]string
+	for _, exc := range modInfo.Exclude {
+		excludes = append(excludes, []string{markdownCode(exc.Path), exc.Version})
+	}
+	writeMarkdownTable(&b, "Exclude", []string{"Module", "Version"}, excludes)
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// writeMarkdownTable writes a section with a table, nothing when rows is empty
+// writeMarkdownTable 写入带表格的部分，rows 为空时不写入
+func writeMarkdownTable(b *strings.Builder, title string, header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "## %s\n\n", title)
+	fmt.Fprintf(b, "| %s |\n", strings.Join(header, " | "))
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	}
+	b.WriteString("\n")
+}
+
+// markdownCode formats a path as inline code, empty paths stay empty
+// markdownCode 将路径格式化为行内代码，空路径保持为空
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + s + "`"
+}
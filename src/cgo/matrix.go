@@ -0,0 +1,146 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"sort"
+	"syscall/js"
+)
+
+// ImplementationMatrix reports which interfaces each concrete type of a
+// package satisfies.
+// Args: workspace files (JSON array of {path, content}), a file of the package.
+// 报告包中每个具体类型满足哪些接口
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）、包中的一个文件
+func ImplementationMatrix(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("files and package file are required")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+
+	ws := newWorkspace(files)
+	pkg, _ := ws.file(args[1].String())
+	if pkg == nil {
+		return createErrorJSON("file not found: " + args[1].String())
+	}
+	ws.checkAll()
+
+	result, err := json.Marshal(implementationMatrix(ws, pkg))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// Matrix is the result of ImplementationMatrix
+// Matrix 是 ImplementationMatrix 的结果
+type Matrix struct {
+	Package    string            `json:"package"`
+	Interfaces []MatrixInterface `json:"interfaces"` // columns
+	Types      []MatrixType      `json:"types"`      // rows
+}
+
+// MatrixInterface is an interface column of the matrix
+// MatrixInterface 表示矩阵中的接口列
+type MatrixInterface struct {
+	Name    string `json:"name"` // qualified relative to the package, e.g. greet.Greeter
+	Package string `json:"package"`
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+}
+
+// MatrixType is a concrete type row of the matrix. Value and pointer types
+// get a row each, the pointer row only when it satisfies more interfaces.
+// MatrixType 表示矩阵中的具体类型行。值类型与指针类型各占一行，
+// 指针类型只有在满足更多接口时才单独成行
+type MatrixType struct {
+	Name       string   `json:"name"` // prefixed with * for the pointer type
+	Pointer    bool     `json:"pointer"`
+	Path       string   `json:"path"`
+	Line       int      `json:"line"`
+	Implements []string `json:"implements"` // names of the satisfied interfaces
+}
+
+// implementationMatrix checks the concrete types of a package against its
+// own interfaces and those of the workspace packages it imports, directly or
+// transitively, since those are the interfaces its types can be used as.
+// implementationMatrix 将包中的具体类型与包自身以及直接或间接导入的工作空间包中的接口进行比较，
+// 这些正是其类型可以用作的接口
+func implementationMatrix(ws *workspace, pkg *wsPackage) Matrix {
+	matrix := Matrix{Package: pkg.importPath, Interfaces: []MatrixInterface{}, Types: []MatrixType{}}
+	if pkg.types == nil {
+		return matrix
+	}
+	qf := packageQualifier(pkg.types)
+
+	var interfaces []*types.TypeName
+	for _, dep := range ws.importedPackages(pkg) {
+		ifaces, _ := packageTypes(dep)
+		interfaces = append(interfaces, ifaces...)
+	}
+	for _, obj := range interfaces {
+		path, line := ws.position(obj.Pos())
+		matrix.Interfaces = append(matrix.Interfaces, MatrixInterface{
+			Name:    types.TypeString(obj.Type(), qf),
+			Package: obj.Pkg().Path(),
+			Path:    path,
+			Line:    line,
+		})
+	}
+
+	_, concretes := packageTypes(pkg)
+	for _, obj := range concretes {
+		path, line := ws.position(obj.Pos())
+		value := MatrixType{Name: obj.Name(), Path: path, Line: line, Implements: []string{}}
+		pointer := MatrixType{Name: "*" + obj.Name(), Pointer: true, Path: path, Line: line, Implements: []string{}}
+		for i, iface := range interfaces {
+			underlying := iface.Type().Underlying().(*types.Interface)
+			if types.Implements(obj.Type(), underlying) {
+				value.Implements = append(value.Implements, matrix.Interfaces[i].Name)
+			}
+			if types.Implements(types.NewPointer(obj.Type()), underlying) {
+				pointer.Implements = append(pointer.Implements, matrix.Interfaces[i].Name)
+			}
+		}
+
+		matrix.Types = append(matrix.Types, value)
+		if len(pointer.Implements) > len(value.Implements) {
+			matrix.Types = append(matrix.Types, pointer)
+		}
+	}
+	return matrix
+}
+
+// importedPackages returns a package followed by the workspace packages it
+// imports directly or transitively, ordered by import path
+// importedPackages 返回包本身，以及它直接或间接导入的工作空间包（按导入路径排序）
+func (ws *workspace) importedPackages(pkg *wsPackage) []*wsPackage {
+	seen := map[string]bool{pkg.importPath: true}
+	var deps []*wsPackage
+	queue := []*types.Package{pkg.types}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, imp := range current.Imports() {
+			dep, ok := ws.packages[imp.Path()]
+			if seen[imp.Path()] || !ok || dep.types != imp {
+				continue
+			}
+			seen[imp.Path()] = true
+			deps = append(deps, dep)
+			queue = append(queue, imp)
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool { return deps[i].importPath < deps[j].importPath })
+	return append([]*wsPackage{pkg}, deps...)
+}
@@ -0,0 +1,230 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// MergeMod merges the requirements of a second go.mod into a first one, for
+// example when consolidating two modules into a monorepo. The module path,
+// retractions and other directives of the first file are kept.
+// Args: first go.mod content, second go.mod content.
+// 将第二个 go.mod 的依赖合并到第一个 go.mod 中，例如将两个模块合并到 monorepo 时。
+// 保留第一个文件的模块路径、retract 以及其他指令
+// 参数: 第一个 go.mod 内容、第二个 go.mod 内容
+func MergeMod(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("two go.mod contents are required")
+	}
+
+	first, err := parseModContent(args[0].String())
+	if err != nil {
+		return createErrorJSON("first: " + err.Error())
+	}
+	second, err := parseModContent(args[1].String())
+	if err != nil {
+		return createErrorJSON("second: " + err.Error())
+	}
+
+	resolutions, err := mergeMod(first, second)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(ModMerge{Content: formatModFile(first), Resolutions: resolutions})
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ModMerge is the result of MergeMod
+// ModMerge 是 MergeMod 的结果
+type ModMerge struct {
+	Content     string       `json:"content"`     // formatted merged go.mod
+	Resolutions []Resolution `json:"resolutions"` // values both files set differently
+}
+
+// Resolution is a value both go.mod files set differently and the one merged
+// Resolution 表示两个 go.mod 文件设置不同的值以及合并后采用的值
+type Resolution struct {
+	Kind   string `json:"kind"`   // require, replace, go or toolchain
+	Path   string `json:"path"`   // module path, or "path version" of a replace, empty for go and toolchain
+	First  string `json:"first"`  // value in the first file
+	Second string `json:"second"` // value in the second file
+	Chosen string `json:"chosen"` // value in the merged file
+}
+
+// mergeMod merges second into first. Conflicting requires take the higher
+// semantic version and stay direct if either file requires them directly;
+// new direct requires are appended to the first require block without
+// indirect requires and new indirect ones to the last block, TidyFormat
+// sorts them.
+// Replaces and excludes are unioned; a replace of the same module version
+// pointing elsewhere keeps the replacement of the first file, and local
+// replacement paths are copied as they are, relative to the second module.
+// The go and toolchain directives take the higher version, and a toolchain
+// no newer than the merged go version is dropped as the go command does.
+// mergeMod 将 second 合并到 first。冲突的 require 取较高的语义化版本，任一文件直接依赖时保持直接依赖；
+// 新的直接依赖追加到第一个不含间接依赖的 require 块，新的间接依赖追加到最后一个块，可用 TidyFormat 排序。replace 与 exclude 取并集；
+// 同一模块版本替换到不同位置时保留第一个文件的替换，本地替换路径原样复制，仍相对于第二个模块。
+// go 与 toolchain 指令取较高版本，与 go 命令一样删除不高于合并后 go 版本的 toolchain
+func mergeMod(first, second *modfile.File) ([]Resolution, error) {
+	resolutions := []Resolution{}
+
+	firstReqs := requireByPath(first)
+	for _, req := range second.Require {
+		path, version := req.Mod.Path, req.Mod.Version
+		existing, ok := firstReqs[path]
+		if !ok {
+			if req.Indirect {
+				first.AddNewRequire(path, version, true)
+			} else {
+				addDirectRequire(first, path, version)
+			}
+			firstReqs[path] = req
+			continue
+		}
+		if existing.Mod.Version != version {
+			chosen := existing.Mod.Version
+			if semver.Compare(version, chosen) > 0 {
+				chosen = version
+			}
+			resolutions = append(resolutions, Resolution{
+				Kind: "require", Path: path, First: existing.Mod.Version, Second: version, Chosen: chosen,
+			})
+			if err := first.AddRequire(path, chosen); err != nil {
+				return nil, fmt.Errorf("failed to require %s %s: %s", path, chosen, err.Error())
+			}
+		}
+		if existing.Indirect && !req.Indirect {
+			for _, r := range first.Require {
+				if r.Mod.Path == path {
+					clearIndirect(r)
+				}
+			}
+		}
+	}
+
+	firstReplaces := make(map[string]*modfile.Replace)
+	for _, rep := range first.Replace {
+		firstReplaces[rep.Old.String()] = rep
+	}
+	for _, rep := range second.Replace {
+		existing, ok := firstReplaces[rep.Old.String()]
+		if !ok {
+			if err := first.AddReplace(rep.Old.Path, rep.Old.Version, rep.New.Path, rep.New.Version); err != nil {
+				return nil, fmt.Errorf("failed to replace %s: %s", rep.Old.String(), err.Error())
+			}
+			firstReplaces[rep.Old.String()] = rep
+			continue
+		}
+		if existing.New != rep.New {
+			resolutions = append(resolutions, Resolution{
+				Kind: "replace", Path: replaceSource(rep), First: existing.New.String(), Second: rep.New.String(), Chosen: existing.New.String(),
+			})
+		}
+	}
+
+	for _, exc := range second.Exclude {
+		if err := first.AddExclude(exc.Mod.Path, exc.Mod.Version); err != nil {
+			return nil, fmt.Errorf("failed to exclude %s %s: %s", exc.Mod.Path, exc.Mod.Version, err.Error())
+		}
+	}
+
+	if second.Go != nil {
+		switch {
+		case first.Go == nil:
+			if err := first.AddGoStmt(second.Go.Version); err != nil {
+				return nil, fmt.Errorf("failed to set go version: %s", err.Error())
+			}
+		case first.Go.Version != second.Go.Version:
+			chosen := first.Go.Version
+			if compareGoVersions(second.Go.Version, chosen) > 0 {
+				chosen = second.Go.Version
+			}
+			resolutions = append(resolutions, Resolution{Kind: "go", First: first.Go.Version, Second: second.Go.Version, Chosen: chosen})
+			if err := first.AddGoStmt(chosen); err != nil {
+				return nil, fmt.Errorf("failed to set go version: %s", err.Error())
+			}
+		}
+	}
+
+	// "default" and invalid names have no version and never win over a version
+	// "default" 和无效名称没有版本，不会取代有版本的 toolchain
+	if second.Toolchain != nil {
+		name := second.Toolchain.Name
+		switch {
+		case first.Toolchain == nil:
+			if err := first.AddToolchainStmt(name); err != nil {
+				return nil, fmt.Errorf("failed to set toolchain: %s", err.Error())
+			}
+		case first.Toolchain.Name != name:
+			chosen := first.Toolchain.Name
+			if v := toolchainVersion(name); v != "" && (toolchainVersion(chosen) == "" || compareGoVersions(v, toolchainVersion(chosen)) > 0) {
+				chosen = name
+			}
+			resolutions = append(resolutions, Resolution{Kind: "toolchain", First: first.Toolchain.Name, Second: name, Chosen: chosen})
+			if err := first.AddToolchainStmt(chosen); err != nil {
+				return nil, fmt.Errorf("failed to set toolchain: %s", err.Error())
+			}
+		}
+	}
+	if first.Toolchain != nil && first.Go != nil {
+		toolchain := toolchainVersion(first.Toolchain.Name)
+		if toolchain != "" && compareGoVersions(toolchain, first.Go.Version) <= 0 {
+			first.DropToolchainStmt()
+		}
+	}
+
+	return resolutions, nil
+}
+
+// replaceSource prints the replaced side of a replace, e.g. "example.com/a v1.0.0"
+// or "example.com/a" for a replace of every version
+// replaceSource 打印 replace 被替换的一侧，例如 "example.com/a v1.0.0"，替换所有版本时为 "example.com/a"
+func replaceSource(rep *modfile.Replace) string {
+	if rep.Old.Version == "" {
+		return rep.Old.Path
+	}
+	return rep.Old.Path + " " + rep.Old.Version
+}
+
+// addDirectRequire adds a direct require to the first require block that has
+// no indirect requires. AddNewRequire appends to the last block, which is
+// usually the // indirect one; it is still used when no block qualifies.
+// addDirectRequire 将直接依赖添加到第一个不含间接依赖的 require 块。AddNewRequire 追加到最后一个块，
+// 而它通常是 // indirect 块；没有符合条件的块时仍使用 AddNewRequire
+func addDirectRequire(f *modfile.File, path, version string) {
+	indirect := make(map[*modfile.Line]bool)
+	for _, r := range f.Require {
+		if r.Indirect {
+			indirect[r.Syntax] = true
+		}
+	}
+
+	for _, stmt := range f.Syntax.Stmt {
+		block, ok := stmt.(*modfile.LineBlock)
+		if !ok || len(block.Token) != 1 || block.Token[0] != "require" {
+			continue
+		}
+		if slices.ContainsFunc(block.Line, func(line *modfile.Line) bool { return indirect[line] }) {
+			continue
+		}
+		line := &modfile.Line{Token: []string{modfile.AutoQuote(path), version}, InBlock: true}
+		block.Line = append(block.Line, line)
+		f.Require = append(f.Require, &modfile.Require{Mod: module.Version{Path: path, Version: version}, Syntax: line})
+		return
+	}
+	f.AddNewRequire(path, version, false)
+}
@@ -0,0 +1,61 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import "testing"
+
+func TestMergeModDirectRequireGoesToDirectBlock(t *testing.T) {
+	first, err := parseModContent(`module example.com/a
+
+go 1.21
+
+require (
+	example.com/x v1.0.0
+	example.com/w v1.0.0
+)
+
+require (
+	example.com/y v1.0.0 // indirect
+	example.com/v v1.0.0 // indirect
+)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := parseModContent(`module example.com/b
+
+go 1.21
+
+require (
+	example.com/z v1.2.0
+	example.com/u v1.0.0 // indirect
+)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := mergeMod(first, second); err != nil {
+		t.Fatal(err)
+	}
+	want := `module example.com/a
+
+go 1.21
+
+require (
+	example.com/x v1.0.0
+	example.com/w v1.0.0
+	example.com/z v1.2.0
+)
+
+require (
+	example.com/y v1.0.0 // indirect
+	example.com/v v1.0.0 // indirect
+	example.com/u v1.0.0 // indirect
+)
+`
+	if got := formatModFile(first); got != want {
+		t.Errorf("merged go.mod:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+	"syscall/js"
+)
+
+// InterfaceMethodSet expands the method set of an interface, methods
+// promoted from embedded interfaces included.
+// Args: workspace files (JSON array of {path, content}), the file the
+// interface is looked up from, the interface, either "Greeter" or qualified
+// as "example.com/greet.Greeter".
+// 展开接口的方法集，包括从嵌入接口提升的方法
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）、查找接口的起始文件、接口，
+// 可以是 "Greeter" 或限定形式 "example.com/greet.Greeter"
+func InterfaceMethodSet(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("files, file and interface are required")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	filePath, ifaceName := args[1].String(), args[2].String()
+
+	ws := newWorkspace(files)
+	pkg, _ := ws.file(filePath)
+	if pkg == nil {
+		return createErrorJSON("file not found: " + filePath)
+	}
+	ws.checkAll()
+
+	named, ok := lookupInterface(ws, pkg, ifaceName)
+	if !ok {
+		return createErrorJSON("interface not found: " + ifaceName)
+	}
+
+	methodSet, err := interfaceMethodSet(ws, named)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(methodSet)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// MethodSet is the expanded method set of an interface
+// MethodSet 表示展开后的接口方法集
+type MethodSet struct {
+	Interface  string            `json:"interface"`
+	Package    string            `json:"package"`
+	Path       string            `json:"path"`
+	Line       int               `json:"line"`
+	Methods    []InterfaceMethod `json:"methods"`    // sorted by name
+	Unresolved []string          `json:"unresolved"` // embedded interfaces from packages outside the workspace
+}
+
+// InterfaceMethod is a method of an interface method set
+// InterfaceMethod 表示接口方法集中的方法
+type InterfaceMethod struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"` // e.g. Greet(name string) string
+	From      string `json:"from"`      // interface declaring the method, e.g. greet.Namer
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+}
+
+// interfaceMethodSet walks the embedded interfaces itself instead of using
+// the completed interface: go/types keeps one of two conflicting methods
+// after reporting a type error, while a method reached along several
+// embedding paths with identical signatures is the same method. Types are
+// printed relative to the package of the interface.
+// interfaceMethodSet 自行遍历嵌入接口，而不是使用补全后的接口：go/types 在报告类型错误后
+// 只保留两个冲突方法中的一个，而沿多条嵌入路径到达、签名相同的方法是同一个方法。
+// 类型相对于接口所在的包打印
+func interfaceMethodSet(ws *workspace, named *types.Named) (MethodSet, error) {
+	obj := named.Obj()
+	path, line := ws.position(obj.Pos())
+	methodSet := MethodSet{
+		Interface:  obj.Name(),
+		Package:    obj.Pkg().Path(),
+		Path:       path,
+		Line:       line,
+		Methods:    []InterfaceMethod{},
+		Unresolved: []string{},
+	}
+
+	qf := packageQualifier(obj.Pkg())
+	found := make(map[string]int) // method name -> index in methodSet.Methods
+	funcs := make(map[string]*types.Func)
+	visited := make(map[*types.Named]bool)
+
+	var walk func(named *types.Named) error
+	walk = func(named *types.Named) error {
+		if visited[named] {
+			return nil
+		}
+		visited[named] = true
+
+		iface, ok := named.Underlying().(*types.Interface)
+		if !ok {
+			return nil
+		}
+		if pkg := named.Obj().Pkg(); pkg != nil && ws.fakes[pkg.Path()] == pkg {
+			methodSet.Unresolved = append(methodSet.Unresolved, types.TypeString(named, qf))
+			return nil
+		}
+
+		for i := 0; i < iface.NumExplicitMethods(); i++ {
+			fn := iface.ExplicitMethod(i)
+			if idx, ok := found[fn.Name()]; ok {
+				if prev := methodSet.Methods[idx]; !types.Identical(funcs[fn.Name()].Type(), fn.Type()) {
+					return fmt.Errorf("conflicting signatures for method %s: %s (%s) and %s (%s)", fn.Name(),
+						prev.Signature, prev.From, methodSignature(fn, qf), types.TypeString(named, qf))
+				}
+				continue
+			}
+			found[fn.Name()] = len(methodSet.Methods)
+			funcs[fn.Name()] = fn
+
+			path, line := ws.position(fn.Pos())
+			methodSet.Methods = append(methodSet.Methods, InterfaceMethod{
+				Name:      fn.Name(),
+				Signature: methodSignature(fn, qf),
+				From:      types.TypeString(named, qf),
+				Path:      path,
+				Line:      line,
+			})
+		}
+
+		for i := 0; i < iface.NumEmbeddeds(); i++ {
+			if embedded, ok := iface.EmbeddedType(i).(*types.Named); ok {
+				if err := walk(embedded); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(named); err != nil {
+		return MethodSet{}, err
+	}
+	sort.Slice(methodSet.Methods, func(i, j int) bool { return methodSet.Methods[i].Name < methodSet.Methods[j].Name })
+	sort.Strings(methodSet.Unresolved)
+	return methodSet, nil
+}
+
+// methodSignature formats a method as Name(params) results
+// methodSignature 将方法格式化为 Name(params) results
+func methodSignature(fn *types.Func, qf types.Qualifier) string {
+	return fn.Name() + strings.TrimPrefix(types.TypeString(fn.Type(), qf), "func")
+}
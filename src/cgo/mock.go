@@ -0,0 +1,315 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall/js"
+)
+
+// Mock is a generated test double for an interface
+// Mock 表示为接口生成的测试替身
+type Mock struct {
+	Path    string `json:"path"`    // suggested file for the mock, <dir>/mock_<interface>.go
+	Package string `json:"package"` // package clause of the file
+	Name    string `json:"name"`    // mock type name
+	Text    string `json:"text"`    // complete file source, package clause and imports included
+}
+
+// GenerateMock generates a struct with one function field per interface
+// method and methods delegating to them, e.g. SayHelloFunc for SayHello.
+// Args: workspace files (JSON array of {path, content}), the file declaring
+// the interface, the interface ("Greeter" or "example.com/greet.Greeter"),
+// directory of the package receiving the mock, mock type name (optional,
+// Mock + interface name by default).
+// GenerateMock 生成一个结构体，每个接口方法对应一个函数字段，方法委托给这些字段，例如 SayHello 对应 SayHelloFunc。
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）、声明接口的文件、接口（"Greeter" 或 "example.com/greet.Greeter"）、
+// 接收 mock 的包目录、mock 类型名称（可选，默认为 Mock 加接口名）
+func GenerateMock(this js.Value, args []js.Value) any {
+	if len(args) < 4 {
+		return createErrorJSON("files, interface file, interface and package directory are required")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	ifacePath, ifaceName := args[1].String(), args[2].String()
+	dir := strings.TrimSuffix(slashPath(args[3].String()), "/")
+
+	ws := newWorkspace(files)
+	from, _ := ws.file(ifacePath)
+	if from == nil {
+		return createErrorJSON(fmt.Sprintf("file not found: %s", ifacePath))
+	}
+	ws.checkAll()
+
+	iface, ok := lookupInterface(ws, from, ifaceName)
+	if !ok {
+		return createErrorJSON(fmt.Sprintf("interface not found: %s", ifaceName))
+	}
+
+	mockName := "Mock" + iface.Obj().Name()
+	if len(args) > 4 && args[4].Truthy() {
+		mockName = args[4].String()
+	}
+	if !token.IsIdentifier(mockName) {
+		return createErrorJSON(fmt.Sprintf("invalid mock name: %s", mockName))
+	}
+
+	// A directory without Go files gets a new package
+	// 目录中没有 Go 文件时创建新包
+	mock, err := generateMock(ws, iface, ws.dirPackage(dir), dir, mockName)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(mock)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// dirPackage returns the non-test package of a directory, nil without one
+// dirPackage 返回目录中的非测试包，不存在时返回 nil
+func (ws *workspace) dirPackage(dir string) *wsPackage {
+	for _, pkg := range ws.sortedPackages() {
+		if pkg.dir == dir && !strings.HasSuffix(pkg.importPath, "_test") {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// generateMock builds the declarations of the mock as syntax trees and
+// prints them, so the result is formatted Go. Signatures are taken from
+// go/types, which keeps named results and variadic parameters as declared.
+// generateMock 以语法树构建 mock 的声明并打印，因此结果是格式化的 Go 代码。
+// 签名取自 go/types，保留声明时的具名返回值和可变参数
+func generateMock(ws *workspace, iface *types.Named, target *wsPackage, dir, mockName string) (Mock, error) {
+	mock := Mock{
+		Path: path.Join(dir, "mock_"+convertCase(iface.Obj().Name(), TagCaseSnake)+".go"),
+		Name: mockName,
+	}
+
+	var pkg *types.Package
+	if target != nil {
+		mock.Package, pkg = target.name, target.types
+	} else {
+		mock.Package = guessPackageName(ws.importPath(dir))
+	}
+
+	if embeds := outsideEmbeds(ws, iface); len(embeds) > 0 {
+		return mock, incompleteError(embeds)
+	}
+
+	// Unexported methods can only be implemented inside the declaring package
+	// 未导出的方法只能在声明它的包中实现
+	underlying := iface.Underlying().(*types.Interface)
+	local := pkg != nil && pkg == iface.Obj().Pkg()
+	for i := 0; i < underlying.NumMethods(); i++ {
+		if m := underlying.Method(i); !m.Exported() && !local {
+			return mock, fmt.Errorf("unexported method %s can only be mocked in package %s", m.Name(), m.Pkg().Path())
+		}
+	}
+
+	imports := map[string]string{} // import path -> package name
+	qf := func(other *types.Package) string {
+		if other == pkg || (pkg == nil && other.Path() == ws.importPath(dir)) {
+			return ""
+		}
+		imports[other.Path()] = other.Name()
+		return other.Name()
+	}
+	ifaceType := types.TypeString(iface, qf)
+
+	recv := receiverName(mockName)
+	fields := &ast.FieldList{}
+	var methods []ast.Decl
+	for i := 0; i < underlying.NumMethods(); i++ {
+		m := underlying.Method(i)
+		sig := m.Type().(*types.Signature)
+		field := m.Name() + "Func"
+
+		fieldType, err := parser.ParseExpr(types.TypeString(sig, qf))
+		if err != nil {
+			return mock, fmt.Errorf("failed to render %s: %s", m.Name(), err.Error())
+		}
+		fields.List = append(fields.List, &ast.Field{Names: []*ast.Ident{ast.NewIdent(field)}, Type: fieldType})
+
+		decl, err := mockMethod(recv, mockName, field, m.Name(), sig, qf)
+		if err != nil {
+			return mock, err
+		}
+		methods = append(methods, decl)
+	}
+
+	typeDecl := &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{&ast.TypeSpec{
+		Name: ast.NewIdent(mockName),
+		Type: &ast.StructType{Fields: fields},
+	}}}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "package %s\n", mock.Package)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for p := range imports {
+			paths = append(paths, p)
+		}
+		// Standard library packages first, the way goimports groups them
+		// 与 goimports 一样先列出标准库包
+		slices.SortFunc(paths, func(a, b string) int {
+			if isStdImport(a) != isStdImport(b) {
+				if isStdImport(a) {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a, b)
+		})
+		buf.WriteString("\nimport (\n")
+		for i, p := range paths {
+			if i > 0 && isStdImport(p) != isStdImport(paths[i-1]) {
+				buf.WriteString("\n")
+			}
+			if imports[p] != guessPackageName(p) {
+				fmt.Fprintf(&buf, "\t%s %s\n", imports[p], strconv.Quote(p))
+			} else {
+				fmt.Fprintf(&buf, "\t%s\n", strconv.Quote(p))
+			}
+		}
+		buf.WriteString(")\n")
+	}
+
+	fmt.Fprintf(&buf, "\n// %s is a mock of %s. Set the func fields a test needs;\n// calling a method whose field is nil panics.\n", mockName, ifaceType)
+	if err := printDecl(&buf, typeDecl); err != nil {
+		return mock, err
+	}
+	fmt.Fprintf(&buf, "\n\nvar _ %s = (*%s)(nil)\n", ifaceType, mockName)
+	for i, decl := range methods {
+		fmt.Fprintf(&buf, "\n// %s calls %s.\n", underlying.Method(i).Name(), underlying.Method(i).Name()+"Func")
+		if err := printDecl(&buf, decl); err != nil {
+			return mock, err
+		}
+		buf.WriteString("\n")
+	}
+
+	mock.Text = buf.String()
+	return mock, nil
+}
+
+// mockMethod builds a method that panics when its func field is nil and
+// otherwise returns the result of calling it. Parameters without a usable
+// name are named p0, p1, ... so they can be forwarded, and the receiver is
+// renamed when a parameter or result already uses its name.
+// mockMethod 构建一个方法: 函数字段为 nil 时 panic，否则返回调用它的结果。
+// 没有可用名称的参数命名为 p0、p1……以便转发；参数或返回值已使用接收者名称时重命名接收者
+func mockMethod(recv, mockName, field, name string, sig *types.Signature, qf types.Qualifier) (ast.Decl, error) {
+	recv = freeReceiverName(recv, sig)
+	used := map[string]bool{recv: true}
+	for i := 0; i < sig.Results().Len(); i++ {
+		used[sig.Results().At(i).Name()] = true
+	}
+
+	params := &ast.FieldList{}
+	var callArgs []ast.Expr
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		paramName := param.Name()
+		if paramName == "" || paramName == "_" || used[paramName] {
+			paramName = fmt.Sprintf("p%d", i)
+		}
+		used[paramName] = true
+
+		typeSrc := types.TypeString(param.Type(), qf)
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			typeSrc = "..." + types.TypeString(param.Type().(*types.Slice).Elem(), qf)
+		}
+		paramType, err := parseTypeExpr(typeSrc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %s", name, err.Error())
+		}
+		params.List = append(params.List, &ast.Field{Names: []*ast.Ident{ast.NewIdent(paramName)}, Type: paramType})
+		callArgs = append(callArgs, ast.NewIdent(paramName))
+	}
+
+	// Results keep their names from the interface
+	// 返回值沿用接口中的名称
+	var results *ast.FieldList
+	if sig.Results().Len() > 0 {
+		resultsType, err := parser.ParseExpr("func()" + types.TypeString(sig.Results(), qf))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %s", name, err.Error())
+		}
+		results = resultsType.(*ast.FuncType).Results
+	}
+
+	fn := &ast.SelectorExpr{X: ast.NewIdent(recv), Sel: ast.NewIdent(field)}
+	call := &ast.CallExpr{Fun: fn, Args: callArgs}
+	if sig.Variadic() {
+		call.Ellipsis = 1 // any valid position prints the ...
+	}
+	var last ast.Stmt = &ast.ExprStmt{X: call}
+	if results != nil {
+		last = &ast.ReturnStmt{Results: []ast.Expr{call}}
+	}
+
+	return &ast.FuncDecl{
+		Recv: &ast.FieldList{List: []*ast.Field{{
+			Names: []*ast.Ident{ast.NewIdent(recv)},
+			Type:  &ast.StarExpr{X: ast.NewIdent(mockName)},
+		}}},
+		Name: ast.NewIdent(name),
+		Type: &ast.FuncType{Params: params, Results: results},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.IfStmt{
+				Cond: &ast.BinaryExpr{X: fn, Op: token.EQL, Y: ast.NewIdent("nil")},
+				Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ExprStmt{X: &ast.CallExpr{
+					Fun:  ast.NewIdent("panic"),
+					Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(mockName + "." + name + " is not set")}},
+				}}}},
+			},
+			last,
+		}},
+	}, nil
+}
+
+// parseTypeExpr parses a parameter type, including a variadic ...T
+// parseTypeExpr 解析参数类型，包括可变参数 ...T
+func parseTypeExpr(src string) (ast.Expr, error) {
+	expr, err := parser.ParseExpr("func(" + src + ")")
+	if err != nil {
+		return nil, err
+	}
+	return expr.(*ast.FuncType).Params.List[0].Type, nil
+}
+
+// isStdImport reports whether an import path belongs to the standard
+// library, whose first element has no dot
+// isStdImport 判断导入路径是否属于标准库，标准库路径的第一个元素不含点
+func isStdImport(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// printDecl formats a generated declaration
+// printDecl 格式化生成的声明
+func printDecl(buf *strings.Builder, decl ast.Decl) error {
+	if err := format.Node(buf, token.NewFileSet(), decl); err != nil {
+		return fmt.Errorf("failed to format mock: %s", err.Error())
+	}
+	return nil
+}
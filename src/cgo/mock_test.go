@@ -0,0 +1,44 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestGenerateMockReceiverClashesWithResult(t *testing.T) {
+	ws := newWorkspace([]SourceFile{
+		{Path: "/w/go.mod", Content: "module example.com/greet\n\ngo 1.21\n"},
+		{Path: "/w/greet.go", Content: `package greet
+
+import "context"
+
+type Greeter interface {
+	SayHello(ctx context.Context, name string) (m string, err error)
+}
+`},
+	})
+	pkg, _ := ws.file("/w/greet.go")
+	ws.checkAll()
+	iface, ok := lookupInterface(ws, pkg, "Greeter")
+	if !ok {
+		t.Fatal("interface not found")
+	}
+
+	mock, err := generateMock(ws, iface, pkg, "/w", "MockGreeter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mock.Text, "func (m2 *MockGreeter) SayHello(ctx context.Context, name string) (m string, err error) {") {
+		t.Errorf("receiver not renamed:\n%s", mock.Text)
+	}
+	if !strings.Contains(mock.Text, "return m2.SayHelloFunc(ctx, name)") {
+		t.Errorf("call does not use the renamed receiver:\n%s", mock.Text)
+	}
+	if _, err := format.Source([]byte(mock.Text)); err != nil {
+		t.Errorf("mock does not parse: %s\n%s", err, mock.Text)
+	}
+}
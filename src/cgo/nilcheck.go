@@ -0,0 +1,354 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"syscall/js"
+)
+
+// CheckNilInterfaces finds returns that wrap a nil pointer of a concrete
+// type in an interface result, e.g. a function returning error that
+// returns a nil *MyError: the result then compares != nil at the caller.
+// Args: workspace files (JSON array of {path, content}).
+// 查找将具体类型的 nil 指针包装进接口返回值的 return 语句，例如返回 error 的函数返回了
+// nil 的 *MyError：调用方得到的结果与 nil 比较时不相等
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）
+func CheckNilInterfaces(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no files provided")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+
+	ws := newWorkspace(files)
+	ws.checkAll()
+
+	result, err := json.Marshal(checkNilInterfaces(ws))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// NilInterface is a return of a possibly nil concrete value as an interface
+// NilInterface 表示以接口形式返回可能为 nil 的具体类型值
+type NilInterface struct {
+	Path        string       `json:"path"`
+	Start       Position     `json:"start"` // start of the returned expression
+	End         Position     `json:"end"`
+	Function    string       `json:"function"`  // e.g. Parse or (*Parser).Parse, "" for function literals
+	Type        string       `json:"type"`      // concrete type returned, e.g. *MyError
+	Interface   string       `json:"interface"` // result type, e.g. error
+	Explicit    bool         `json:"explicit"`  // a typed nil such as (*MyError)(nil) rather than a variable
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+// Comparison is a comparison of the function result with nil
+// Comparison 表示函数结果与 nil 的比较
+type Comparison struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// checkNilInterfaces reports two conservative patterns with an interface
+// result: a typed nil conversion such as (*T)(nil), and a local variable of
+// a nillable concrete type that is declared without a value or assigned
+// nil, the usual var err *MyError ... return err. Untyped nil, parameters
+// and any other expression, such as a call or &T{}, are left alone, so a
+// genuine nil interface is never reported. Comparisons of the results of
+// reported functions with nil are attached to help judge the impact.
+// checkNilInterfaces 对接口返回值报告两种保守的模式：(*T)(nil) 之类的带类型 nil 转换，
+// 以及未赋初值或被赋值为 nil 的可为 nil 的具体类型局部变量，即常见的 var err *MyError ... return err。
+// 无类型 nil、参数以及函数调用、&T{} 等其他表达式都不报告，因此真正的 nil 接口不会被误报。
+// 被报告函数的结果与 nil 的比较会附带返回，便于判断影响
+func checkNilInterfaces(ws *workspace) []NilInterface {
+	results := []NilInterface{}
+	flagged := make(map[*types.Func]map[int][]int) // function -> result index -> indexes into results
+	for _, pkg := range ws.sortedPackages() {
+		if pkg.info == nil {
+			continue
+		}
+		qf := packageQualifier(pkg.types)
+		for _, f := range pkg.files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				var (
+					body *ast.BlockStmt
+					sig  *types.Signature
+					fn   *types.Func
+				)
+				switch n := n.(type) {
+				case *ast.FuncDecl:
+					fn, _ = pkg.info.Defs[n.Name].(*types.Func)
+					if fn != nil {
+						body, sig = n.Body, fn.Type().(*types.Signature)
+					}
+				case *ast.FuncLit:
+					sig, _ = pkg.info.TypeOf(n).(*types.Signature)
+					body = n.Body
+				}
+				if body == nil || sig == nil {
+					return true
+				}
+
+				for _, ret := range nilReturns(pkg.info, body, sig) {
+					start, end := ws.fset.Position(ret.expr.Pos()), ws.fset.Position(ret.expr.End())
+					item := NilInterface{
+						Path:        start.Filename,
+						Start:       Position{Line: start.Line, Column: start.Column},
+						End:         Position{Line: end.Line, Column: end.Column},
+						Type:        types.TypeString(pkg.info.TypeOf(ret.expr), qf),
+						Interface:   types.TypeString(sig.Results().At(ret.index).Type(), qf),
+						Explicit:    ret.explicit,
+						Comparisons: []Comparison{},
+					}
+					if fn != nil {
+						item.Function = funcName(fn, qf)
+						if flagged[fn] == nil {
+							flagged[fn] = make(map[int][]int)
+						}
+						flagged[fn][ret.index] = append(flagged[fn][ret.index], len(results))
+					}
+					results = append(results, item)
+				}
+				return true
+			})
+		}
+	}
+
+	for _, pkg := range ws.sortedPackages() {
+		if pkg.info == nil {
+			continue
+		}
+		for _, f := range pkg.files {
+			for _, cmp := range nilComparisons(pkg.info, f, flagged) {
+				pos := ws.fset.Position(cmp.pos)
+				for _, i := range flagged[cmp.fn][cmp.index] {
+					results[i].Comparisons = append(results[i].Comparisons, Comparison{Path: pos.Filename, Line: pos.Line, Column: pos.Column})
+				}
+			}
+		}
+	}
+	return results
+}
+
+// nilReturn is a returned expression reported by nilReturns
+// nilReturn 表示 nilReturns 报告的返回表达式
+type nilReturn struct {
+	expr     ast.Expr
+	index    int // index of the result
+	explicit bool
+}
+
+// nilReturns checks the return statements of a function body, not those of
+// the function literals inside it, which are checked on their own
+// nilReturns 检查函数体中的 return 语句，其中函数字面量的 return 语句单独检查
+func nilReturns(info *types.Info, body *ast.BlockStmt, sig *types.Signature) []nilReturn {
+	nilVars := nilVariables(info, body)
+	var returns []nilReturn
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != sig.Results().Len() {
+				return true
+			}
+			for i, expr := range n.Results {
+				if !types.IsInterface(sig.Results().At(i).Type()) || !isNillable(info.TypeOf(expr)) {
+					continue
+				}
+				if isTypedNil(info, expr) {
+					returns = append(returns, nilReturn{expr: expr, index: i, explicit: true})
+				} else if ident, ok := ast.Unparen(expr).(*ast.Ident); ok && nilVars[info.Uses[ident]] {
+					returns = append(returns, nilReturn{expr: expr, index: i})
+				}
+			}
+		}
+		return true
+	})
+	return returns
+}
+
+// nilVariables returns the local variables of a body with a nillable
+// concrete type that are declared without a value or assigned nil
+// nilVariables 返回函数体中类型为可为 nil 的具体类型、且未赋初值或被赋值为 nil 的局部变量
+func nilVariables(info *types.Info, body *ast.BlockStmt) map[types.Object]bool {
+	vars := make(map[types.Object]bool)
+	mark := func(ident *ast.Ident, obj types.Object) {
+		if v, ok := obj.(*types.Var); ok && ident.Name != "_" && isNillable(v.Type()) {
+			vars[v] = true
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for i, ident := range n.Names {
+				if len(n.Values) == 0 || (len(n.Values) == len(n.Names) && isNilValue(info, n.Values[i])) {
+					mark(ident, info.Defs[ident])
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || !isNilValue(info, n.Rhs[i]) {
+					continue
+				}
+				if obj := info.Defs[ident]; obj != nil {
+					mark(ident, obj)
+				} else {
+					mark(ident, info.Uses[ident])
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// isNillable reports whether t is a concrete type whose zero value is nil
+// isNillable 判断 t 是否为零值为 nil 的具体类型
+func isNillable(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		return true
+	}
+	return false
+}
+
+// isNilValue reports whether expr is nil, untyped or converted
+// isNilValue 判断表达式是否为 nil（无类型或经过类型转换）
+func isNilValue(info *types.Info, expr ast.Expr) bool {
+	return info.Types[ast.Unparen(expr)].IsNil() || isTypedNil(info, expr)
+}
+
+// isTypedNil reports whether expr converts nil to a type, e.g. (*T)(nil)
+// isTypedNil 判断表达式是否将 nil 转换为某个类型，例如 (*T)(nil)
+func isTypedNil(info *types.Info, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	return ok && len(call.Args) == 1 && info.Types[call.Fun].IsType() && info.Types[ast.Unparen(call.Args[0])].IsNil()
+}
+
+// funcName formats a function as Name or (*Recv).Name for methods
+// funcName 将函数格式化为 Name，方法格式化为 (*Recv).Name
+func funcName(fn *types.Func, qf types.Qualifier) string {
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() == nil {
+		return fn.Name()
+	}
+	return fmt.Sprintf("(%s).%s", types.TypeString(sig.Recv().Type(), qf), fn.Name())
+}
+
+// nilComparison is a comparison with nil of result index of fn
+// nilComparison 表示 fn 的第 index 个结果与 nil 的比较
+type nilComparison struct {
+	pos   token.Pos
+	fn    *types.Func
+	index int
+}
+
+// nilComparisons finds x == nil and x != nil where x is a call of a
+// flagged function or a variable holding one of its results
+// nilComparisons 查找 x == nil 与 x != nil，其中 x 是对被报告函数的调用或保存其结果的变量
+func nilComparisons(info *types.Info, f *ast.File, flagged map[*types.Func]map[int][]int) []nilComparison {
+	callee := func(expr ast.Expr) *types.Func {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return nil
+		}
+		var ident *ast.Ident
+		switch fun := ast.Unparen(call.Fun).(type) {
+		case *ast.Ident:
+			ident = fun
+		case *ast.SelectorExpr:
+			ident = fun.Sel
+		default:
+			return nil
+		}
+		fn, _ := info.Uses[ident].(*types.Func)
+		if fn != nil {
+			fn = fn.Origin()
+		}
+		if _, ok := flagged[fn]; !ok {
+			return nil
+		}
+		return fn
+	}
+
+	// Variables assigned the results of flagged functions
+	// 被赋值为被报告函数结果的变量
+	type result struct {
+		fn    *types.Func
+		index int
+	}
+	holders := make(map[types.Object]result)
+	assign := func(lhs []*ast.Ident, rhs []ast.Expr) {
+		if len(rhs) != 1 {
+			return
+		}
+		if fn := callee(rhs[0]); fn != nil {
+			for i, ident := range lhs {
+				if obj := info.ObjectOf(ident); obj != nil && ident.Name != "_" {
+					holders[obj] = result{fn, i}
+				}
+			}
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			assign(n.Names, n.Values)
+		case *ast.AssignStmt:
+			var lhs []*ast.Ident
+			for _, expr := range n.Lhs {
+				ident, _ := expr.(*ast.Ident)
+				if ident == nil {
+					ident = ast.NewIdent("_")
+				}
+				lhs = append(lhs, ident)
+			}
+			assign(lhs, n.Rhs)
+		}
+		return true
+	})
+
+	var comparisons []nilComparison
+	ast.Inspect(f, func(n ast.Node) bool {
+		bin, ok := n.(*ast.BinaryExpr)
+		if !ok || (bin.Op != token.EQL && bin.Op != token.NEQ) {
+			return true
+		}
+		x := bin.X
+		if !info.Types[ast.Unparen(bin.Y)].IsNil() {
+			if !info.Types[ast.Unparen(bin.X)].IsNil() {
+				return true
+			}
+			x = bin.Y
+		}
+		if fn := callee(x); fn != nil {
+			comparisons = append(comparisons, nilComparison{pos: bin.Pos(), fn: fn, index: 0})
+		} else if ident, ok := ast.Unparen(x).(*ast.Ident); ok {
+			if held, ok := holders[info.Uses[ident]]; ok {
+				comparisons = append(comparisons, nilComparison{pos: bin.Pos(), fn: held.fn, index: held.index})
+			}
+		}
+		return true
+	})
+	return comparisons
+}
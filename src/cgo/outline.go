@@ -0,0 +1,139 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"syscall/js"
+)
+
+// Kinds of outline entries
+// 大纲条目的类型
+const (
+	OutlineKindFunc      = "func" // functions and methods
+	OutlineKindType      = "type" // named types other than structs and interfaces, and aliases
+	OutlineKindStruct    = "struct"
+	OutlineKindInterface = "interface"
+	OutlineKindVar       = "var"
+	OutlineKindConst     = "const"
+)
+
+// FileOutline describes the top-level declarations of a Go file.
+// Args: file content.
+// 描述 Go 文件的顶层声明
+// 参数: 文件内容
+func FileOutline(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", args[0].String(), parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	result, err := json.Marshal(fileOutline(fset, file))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// OutlineEntry is a top-level declaration. Every name of a grouped var or
+// const declaration gets its own entry.
+// OutlineEntry 表示一个顶层声明，分组的 var 或 const 声明中每个名称各占一个条目
+type OutlineEntry struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Exported  bool   `json:"exported"`
+	StartLine int    `json:"startLine"` // 1-based, doc comment excluded
+	EndLine   int    `json:"endLine"`
+	Receiver  string `json:"receiver"` // receiver type of methods, e.g. *Server or List[T]
+	Doc       string `json:"doc"`      // first sentence of the doc comment
+}
+
+// fileOutline lists the declarations in source order. A spec inside a
+// group uses its own doc comment, falling back to the group's one when the
+// group holds a single spec, since that comment then documents it.
+// fileOutline 按源码顺序列出声明。分组内的声明使用自身的文档注释，
+// 分组只有一个声明时该分组的注释就是它的文档，因此作为后备
+func fileOutline(fset *token.FileSet, file *ast.File) []OutlineEntry {
+	entries := []OutlineEntry{}
+	// Ungrouped declarations start at their keyword, grouped ones at the spec
+	// 未分组的声明从关键字开始，分组内的声明从自身开始
+	node := func(decl *ast.GenDecl, spec ast.Spec) ast.Node {
+		if decl.Lparen.IsValid() {
+			return spec
+		}
+		return decl
+	}
+	add := func(kind, name string, node ast.Node, docs ...*ast.CommentGroup) *OutlineEntry {
+		entry := OutlineEntry{
+			Kind:      kind,
+			Name:      name,
+			Exported:  token.IsExported(name),
+			StartLine: fset.Position(node.Pos()).Line,
+			EndLine:   fset.Position(node.End()).Line,
+		}
+		for _, group := range docs {
+			if group != nil {
+				entry.Doc = docSynopsis(group)
+				break
+			}
+		}
+		entries = append(entries, entry)
+		return &entries[len(entries)-1]
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			entry := add(OutlineKindFunc, decl.Name.Name, decl, decl.Doc)
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				entry.Receiver = types.ExprString(decl.Recv.List[0].Type)
+			}
+
+		case *ast.GenDecl:
+			var groupDoc *ast.CommentGroup
+			if len(decl.Specs) == 1 {
+				groupDoc = decl.Doc
+			}
+
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					kind := OutlineKindType
+					if spec.Assign == token.NoPos {
+						switch spec.Type.(type) {
+						case *ast.StructType:
+							kind = OutlineKindStruct
+						case *ast.InterfaceType:
+							kind = OutlineKindInterface
+						}
+					}
+					add(kind, spec.Name.Name, node(decl, spec), spec.Doc, groupDoc)
+
+				case *ast.ValueSpec:
+					kind := OutlineKindVar
+					if decl.Tok == token.CONST {
+						kind = OutlineKindConst
+					}
+					for _, name := range spec.Names {
+						if name.Name != "_" {
+							add(kind, name.Name, node(decl, spec), spec.Doc, groupDoc)
+						}
+					}
+				}
+			}
+		}
+	}
+	return entries
+}
@@ -0,0 +1,69 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+	"syscall/js"
+)
+
+// PackageName returns the name declared by the package clause of a Go
+// file, which may differ from the directory name.
+// Args: file content, file path (optional, needed to tell external test packages).
+// 返回 Go 文件 package 子句声明的名称，它可能与目录名不同
+// 参数: 文件内容、文件路径（可选，用于识别外部测试包）
+func PackageName(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+	filePath := ""
+	if len(args) > 1 && args[1].Truthy() {
+		filePath = slashPath(args[1].String())
+	}
+
+	// Only the package clause is needed, so a file with errors further down
+	// still has a name
+	// 只需要 package 子句，因此后面有错误的文件仍然有名称
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", args[0].String(), parser.PackageClauseOnly)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse package clause: %s", err.Error()))
+	}
+
+	result, err := json.Marshal(packageName(file.Name.Name, filePath))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// PackageClause is the package name of a file
+// PackageClause 表示文件的包名
+type PackageClause struct {
+	Name         string `json:"name"`
+	Main         bool   `json:"main"`         // package main, built as a command
+	ExternalTest bool   `json:"externalTest"` // foo_test package of a _test.go file
+	// Package under test for external test packages, e.g. foo for foo_test, "" otherwise
+	// 外部测试包所测试的包，例如 foo_test 对应 foo，其他情况为 ""
+	TestedPackage string `json:"testedPackage"`
+}
+
+// packageName classifies a package name. As with the go command, and the
+// workspace loader, a _test suffix only makes an external test package in
+// a _test.go file; without a path the suffix alone decides.
+// packageName 对包名分类。与 go 命令及工作空间加载器一致，只有 _test.go 文件中的 _test 后缀才构成外部测试包；
+// 没有路径时仅按后缀判断
+func packageName(name, filePath string) PackageClause {
+	clause := PackageClause{Name: name, Main: name == "main"}
+	if base, ok := strings.CutSuffix(name, "_test"); ok && base != "" && (filePath == "" || strings.HasSuffix(filePath, "_test.go")) {
+		clause.ExternalTest = true
+		clause.TestedPackage = base
+	}
+	return clause
+}
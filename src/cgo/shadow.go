@@ -0,0 +1,158 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+	"syscall/js"
+)
+
+// FindShadowedMethods reports methods declared on a type that shadow a
+// method promoted from one of its embedded fields, such as
+// ChineseGreeter.SayHello hiding EnglishGreeter.SayHello.
+// Args: workspace files (JSON array of {path, content}), whether to report
+// every shadowed method (optional, by default only methods of interfaces the
+// type implements).
+// 报告类型上声明、遮蔽了从嵌入字段提升的方法的方法，例如 ChineseGreeter.SayHello 遮蔽 EnglishGreeter.SayHello
+// 参数: 工作空间文件（{path, content} 的 JSON 数组）、是否报告所有被遮蔽的方法（可选，默认只报告类型所实现接口中的方法）
+func FindShadowedMethods(this js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return createErrorJSON("no files provided")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	all := len(args) > 1 && args[1].Truthy()
+
+	ws := newWorkspace(files)
+	ws.checkAll()
+
+	result, err := json.Marshal(findShadowedMethods(ws, all))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ShadowedMethod is a method declared on a type hiding a promoted method
+// ShadowedMethod 表示类型上声明的、遮蔽了提升方法的方法
+type ShadowedMethod struct {
+	Type         string   `json:"type"`   // e.g. ChineseGreeter
+	Method       string   `json:"method"` // e.g. SayHello
+	Path         string   `json:"path"`
+	Start        Position `json:"start"`    // start of the method name
+	End          Position `json:"end"`      // end of the method name
+	Embedded     string   `json:"embedded"` // embedded fields the shadowed method is promoted through, e.g. EnglishGreeter or Base.Inner
+	ShadowedPath string   `json:"shadowedPath"`
+	ShadowedLine int      `json:"shadowedLine"`
+	Interfaces   []string `json:"interfaces"` // workspace interfaces with the method that the type or its pointer implements, e.g. greet.Greeter
+}
+
+// findShadowedMethods checks every embedded field of the struct types of the
+// workspace: the method a field would promote is the one selected on the
+// field type, so a method promoted from deeper down is found too, and a method
+// hiding several promoted ones is reported once, through the first field.
+// Selections are addressable and interfaces are matched by the pointer type,
+// which makes pointer receiver methods count.
+// findShadowedMethods 检查工作空间中结构体类型的每个嵌入字段：字段会提升的方法就是在字段类型上选择到的方法，
+// 因此也能找到从更深处提升的方法；遮蔽了多个提升方法的方法只通过第一个字段报告一次。
+// 选择时视为可寻址，并以指针类型匹配接口，从而计入指针接收者的方法
+func findShadowedMethods(ws *workspace, all bool) []ShadowedMethod {
+	var interfaces []*types.TypeName
+	for _, pkg := range ws.sortedPackages() {
+		pkgInterfaces, _ := packageTypes(pkg)
+		interfaces = append(interfaces, pkgInterfaces...)
+	}
+
+	results := []ShadowedMethod{}
+	for _, pkg := range ws.sortedPackages() {
+		_, concretes := packageTypes(pkg)
+		qf := packageQualifier(pkg.types)
+		for _, obj := range concretes {
+			named := obj.Type().(*types.Named)
+			st, ok := named.Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for i := 0; i < named.NumMethods(); i++ {
+				fn := named.Method(i)
+				item, ok := shadowedMethod(ws, st, fn)
+				if !ok {
+					continue
+				}
+				item.Type = obj.Name()
+				item.Interfaces = implementedWith(named, fn.Name(), interfaces, qf)
+				if all || len(item.Interfaces) > 0 {
+					results = append(results, item)
+				}
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Path != results[j].Path {
+			return results[i].Path < results[j].Path
+		}
+		return results[i].Start.Line < results[j].Start.Line
+	})
+	return results
+}
+
+// shadowedMethod looks for the method fn hides among the embedded fields of st
+// shadowedMethod 在 st 的嵌入字段中查找被 fn 遮蔽的方法
+func shadowedMethod(ws *workspace, st *types.Struct, fn *types.Func) (ShadowedMethod, bool) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Embedded() {
+			continue
+		}
+		obj, index, _ := types.LookupFieldOrMethod(field.Type(), true, fn.Pkg(), fn.Name())
+		shadowed, ok := obj.(*types.Func)
+		if !ok || !shadowed.Pos().IsValid() {
+			continue
+		}
+
+		embedded := field.Name()
+		if via := embeddingPath(field.Type(), index); via != "" {
+			embedded += "." + via
+		}
+		start, end := ws.fset.Position(fn.Pos()), ws.fset.Position(fn.Pos()+token.Pos(len(fn.Name())))
+		shadowedPath, shadowedLine := ws.position(shadowed.Pos())
+		return ShadowedMethod{
+			Method:       fn.Name(),
+			Path:         start.Filename,
+			Start:        Position{Line: start.Line, Column: start.Column},
+			End:          Position{Line: end.Line, Column: end.Column},
+			Embedded:     embedded,
+			ShadowedPath: shadowedPath,
+			ShadowedLine: shadowedLine,
+		}, true
+	}
+	return ShadowedMethod{}, false
+}
+
+// implementedWith returns the interfaces declaring a method name that a type
+// or its pointer implements, sorted
+// implementedWith 返回声明了该方法名、且被类型或其指针实现的接口，已排序
+func implementedWith(named *types.Named, name string, interfaces []*types.TypeName, qf types.Qualifier) []string {
+	names := []string{}
+	ptr := types.NewPointer(named)
+	for _, obj := range interfaces {
+		iface := obj.Type().Underlying().(*types.Interface)
+		if m, _, _ := types.LookupFieldOrMethod(iface, false, obj.Pkg(), name); m == nil {
+			continue
+		}
+		if types.Implements(ptr, iface) {
+			names = append(names, types.TypeString(obj.Type(), qf))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,131 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/semver"
+)
+
+// Sort orders of SortMods
+// SortMods 的排序方式
+const (
+	SortByFile     = "file"     // keep the file order
+	SortByPath     = "path"     // module path, ascending
+	SortByOutdated = "outdated" // most outdated first, see outdatedDistance
+)
+
+// SortOptions controls how SortMods orders the requires
+// SortOptions 控制 SortMods 如何排序依赖
+type SortOptions struct {
+	By           string            `json:"by"`           // file, path or outdated; file by default
+	Latest       map[string]string `json:"latest"`       // module path -> latest version, used by outdated
+	IndirectLast bool              `json:"indirectLast"` // sink // indirect requires below the direct ones
+}
+
+// SortMods returns the requires of a go.mod sorted by module path or by how
+// far they are behind their latest versions.
+// Args: go.mod content, JSON SortOptions (optional).
+// 返回按模块路径或按落后最新版本的程度排序的 go.mod 依赖
+// 参数: go.mod 内容、SortOptions JSON（可选）
+func SortMods(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	var opts SortOptions
+	if len(args) > 1 && args[1].Truthy() {
+		if err := json.Unmarshal([]byte(args[1].String()), &opts); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse options: %s", err.Error()))
+		}
+	}
+	switch opts.By {
+	case "":
+		opts.By = SortByFile
+	case SortByFile, SortByPath, SortByOutdated:
+	default:
+		return createErrorJSON(fmt.Sprintf("unknown sort order: %s", opts.By))
+	}
+
+	modInfo, err := parseModInfo(args[0].String())
+	if err != nil {
+		return createParseErrorJSON(err)
+	}
+
+	result, err := json.Marshal(sortMods(modInfo.Require, opts))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// sortMods sorts a copy of mods with a stable sort, so entries with equal
+// keys keep their file order
+// sortMods 使用稳定排序对 mods 的副本排序，键相同的条目保持文件中的顺序
+func sortMods(mods []Mod, opts SortOptions) []Mod {
+	sorted := append([]Mod{}, mods...)
+	slices.SortStableFunc(sorted, func(a, b Mod) int {
+		if opts.IndirectLast && a.Indirect != b.Indirect {
+			if b.Indirect {
+				return -1
+			}
+			return 1
+		}
+		switch opts.By {
+		case SortByPath:
+			return strings.Compare(a.Path, b.Path)
+		case SortByOutdated:
+			// Descending distance: the most outdated entry comes first
+			// 按距离降序：落后最多的条目在前
+			return slices.Compare(outdatedDistance(b, opts.Latest), outdatedDistance(a, opts.Latest))
+		}
+		return 0
+	})
+	return sorted
+}
+
+// outdatedDistance measures how far a require is behind its latest version
+// as [major, minor, patch] steps, where only the most significant differing
+// part counts: v1.2.3 -> v2.0.1 is [1 0 0] and v1.2.3 -> v1.4.0 is [0 2 0].
+// Modules without a latest version, or already at or after it, are [0 0 0].
+// outdatedDistance 以 [主版本, 次版本, 补丁] 步数衡量依赖落后最新版本的程度，只计算最高的不同部分:
+// v1.2.3 -> v2.0.1 为 [1 0 0]，v1.2.3 -> v1.4.0 为 [0 2 0]。没有最新版本或已不低于最新版本的模块为 [0 0 0]
+func outdatedDistance(mod Mod, latest map[string]string) []int {
+	distance := []int{0, 0, 0}
+	target, ok := latest[mod.Path]
+	if !ok || !semver.IsValid(mod.Version) || !semver.IsValid(target) || semver.Compare(mod.Version, target) >= 0 {
+		return distance
+	}
+
+	current, next := versionParts(mod.Version), versionParts(target)
+	for i := range distance {
+		if current[i] != next[i] {
+			distance[i] = next[i] - current[i]
+			break
+		}
+	}
+	return distance
+}
+
+// versionParts returns the major, minor and patch numbers of a valid semantic
+// version, ignoring prerelease and build suffixes
+// versionParts 返回合法语义化版本的主版本、次版本与补丁号，忽略预发布与构建后缀
+func versionParts(v string) []int {
+	core := strings.TrimPrefix(semver.Canonical(v), "v")
+	core, _, _ = strings.Cut(core, "-")
+	core, _, _ = strings.Cut(core, "+")
+
+	parts := make([]int, 3)
+	for i, s := range strings.SplitN(core, ".", 3) {
+		parts[i], _ = strconv.Atoi(s)
+	}
+	return parts
+}
@@ -0,0 +1,204 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// workGoVersion is the first Go version supporting go.work
+// workGoVersion 是第一个支持 go.work 的 Go 版本
+const workGoVersion = "1.18"
+
+// SplitWorkspace splits a go.mod into one module per subdirectory plus a
+// go.work using all of them.
+// Args: go.mod content, JSON object mapping import path prefixes to
+// subdirectories, e.g. {"example.com/mono/api": "api"}.
+// 将 go.mod 拆分为每个子目录一个模块，并生成使用这些模块的 go.work
+// 参数: go.mod 内容、导入路径前缀到子目录的 JSON 对象，例如 {"example.com/mono/api": "api"}
+func SplitWorkspace(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 2 {
+		return createErrorJSON("module mapping is required")
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal([]byte(args[1].String()), &mapping); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse module mapping: %s", err.Error()))
+	}
+
+	files, err := splitWorkspace(modFile, mapping)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(files)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// splitMember is a module split out of the root module
+// splitMember 表示从根模块拆分出的模块
+type splitMember struct {
+	modulePath string
+	dir        string // clean slash path relative to the root module
+}
+
+// splitWorkspace returns go.work followed by the go.mod of every member,
+// ordered by directory, with paths relative to the root module. A member's
+// module path is its import path prefix, so imports of its packages keep
+// resolving; the prefix must therefore lie inside the root module path.
+// Every member carries the requires, excludes, go and toolchain of the root,
+// and its local replaces are rebased onto the member directory. Members do
+// not require each other: the workspace resolves them.
+// splitWorkspace 返回 go.work 以及每个成员的 go.mod，按目录排序，路径相对于根模块。成员的模块路径
+// 就是它的导入路径前缀，因此对其包的导入仍能解析；前缀必须位于根模块路径之内。每个成员都沿用根模块的
+// require、exclude、go 与 toolchain，本地 replace 的路径改为相对成员目录。成员之间不互相 require，由工作区解析
+func splitWorkspace(root *modfile.File, mapping map[string]string) ([]SourceFile, error) {
+	if root.Module == nil {
+		return nil, fmt.Errorf("go.mod has no module directive")
+	}
+	rootPath := root.Module.Mod.Path
+
+	members := make([]splitMember, 0, len(mapping))
+	dirs := map[string]string{}
+	modulePaths := map[string]bool{}
+	prefixes := make([]string, 0, len(mapping))
+	for prefix := range mapping {
+		prefixes = append(prefixes, prefix)
+	}
+	slices.Sort(prefixes)
+	for _, prefix := range prefixes {
+		dir := mapping[prefix]
+		if !strings.HasPrefix(prefix, rootPath+"/") {
+			return nil, fmt.Errorf("%s is not inside module %s", prefix, rootPath)
+		}
+		if err := module.CheckPath(prefix); err != nil {
+			return nil, fmt.Errorf("invalid module path: %s", err.Error())
+		}
+
+		dir = path.Clean(slashPath(dir))
+		if dir == "." || path.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, "../") {
+			return nil, fmt.Errorf("%s must be a subdirectory of the module, got %s", prefix, dir)
+		}
+		if other, ok := dirs[dir]; ok {
+			return nil, fmt.Errorf("%s and %s both map to %s", other, prefix, dir)
+		}
+		dirs[dir] = prefix
+		modulePaths[prefix] = true
+		members = append(members, splitMember{modulePath: prefix, dir: dir})
+	}
+	slices.SortFunc(members, func(a, b splitMember) int { return strings.Compare(a.dir, b.dir) })
+
+	work := &modfile.WorkFile{Syntax: new(modfile.FileSyntax)}
+	goVersion := workGoVersion
+	if root.Go != nil && compareGoVersions(root.Go.Version, workGoVersion) > 0 {
+		goVersion = root.Go.Version
+	}
+	if err := work.AddGoStmt(goVersion); err != nil {
+		return nil, err
+	}
+	if root.Toolchain != nil {
+		if err := work.AddToolchainStmt(root.Toolchain.Name); err != nil {
+			return nil, err
+		}
+	}
+	work.AddNewUse(".", "")
+
+	files := []SourceFile{}
+	for _, member := range members {
+		work.AddNewUse("./"+member.dir, "")
+		content, err := memberModFile(root, member, modulePaths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s/go.mod: %s", member.dir, err.Error())
+		}
+		files = append(files, SourceFile{Path: member.dir + "/go.mod", Content: content})
+	}
+	work.Cleanup()
+
+	return append([]SourceFile{{Path: "go.work", Content: string(modfile.Format(work.Syntax))}}, files...), nil
+}
+
+// memberModFile builds the go.mod of a member with the modfile construction
+// API. Requires and replaces of the split modules themselves are dropped.
+// memberModFile 使用 modfile 构造 API 生成成员的 go.mod。对拆分出的模块自身的 require 与 replace 被丢弃
+func memberModFile(root *modfile.File, member splitMember, modulePaths map[string]bool) (string, error) {
+	f := new(modfile.File)
+	if err := f.AddModuleStmt(member.modulePath); err != nil {
+		return "", err
+	}
+	if root.Go != nil {
+		if err := f.AddGoStmt(root.Go.Version); err != nil {
+			return "", err
+		}
+	}
+	if root.Toolchain != nil {
+		if err := f.AddToolchainStmt(root.Toolchain.Name); err != nil {
+			return "", err
+		}
+	}
+
+	var requires []*modfile.Require
+	for _, req := range root.Require {
+		if !modulePaths[req.Mod.Path] && req.Mod.Path != root.Module.Mod.Path {
+			requires = append(requires, &modfile.Require{Mod: req.Mod, Indirect: req.Indirect})
+		}
+	}
+	f.SetRequireSeparateIndirect(requires)
+
+	for _, rep := range root.Replace {
+		if modulePaths[rep.Old.Path] {
+			continue
+		}
+		newPath := rep.New.Path
+		if modfile.IsDirectoryPath(newPath) && !path.IsAbs(slashPath(newPath)) {
+			newPath = relativeDir(member.dir, path.Clean(slashPath(newPath)))
+		}
+		if err := f.AddReplace(rep.Old.Path, rep.Old.Version, newPath, rep.New.Version); err != nil {
+			return "", err
+		}
+	}
+	for _, exc := range root.Exclude {
+		if err := f.AddExclude(exc.Mod.Path, exc.Mod.Version); err != nil {
+			return "", err
+		}
+	}
+
+	f.Cleanup()
+	return string(modfile.Format(f.Syntax)), nil
+}
+
+// relativeDir returns target, relative to the root module, as a local path
+// relative to dir, starting with ./ or ../ as replace directives require
+// relativeDir 将相对根模块的 target 转换为相对 dir 的本地路径，按 replace 指令的要求以 ./ 或 ../ 开头
+func relativeDir(dir, target string) string {
+	from, to := strings.Split(dir, "/"), strings.Split(target, "/")
+	if target == "." {
+		to = nil
+	}
+	i := 0
+	for i < len(from) && i < len(to) && from[i] == to[i] {
+		i++
+	}
+
+	parts := append(slices.Repeat([]string{".."}, len(from)-i), to[i:]...)
+	if len(parts) == 0 || parts[0] != ".." {
+		parts = append([]string{"."}, parts...)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,365 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall/js"
+	"unicode"
+)
+
+// Stubs is generated method source for a file
+// Stubs 表示为某个文件生成的方法源码
+type Stubs struct {
+	Path    string   `json:"path"`    // file receiving the stubs
+	Text    string   `json:"text"`    // source to append to the file
+	Imports []string `json:"imports"` // import paths the file is missing
+}
+
+// stubBody renders the body of a stub for a signature
+// stubBody 根据签名生成桩方法的方法体
+type stubBody func(sig *types.Signature, qf types.Qualifier) string
+
+// zeroBody returns zero values for every result
+// zeroBody 为每个返回值返回零值
+func zeroBody(sig *types.Signature, qf types.Qualifier) string {
+	if sig.Results().Len() == 0 {
+		return ""
+	}
+	values := make([]string, sig.Results().Len())
+	for i := range values {
+		values[i] = zeroValue(sig.Results().At(i).Type(), qf)
+	}
+	return "return " + strings.Join(values, ", ")
+}
+
+// panicBody panics with "not implemented"
+// panicBody 以 "not implemented" 触发 panic
+func panicBody(*types.Signature, types.Qualifier) string {
+	return `panic("not implemented")`
+}
+
+// zeroValue returns the source of the zero value of a type
+// zeroValue 返回类型零值的源码
+func zeroValue(t types.Type, qf types.Qualifier) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Struct, *types.Array:
+		return types.TypeString(t, qf) + "{}"
+	}
+	return "nil"
+}
+
+// generateStubs writes stub methods on a named type for the given interface
+// methods. Stubs follow the receiver name and pointer style of the existing
+// methods on the type; pointer is used when the type has no methods yet. A
+// receiver name clashing with a parameter or result of a method gets a suffix
+// in that stub.
+// generateStubs 为具名类型生成给定接口方法的桩方法。桩方法沿用该类型已有方法的
+// 接收者名称和指针风格；类型尚无方法时使用 pointer 参数。接收者名称与方法的参数或结果重名时，
+// 在该桩方法中加上后缀
+func generateStubs(ws *workspace, named *types.Named, pointer bool, iface types.Type, methods []*types.Func, body stubBody) Stubs {
+	filePath, _ := ws.position(named.Obj().Pos())
+	stubs := Stubs{Path: filePath, Imports: []string{}}
+
+	recvName, recvPointer, ok := receiverStyle(named)
+	if !ok {
+		recvName, recvPointer = receiverName(named.Obj().Name()), pointer
+	}
+	recvType := named.Obj().Name()
+	if recvPointer {
+		recvType = "*" + recvType
+	}
+
+	qf := stubQualifier(ws, filePath, named.Obj().Pkg(), &stubs.Imports)
+
+	ifaceName := types.TypeString(iface, packageQualifier(named.Obj().Pkg()))
+	var buf strings.Builder
+	for _, m := range methods {
+		sig := m.Type().(*types.Signature)
+		fmt.Fprintf(&buf, "\n// %s implements %s.\n", m.Name(), ifaceName)
+		fmt.Fprintf(&buf, "func (%s %s) %s%s {\n", freeReceiverName(recvName, sig), recvType, m.Name(),
+			strings.TrimPrefix(types.TypeString(sig, qf), "func"))
+		if text := body(sig, qf); text != "" {
+			fmt.Fprintf(&buf, "\t%s\n", text)
+		}
+		buf.WriteString("}\n")
+	}
+	stubs.Text = buf.String()
+	return stubs
+}
+
+// receiverStyle returns the receiver name and pointer style of the first
+// method declared on a type
+// receiverStyle 返回类型上第一个声明方法的接收者名称和指针风格
+func receiverStyle(named *types.Named) (string, bool, bool) {
+	for i := 0; i < named.NumMethods(); i++ {
+		recv := named.Method(i).Type().(*types.Signature).Recv()
+		if recv == nil || recv.Name() == "" || recv.Name() == "_" {
+			continue
+		}
+		_, pointer := recv.Type().(*types.Pointer)
+		return recv.Name(), pointer, true
+	}
+	return "", false, false
+}
+
+// freeReceiverName returns name, or name with the first numeric suffix that
+// no parameter or result of sig uses, e.g. e2 when a parameter is called e
+// freeReceiverName 返回 name，或加上 sig 的参数与结果都未使用的第一个数字后缀，例如参数名为 e 时返回 e2
+func freeReceiverName(name string, sig *types.Signature) string {
+	used := make(map[string]bool)
+	for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+		for i := 0; i < tuple.Len(); i++ {
+			used[tuple.At(i).Name()] = true
+		}
+	}
+	free := name
+	for n := 2; used[free]; n++ {
+		free = fmt.Sprintf("%s%d", name, n)
+	}
+	return free
+}
+
+// receiverName derives a receiver name from a type name, e.g. EnglishGreeter -> e
+// receiverName 根据类型名推导接收者名称，例如 EnglishGreeter -> e
+func receiverName(typeName string) string {
+	for _, r := range typeName {
+		return string(unicode.ToLower(r))
+	}
+	return "r"
+}
+
+// stubQualifier qualifies types the way the target file refers to their
+// packages, recording imports the file does not have yet
+// stubQualifier 按目标文件引用包的方式限定类型，并记录文件中尚未导入的包
+func stubQualifier(ws *workspace, filePath string, pkg *types.Package, missing *[]string) types.Qualifier {
+	names := make(map[string]string) // import path -> import name, "" for the package name
+	if _, f := ws.file(filePath); f != nil {
+		for _, spec := range f.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || (spec.Name != nil && spec.Name.Name == "_") {
+				continue
+			}
+			names[importPath] = ""
+			if spec.Name != nil {
+				names[importPath] = spec.Name.Name
+			}
+		}
+	}
+
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		name, ok := names[other.Path()]
+		if !ok {
+			names[other.Path()] = ""
+			*missing = append(*missing, other.Path())
+		}
+		switch name {
+		case ".":
+			return ""
+		case "":
+			return other.Name()
+		}
+		return name
+	}
+}
+
+// packageQualifier qualifies types from other packages by package name
+// packageQualifier 使用包名限定其他包中的类型
+func packageQualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+// interfaceMethods splits the methods of an interface into those a type is
+// missing and those it declares with a different signature
+// interfaceMethods 将接口方法分为类型缺少的方法和签名不一致的方法
+func interfaceMethods(typ types.Type, iface *types.Interface) (missing, mismatched []*types.Func) {
+	methodSet := types.NewMethodSet(typ)
+	var pointerSet *types.MethodSet
+	if _, ok := typ.(*types.Pointer); !ok {
+		pointerSet = types.NewMethodSet(types.NewPointer(typ))
+	}
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		sel := methodSet.Lookup(m.Pkg(), m.Name())
+		switch {
+		case sel != nil && types.Identical(sel.Type(), m.Type()):
+		case sel != nil:
+			mismatched = append(mismatched, m)
+		case pointerSet != nil && pointerSet.Lookup(m.Pkg(), m.Name()) != nil:
+			mismatched = append(mismatched, m) // declared with a pointer receiver
+		default:
+			missing = append(missing, m)
+		}
+	}
+	return missing, mismatched
+}
+
+// outsideEmbeds returns the interfaces embedded in t, directly or through
+// other embedded interfaces, that come from packages outside the workspace,
+// e.g. io.Closer. Their placeholders declare no methods, so the method set of
+// t is incomplete and missing methods cannot be told apart from unknown ones.
+// outsideEmbeds 返回 t 直接或通过其他嵌入接口嵌入的、来自工作空间之外包的接口，例如 io.Closer。
+// 它们的占位类型没有方法，因此 t 的方法集不完整，无法区分缺少的方法和未知的方法
+func outsideEmbeds(ws *workspace, t types.Type) []string {
+	var embeds []string
+	visited := make(map[types.Type]bool)
+	var walk func(t types.Type)
+	walk = func(t types.Type) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		if named, ok := t.(*types.Named); ok {
+			if pkg := named.Obj().Pkg(); pkg != nil && ws.fakes[pkg.Path()] == pkg {
+				embeds = append(embeds, types.TypeString(named, packageQualifier(nil)))
+				return
+			}
+		}
+		if iface, ok := t.Underlying().(*types.Interface); ok {
+			for i := 0; i < iface.NumEmbeddeds(); i++ {
+				walk(iface.EmbeddedType(i))
+			}
+		}
+	}
+	walk(t)
+	sort.Strings(embeds)
+	return embeds
+}
+
+// incompleteError reports an interface whose method set depends on
+// interfaces outside the workspace
+// incompleteError 报告方法集依赖工作空间之外接口的接口
+func incompleteError(embeds []string) error {
+	return fmt.Errorf("incomplete: embeds %s outside the workspace", strings.Join(embeds, ", "))
+}
+
+// methodNames returns the names of methods
+// methodNames 返回方法名称列表
+func methodNames(methods []*types.Func) []string {
+	names := make([]string, len(methods))
+	for i, m := range methods {
+		names[i] = m.Name()
+	}
+	return names
+}
+
+// isWorkspaceType reports whether a named type is declared in workspace sources
+// isWorkspaceType 判断具名类型是否声明在工作空间源码中
+func isWorkspaceType(ws *workspace, named *types.Named) bool {
+	obj := named.Obj()
+	if obj.Pkg() == nil || named.TypeParams().Len() > 0 {
+		return false
+	}
+	pkg, ok := ws.packages[obj.Pkg().Path()]
+	return ok && pkg.types == obj.Pkg()
+}
+
+// GenerateStubs generates panic("not implemented") stubs for the methods of
+// an interface that a type does not declare yet. Args are the workspace files
+// (JSON array of {path, content}), the file declaring the type, the type name
+// and the interface, either "Greeter" or qualified as "example.com/greet.Greeter".
+// GenerateStubs 为类型尚未声明的接口方法生成 panic("not implemented") 桩方法。
+// 参数为工作空间文件（{path, content} 的 JSON 数组）、声明类型的文件、类型名称和接口，
+// 接口可以是 "Greeter" 或限定形式 "example.com/greet.Greeter"
+func GenerateStubs(this js.Value, args []js.Value) any {
+	if len(args) < 4 {
+		return createErrorJSON("files, type file, type name and interface are required")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+	typePath, typeName, ifaceName := args[1].String(), args[2].String(), args[3].String()
+
+	ws := newWorkspace(files)
+	pkg, _ := ws.file(typePath)
+	if pkg == nil {
+		return createErrorJSON(fmt.Sprintf("file not found: %s", typePath))
+	}
+	ws.checkAll()
+
+	named, ok := lookupNamed(pkg.types, typeName)
+	if !ok || !isWorkspaceType(ws, named) || types.IsInterface(named) {
+		return createErrorJSON(fmt.Sprintf("type not found: %s", typeName))
+	}
+	iface, ok := lookupInterface(ws, pkg, ifaceName)
+	if !ok {
+		return createErrorJSON(fmt.Sprintf("interface not found: %s", ifaceName))
+	}
+	if embeds := outsideEmbeds(ws, iface); len(embeds) > 0 {
+		return createErrorJSON(incompleteError(embeds).Error())
+	}
+
+	// Check the pointer type so that existing pointer receiver methods count
+	// 检查指针类型，使已有的指针接收者方法也被计入
+	missing, _ := interfaceMethods(types.NewPointer(named), iface.Underlying().(*types.Interface))
+	stubs := generateStubs(ws, named, true, iface, missing, panicBody)
+
+	jsonData, err := json.Marshal(stubs)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+	return string(jsonData)
+}
+
+// lookupNamed finds a non-generic named type in a package scope
+// lookupNamed 在包作用域中查找非泛型具名类型
+func lookupNamed(pkg *types.Package, name string) (*types.Named, bool) {
+	if pkg == nil {
+		return nil, false
+	}
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+	named, ok := obj.Type().(*types.Named)
+	return named, ok && named.TypeParams().Len() == 0
+}
+
+// lookupInterface finds an interface by name, preferring the given package
+// when the name is not qualified by an import path
+// lookupInterface 按名称查找接口，名称未用导入路径限定时优先使用给定的包
+func lookupInterface(ws *workspace, from *wsPackage, name string) (*types.Named, bool) {
+	candidates := ws.sortedPackages()
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		pkg, ok := ws.packages[name[:i]]
+		if !ok {
+			return nil, false
+		}
+		candidates, name = []*wsPackage{pkg}, name[i+1:]
+	} else {
+		candidates = append([]*wsPackage{from}, candidates...)
+	}
+
+	for _, pkg := range candidates {
+		if named, ok := lookupNamed(pkg.types, name); ok && types.IsInterface(named) {
+			return named, true
+		}
+	}
+	return nil, false
+}
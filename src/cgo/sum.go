@@ -0,0 +1,162 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// CheckSum compares go.mod with go.sum and reports required modules whose
+// hashes are missing and go.sum entries that are no longer required.
+// Args: go.mod content, go.sum content.
+// 比较 go.mod 与 go.sum，报告缺少哈希的依赖模块以及不再需要的 go.sum 条目
+// 参数: go.mod 内容、go.sum 内容
+func CheckSum(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 2 {
+		return createErrorJSON("go.sum content is required")
+	}
+
+	result, err := json.Marshal(checkSum(modFile, args[1].String()))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// SumCheck is the result of CheckSum. Entries are module@version strings.
+// SumCheck 是 CheckSum 的结果，条目为 module@version 字符串
+type SumCheck struct {
+	Missing  []string  `json:"missing"`  // required but without a /go.mod hash
+	Orphaned []string  `json:"orphaned"` // in go.sum but no longer required
+	Warnings []Warning `json:"warnings"` // malformed go.sum lines
+}
+
+// sumEntry pairs the two go.sum lines of a module version:
+// "path version h1:..." hashes the module zip and
+// "path version/go.mod h1:..." hashes its go.mod file
+// sumEntry 将模块版本的两行 go.sum 配对："path version h1:..." 是模块 zip 的哈希，
+// "path version/go.mod h1:..." 是其 go.mod 文件的哈希
+type sumEntry struct {
+	zip   bool
+	goMod bool
+}
+
+// checkSum matches the requirements of go.mod, after applying replace
+// directives, against go.sum. The go command always needs the /go.mod hash
+// of a required module, while the zip hash is only needed when the module
+// provides packages, so only a missing /go.mod hash counts as missing.
+// An entry is orphaned when its module is not required at all, or when it
+// still holds the zip hash of a version the module has moved away from;
+// /go.mod-only lines of other versions are kept since the module graph
+// may need them.
+// checkSum 将应用 replace 指令后的 go.mod 依赖与 go.sum 进行匹配。go 命令总是需要依赖模块的
+// /go.mod 哈希，而 zip 哈希只在模块提供包时才需要，因此只有缺少 /go.mod 哈希才算缺失。
+// 模块完全不再被依赖，或者仍保留已升级/降级前版本的 zip 哈希时，条目视为孤立；
+// 其他版本的 /go.mod 行可能是模块图所需，因此保留
+func checkSum(modFile *modfile.File, sum string) SumCheck {
+	check := SumCheck{Missing: []string{}, Orphaned: []string{}, Warnings: []Warning{}}
+
+	entries := make(map[module.Version]*sumEntry)
+	for i, line := range strings.Split(sum, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "h1:") {
+			check.Warnings = append(check.Warnings, Warning{
+				Kind:    WarningMalformedSum,
+				Message: fmt.Sprintf("malformed go.sum line: %s", strings.TrimSpace(line)),
+				Line:    i + 1,
+			})
+			continue
+		}
+
+		version, goMod := strings.CutSuffix(fields[1], "/go.mod")
+		key := module.Version{Path: fields[0], Version: version}
+		entry := entries[key]
+		if entry == nil {
+			entry = &sumEntry{}
+			entries[key] = entry
+		}
+		if goMod {
+			entry.goMod = true
+		} else {
+			entry.zip = true
+		}
+	}
+
+	// Required versions after replacement; local replacements need no hash
+	// 替换后的依赖版本，本地替换不需要哈希
+	required := make(map[module.Version]bool)
+	requiredPaths := make(map[string]string)
+	for _, req := range modFile.Require {
+		target, ok := replacedVersion(modFile.Replace, req.Mod)
+		if !ok {
+			continue
+		}
+		required[target] = true
+		requiredPaths[target.Path] = target.Version
+
+		if entry := entries[target]; entry == nil || !entry.goMod {
+			check.Missing = append(check.Missing, target.String())
+		}
+	}
+
+	for key, entry := range entries {
+		version, ok := requiredPaths[key.Path]
+		switch {
+		case required[key]:
+		case !ok:
+			check.Orphaned = append(check.Orphaned, key.String())
+		case entry.zip && version != key.Version:
+			check.Orphaned = append(check.Orphaned, key.String())
+		}
+	}
+
+	sort.Strings(check.Missing)
+	sort.Strings(check.Orphaned)
+	return check
+}
+
+// replacedVersion applies the replace directives to a required module. A
+// replace with a version only matches that version and takes precedence
+// over a wildcard one. ok is false for replacements by a local directory.
+// replacedVersion 对依赖模块应用 replace 指令。带版本的 replace 只匹配该版本，
+// 且优先于通配的 replace。替换为本地目录时 ok 为 false
+func replacedVersion(replaces []*modfile.Replace, mod module.Version) (module.Version, bool) {
+	var match *modfile.Replace
+	for _, rep := range replaces {
+		if rep.Old.Path != mod.Path {
+			continue
+		}
+		if rep.Old.Version == mod.Version {
+			match = rep
+			break
+		}
+		if rep.Old.Version == "" {
+			match = rep
+		}
+	}
+
+	switch {
+	case match == nil:
+		return mod, true
+	case replaceKind(match) == ReplaceKindLocal:
+		return module.Version{}, false
+	default:
+		return match.New, true
+	}
+}
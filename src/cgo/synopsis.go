@@ -0,0 +1,131 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"strings"
+	"syscall/js"
+)
+
+// DocSynopsis returns the first sentence of the doc comment of the
+// declaration at a line.
+// Args: file content, 1-based line of the declared name.
+// 返回指定行上声明的文档注释的第一句
+// 参数: 文件内容、声明名称所在行（从 1 开始）
+func DocSynopsis(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("content and line are required")
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", args[0].String(), parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	line := args[1].Int()
+	name, group, ok := declarationDoc(fset, file, line)
+	if !ok {
+		return createErrorJSON(fmt.Sprintf("no declaration at line %d", line))
+	}
+
+	result, err := json.Marshal(Synopsis{Name: name, Synopsis: docSynopsis(group)})
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// Synopsis is the result of DocSynopsis
+// Synopsis 是 DocSynopsis 的结果
+type Synopsis struct {
+	Name     string `json:"name"`
+	Synopsis string `json:"synopsis"` // "" when the declaration has no doc comment
+}
+
+// sentenceEnds are the full-width marks ending a Chinese sentence that
+// doc.Synopsis does not stop at; it already handles 。 and ．
+// sentenceEnds 是 doc.Synopsis 不会截断的中文句末标点，它已处理 。 与 ．
+const sentenceEnds = "！？；"
+
+// docSynopsis returns the first sentence of a doc comment with
+// doc.Synopsis, which also drops comments such as copyright notices, and
+// cuts it after the first full-width sentence mark it leaves in place.
+// docSynopsis 使用 doc.Synopsis 返回文档注释的第一句（版权声明等注释会被丢弃），
+// 并在其保留的第一个全角句末标点之后截断
+func docSynopsis(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	synopsis := new(doc.Package).Synopsis(group.Text())
+	if i := strings.IndexAny(synopsis, sentenceEnds); i >= 0 {
+		synopsis = strings.TrimSpace(synopsis[:i+len("！")])
+	}
+	return synopsis
+}
+
+// declarationDoc finds the declaration whose name is on a line: functions,
+// methods, types, vars, consts, struct fields and interface methods. A spec
+// without its own doc comment uses the one of its group when the group holds
+// a single spec, as fileOutline does.
+// declarationDoc 查找名称位于指定行的声明：函数、方法、类型、变量、常量、结构体字段与接口方法。
+// 与 fileOutline 一致，没有自身文档注释的声明在分组只有一个声明时使用分组的注释
+func declarationDoc(fset *token.FileSet, file *ast.File, line int) (string, *ast.CommentGroup, bool) {
+	var (
+		name  string
+		group *ast.CommentGroup
+		found bool
+	)
+	at := func(ident *ast.Ident, docs ...*ast.CommentGroup) bool {
+		if found || fset.Position(ident.Pos()).Line != line {
+			return false
+		}
+		name, found = ident.Name, true
+		for _, doc := range docs {
+			if doc != nil {
+				group = doc
+				break
+			}
+		}
+		return true
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			at(n.Name, n.Doc)
+		case *ast.GenDecl:
+			var groupDoc *ast.CommentGroup
+			if len(n.Specs) == 1 {
+				groupDoc = n.Doc
+			}
+			for _, spec := range n.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					at(spec.Name, spec.Doc, groupDoc)
+				case *ast.ValueSpec:
+					for _, ident := range spec.Names {
+						at(ident, spec.Doc, groupDoc)
+					}
+				}
+			}
+		case *ast.Field:
+			for _, ident := range n.Names {
+				at(ident, n.Doc, n.Comment)
+			}
+		}
+		return true
+	})
+	return name, group, found
+}
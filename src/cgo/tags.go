@@ -0,0 +1,308 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall/js"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Case styles of generated tag values
+// 生成的标签值的命名风格
+const (
+	TagCaseSnake = "snake" // user_id
+	TagCaseCamel = "camel" // userId
+	TagCaseKebab = "kebab" // user-id
+)
+
+// TagOptions controls which fields GenerateStructTags tags
+// TagOptions 控制 GenerateStructTags 为哪些字段添加标签
+type TagOptions struct {
+	Tag            string `json:"tag"`            // tag key, e.g. json
+	Case           string `json:"case"`           // snake, camel or kebab
+	SkipEmbedded   bool   `json:"skipEmbedded"`   // leave embedded fields untagged
+	SkipUnexported bool   `json:"skipUnexported"` // leave unexported fields untagged
+}
+
+// TagEdit replaces columns [Start, End) of a line. Columns are UTF-16
+// offsets so they can be used as VSCode positions directly.
+// TagEdit 替换某行的 [Start, End) 列，列为 UTF-16 偏移，可直接用作 VSCode 位置
+type TagEdit struct {
+	Line  int    `json:"line"` // 1-based
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// GenerateStructTags adds a tag to every field of a struct that does not
+// carry it yet, keeping existing tags and other keys as they are.
+// Args: file content, struct name, 1-based line inside the struct (used when
+// the name is empty), JSON TagOptions.
+// 为结构体中尚未带有该标签的字段添加标签，保留已有的标签和其他键
+// 参数: 文件内容、结构体名称、结构体内从 1 开始的行号（名称为空时使用）、TagOptions JSON
+func GenerateStructTags(this js.Value, args []js.Value) any {
+	if len(args) < 4 {
+		return createErrorJSON("content, struct name, line and options are required")
+	}
+
+	var opts TagOptions
+	if err := json.Unmarshal([]byte(args[3].String()), &opts); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse options: %s", err.Error()))
+	}
+	if opts.Tag == "" {
+		return createErrorJSON("tag name is required")
+	}
+
+	src := args[0].String()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", src, parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	st := findStruct(fset, file, args[1].String(), args[2].Int())
+	if st == nil {
+		return createErrorJSON("struct not found")
+	}
+
+	result, err := json.Marshal(structTagEdits(fset, []byte(src), st, opts))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// findStruct finds a struct type by name, or the innermost named struct
+// type containing the line when the name is empty
+// findStruct 按名称查找结构体类型，名称为空时查找包含该行的最内层具名结构体类型
+func findStruct(fset *token.FileSet, file *ast.File, name string, line int) *ast.StructType {
+	var found *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		if name != "" {
+			if spec.Name.Name == name && found == nil {
+				found = st
+			}
+			return found == nil
+		}
+		if fset.Position(spec.Pos()).Line <= line && line <= fset.Position(spec.End()).Line {
+			found = st
+		}
+		return true
+	})
+	return found
+}
+
+// structTagEdits computes the edits adding the tag to the fields of a
+// struct. Fields declaring several names share one tag and are skipped, as
+// are blank fields and fields whose tag cannot be parsed.
+// structTagEdits 计算为结构体字段添加标签的编辑。声明多个名称的字段共享一个标签，会被跳过，
+// 空白字段和标签无法解析的字段也会被跳过
+func structTagEdits(fset *token.FileSet, src []byte, st *ast.StructType, opts TagOptions) []TagEdit {
+	edits := []TagEdit{}
+	for _, field := range st.Fields.List {
+		var name string
+		switch {
+		case len(field.Names) == 0:
+			if opts.SkipEmbedded {
+				continue
+			}
+			name = embeddedName(field.Type)
+		case len(field.Names) == 1:
+			name = field.Names[0].Name
+		default:
+			continue
+		}
+		if name == "" || name == "_" || (opts.SkipUnexported && !ast.IsExported(name)) {
+			continue
+		}
+
+		pair := fmt.Sprintf(`%s:"%s"`, opts.Tag, convertCase(name, opts.Case))
+		if field.Tag == nil {
+			// Insert after the type, before any trailing comment
+			// 插入到类型之后、行尾注释之前
+			edits = append(edits, newTagEdit(fset, src, field.Type.End(), field.Type.End(), " `"+pair+"`"))
+			continue
+		}
+
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		if keys, ok := parseTag(tag); !ok || slices.Contains(keys, opts.Tag) {
+			continue
+		}
+		if tag = strings.TrimSpace(tag); tag != "" {
+			pair = tag + " " + pair
+		}
+		edits = append(edits, newTagEdit(fset, src, field.Tag.Pos(), field.Tag.End(), quoteTag(pair)))
+	}
+	return edits
+}
+
+// newTagEdit converts a token range into a single-line TagEdit
+// newTagEdit 将 token 范围转换为单行 TagEdit
+func newTagEdit(fset *token.FileSet, src []byte, start, end token.Pos, text string) TagEdit {
+	startPos, endPos := fset.Position(start), fset.Position(end)
+	lineStart := startPos.Offset - (startPos.Column - 1)
+	return TagEdit{
+		Line:  startPos.Line,
+		Start: utf16Len(src[lineStart:startPos.Offset]),
+		End:   utf16Len(src[lineStart:endPos.Offset]),
+		Text:  text,
+	}
+}
+
+// utf16Len returns the length of UTF-8 text in UTF-16 code units
+// utf16Len 返回 UTF-8 文本的 UTF-16 码元长度
+func utf16Len(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		n += len(utf16.Encode([]rune{r}))
+		b = b[size:]
+	}
+	return n
+}
+
+// quoteTag quotes a tag as a raw string, falling back to an interpreted
+// string when it contains a backquote
+// quoteTag 将标签引用为原始字符串，包含反引号时退回为解释型字符串
+func quoteTag(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// embeddedName returns the field name of an embedded field: the type name
+// without pointer, package qualifier or type arguments
+// embeddedName 返回嵌入字段的字段名：去掉指针、包限定符和类型参数后的类型名
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedName(t.X)
+	}
+	return ""
+}
+
+// parseTag returns the keys of a struct tag, following the parsing rules
+// of reflect.StructTag.Lookup
+// parseTag 按 reflect.StructTag.Lookup 的解析规则返回结构体标签的键
+func parseTag(tag string) ([]string, bool) {
+	var keys []string
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			return keys, true
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return keys, false
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return keys, false
+		}
+		if _, err := strconv.Unquote(tag[:i+1]); err != nil {
+			return keys, false
+		}
+		keys = append(keys, key)
+		tag = tag[i+1:]
+	}
+}
+
+// convertCase converts a Go identifier to the case style, treating runs of
+// capitals as one word: UserID → user_id, HTTPServer → http_server, and
+// userID / httpServer in camel case
+// convertCase 将 Go 标识符转换为指定命名风格，连续大写字母视为一个单词：
+// UserID → user_id，HTTPServer → http_server，驼峰风格为 userID / httpServer
+func convertCase(name, style string) string {
+	words := splitWords(name)
+	switch style {
+	case TagCaseCamel:
+		for i, w := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(w)
+			words[i] = string(unicode.ToUpper(r)) + w[size:]
+		}
+		return strings.Join(words, "")
+	case TagCaseKebab:
+		return strings.ToLower(strings.Join(words, "-"))
+	default:
+		return strings.ToLower(strings.Join(words, "_"))
+	}
+}
+
+// splitWords splits an identifier at underscores and case changes; digits
+// stay with the preceding word
+// splitWords 在下划线和大小写变化处拆分标识符，数字归属于前一个单词
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = end
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush(i)
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
@@ -0,0 +1,433 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall/js"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of runnable test functions
+// 可运行测试函数的类型
+const (
+	TestKindTest      = "test"      // func TestXxx(t *testing.T)
+	TestKindBenchmark = "benchmark" // func BenchmarkXxx(b *testing.B)
+	TestKindExample   = "example"   // func ExampleXxx()
+	TestKindFuzz      = "fuzz"      // func FuzzXxx(f *testing.F)
+)
+
+// Problems of test-looking functions reported by CheckTestPlacement
+// CheckTestPlacement 报告的疑似测试函数的问题
+const (
+	TestProblemNonTestFile = "test-in-non-test-file" // a test outside a _test.go file, go test never runs it
+	TestProblemLowercase   = "malformed-test-name"   // TestXxx has a lowercase rune after the prefix
+	TestProblemSignature   = "wrong-test-signature"  // e.g. TestXxx(b *testing.B) or a test with results
+)
+
+// Kinds of example output checks
+// 示例输出检查的类型
+const (
+	ExampleOutputOrdered   = "ordered"   // // Output:
+	ExampleOutputUnordered = "unordered" // // Unordered output:
+)
+
+// FindTests reports the functions of a _test.go file that go test runs.
+// Args: file content, whether to detect subtests declared via t.Run.
+// 报告 _test.go 文件中 go test 会运行的函数
+// 参数: 文件内容、是否检测通过 t.Run 声明的子测试
+func FindTests(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+	subtests := len(args) > 1 && args[1].Truthy()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x_test.go", args[0].String(), parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	result, err := json.Marshal(findTests(fset, file, subtests))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// TestFunc is a function run by go test
+// TestFunc 表示 go test 运行的函数
+type TestFunc struct {
+	Name     string    `json:"name"`
+	Kind     string    `json:"kind"` // test, benchmark, example or fuzz
+	Line     int       `json:"line"` // 1-based line of the func keyword
+	Subtests []Subtest `json:"subtests"`
+	// Examples only: how the output is checked, "" when the example has no
+	// output comment and go test only compiles it
+	// 仅示例函数：输出的检查方式，没有输出注释时为 ""，此时 go test 只编译不运行
+	Output        string `json:"output,omitempty"`
+	OutputLine    int    `json:"outputLine,omitempty"`    // 1-based line of the output comment
+	OutputEndLine int    `json:"outputEndLine,omitempty"` // 1-based line where the output comment ends
+}
+
+// Subtest is a subtest or sub-benchmark declared with a literal name
+// Subtest 表示以字面量名称声明的子测试或子基准测试
+type Subtest struct {
+	Name string `json:"name"` // full name, e.g. TestParse/empty input
+	Line int    `json:"line"` // 1-based line of the Run call
+}
+
+// findTests matches top-level functions against the rules of go test:
+// the name must be the prefix alone or followed by a non-lowercase rune, and
+// the signature must take exactly *testing.T, *testing.B or *testing.F (none
+// for examples) and return nothing. Helpers such as testHelper or Testify
+// and functions merely named FuzzSomething are therefore skipped.
+// findTests 按 go test 的规则匹配顶层函数：名称必须是前缀本身或前缀后紧跟非小写字符，
+// 签名必须只接收 *testing.T、*testing.B 或 *testing.F（示例函数无参数）且没有返回值，
+// 因此 testHelper、Testify 之类的辅助函数以及仅名为 FuzzSomething 的函数会被跳过
+func findTests(fset *token.FileSet, file *ast.File, subtests bool) []TestFunc {
+	testing := testingImportName(file)
+	tests := []TestFunc{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil || fn.Type.TypeParams != nil || fn.Type.Results != nil {
+			continue
+		}
+
+		var kind, param string
+		switch {
+		case isTestName(fn.Name.Name, "Test"):
+			kind, param = TestKindTest, testingParam(fn, testing, "T")
+		case isTestName(fn.Name.Name, "Benchmark"):
+			kind, param = TestKindBenchmark, testingParam(fn, testing, "B")
+		case isTestName(fn.Name.Name, "Fuzz"):
+			kind, param = TestKindFuzz, testingParam(fn, testing, "F")
+		case isTestName(fn.Name.Name, "Example"):
+			if fn.Type.Params.NumFields() == 0 {
+				kind = TestKindExample
+			}
+		}
+		if kind == "" || (kind != TestKindExample && param == "") {
+			continue
+		}
+
+		test := TestFunc{
+			Name:     fn.Name.Name,
+			Kind:     kind,
+			Line:     fset.Position(fn.Pos()).Line,
+			Subtests: []Subtest{},
+		}
+		// testing.F has no Run method, the fuzz function runs the inputs
+		// testing.F 没有 Run 方法，输入由 fuzz 函数运行
+		if subtests && param != "_" && kind != TestKindFuzz {
+			test.Subtests = findSubtests(fset, fn.Body, param, fn.Name.Name)
+		}
+		if kind == TestKindExample {
+			if output, group := exampleOutput(file, fn.Body); group != nil {
+				test.Output = output
+				test.OutputLine = fset.Position(group.Pos()).Line
+				test.OutputEndLine = fset.Position(group.End()).Line
+			}
+		}
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// CheckTestPlacement reports test functions go test silently skips: tests,
+// benchmarks and fuzz tests declared outside a _test.go file, and functions
+// of a _test.go file that take *testing.T, B or F but have a malformed name
+// or signature.
+// Args: file content, file path.
+// 报告 go test 会悄悄跳过的测试函数：在 _test.go 文件之外声明的测试、基准测试与模糊测试，
+// 以及 _test.go 文件中接收 *testing.T、B 或 F 但名称或签名不正确的函数
+// 参数: 文件内容、文件路径
+func CheckTestPlacement(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("file content and path are required")
+	}
+
+	src, name := args[0].String(), path.Base(slashPath(args[1].String()))
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, src, parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	var problems []TestProblem
+	if strings.HasSuffix(name, "_test.go") {
+		problems = malformedTests(fset, src, file)
+	} else {
+		problems = misplacedTests(fset, src, file, name)
+	}
+
+	result, err := json.Marshal(problems)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// TestProblem is a test-looking function go test does not run
+// TestProblem 表示 go test 不会运行的疑似测试函数
+type TestProblem struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`    // test, benchmark or fuzz
+	Problem    string   `json:"problem"` // one of the TestProblem kinds
+	Message    string   `json:"message"`
+	Start      Position `json:"start"` // start of the function name
+	End        Position `json:"end"`
+	Suggestion string   `json:"suggestion"` // new file name for misplaced tests, new function name for malformed names, "" otherwise
+}
+
+// misplacedTests reports the functions of a non-test file that go test
+// would run if the file ended in _test.go. Examples are left out, since
+// ExampleXxx() is an ordinary signature outside tests.
+// misplacedTests 报告非测试文件中那些文件名以 _test.go 结尾时 go test 会运行的函数。
+// 示例函数不包括在内，因为 ExampleXxx() 在测试之外也是普通的签名
+func misplacedTests(fset *token.FileSet, src string, file *ast.File, name string) []TestProblem {
+	byName := map[string]*ast.FuncDecl{}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+			byName[fn.Name.Name] = fn
+		}
+	}
+
+	problems := []TestProblem{}
+	for _, test := range findTests(fset, file, false) {
+		if test.Kind == TestKindExample {
+			continue
+		}
+		fn := byName[test.Name]
+		problems = append(problems, TestProblem{
+			Name:       test.Name,
+			Kind:       test.Kind,
+			Problem:    TestProblemNonTestFile,
+			Message:    fmt.Sprintf("%s is declared in %s, go test only runs the tests of _test.go files", test.Name, name),
+			Start:      runePosition(fset, src, fn.Name.Pos()),
+			End:        runePosition(fset, src, fn.Name.End()),
+			Suggestion: strings.TrimSuffix(name, ".go") + "_test.go",
+		})
+	}
+	return problems
+}
+
+// testPrefixes are the name prefixes of test functions, with the testing
+// type of their parameter
+// testPrefixes 是测试函数的名称前缀及其参数的 testing 类型
+var testPrefixes = []struct{ prefix, kind, typ string }{
+	{"Test", TestKindTest, "T"},
+	{"Benchmark", TestKindBenchmark, "B"},
+	{"Fuzz", TestKindFuzz, "F"},
+}
+
+// malformedTests reports the functions of a _test.go file that take a
+// single *testing.T, B or F and start with a test prefix, but that go test
+// does not run: Testfoo, whose prefix is followed by a lowercase rune, and
+// TestFoo with results, type parameters or the parameter of another kind.
+// As with go vet, functions with other parameters, such as TestMain(m
+// *testing.M) or helpers, are not test-looking.
+// malformedTests 报告 _test.go 文件中接收单个 *testing.T、B 或 F 且以测试前缀开头、但 go test 不会运行的函数：
+// 前缀后紧跟小写字符的 Testfoo，以及有返回值、类型参数或参数类型不匹配的 TestFoo。
+// 与 go vet 一致，参数不同的函数（如 TestMain(m *testing.M) 或辅助函数）不视为疑似测试函数
+func malformedTests(fset *token.FileSet, src string, file *ast.File) []TestProblem {
+	testing := testingImportName(file)
+	run := map[string]bool{}
+	for _, test := range findTests(fset, file, false) {
+		run[test.Name] = true
+	}
+
+	problems := []TestProblem{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil || run[fn.Name.Name] {
+			continue
+		}
+
+		name := fn.Name.Name
+		for _, p := range testPrefixes {
+			rest, ok := strings.CutPrefix(name, p.prefix)
+			if !ok {
+				continue
+			}
+			typ := ""
+			for _, t := range []string{"T", "B", "F"} {
+				if testingParam(fn, testing, t) != "" {
+					typ = t
+				}
+			}
+			if typ == "" {
+				break
+			}
+
+			problem := TestProblem{
+				Name:  name,
+				Kind:  p.kind,
+				Start: runePosition(fset, src, fn.Name.Pos()),
+				End:   runePosition(fset, src, fn.Name.End()),
+			}
+			if !isTestName(name, p.prefix) {
+				r, size := utf8.DecodeRuneInString(rest)
+				problem.Problem = TestProblemLowercase
+				problem.Suggestion = p.prefix + string(unicode.ToUpper(r)) + rest[size:]
+				problem.Message = fmt.Sprintf("%s has a malformed name: the first letter after %s must not be lowercase", name, p.prefix)
+			} else {
+				problem.Problem = TestProblemSignature
+				problem.Message = fmt.Sprintf("%s has the wrong signature for a %s, want func %s(%s *testing.%s)", name, p.kind, name, strings.ToLower(p.typ), p.typ)
+			}
+			problems = append(problems, problem)
+			break
+		}
+	}
+	return problems
+}
+
+// exampleOutputRx matches the start of an output comment the way go test does
+// exampleOutputRx 以与 go test 相同的方式匹配输出注释的开头
+var exampleOutputRx = regexp.MustCompile(`(?i)^[[:space:]]*(unordered )?output:`)
+
+// exampleOutput returns how the output of an example is checked and its
+// output comment. As in go/doc, only the last comment group of the body
+// counts, so an output comment followed by another comment is ignored.
+// exampleOutput 返回示例输出的检查方式及其输出注释。与 go/doc 一致，只有函数体中的最后一个注释组有效，
+// 因此后面还有其他注释的输出注释会被忽略
+func exampleOutput(file *ast.File, body *ast.BlockStmt) (string, *ast.CommentGroup) {
+	var last *ast.CommentGroup
+	for _, group := range file.Comments {
+		if body.Lbrace < group.Pos() && group.End() <= body.Rbrace {
+			last = group
+		}
+	}
+	if last == nil {
+		return "", nil
+	}
+
+	match := exampleOutputRx.FindStringSubmatch(last.Text())
+	switch {
+	case match == nil:
+		return "", nil
+	case match[1] != "":
+		return ExampleOutputUnordered, last
+	}
+	return ExampleOutputOrdered, last
+}
+
+// isTestName reports whether name has the prefix and is not followed by a
+// lowercase rune, the way go test recognises TestXxx
+// isTestName 判断名称是否带有该前缀且前缀后不是小写字符，与 go test 识别 TestXxx 的方式一致
+func isTestName(name, prefix string) bool {
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return !unicode.IsLower(r)
+}
+
+// testingImportName returns the name the file uses for the testing
+// package, or "" when it is not imported
+// testingImportName 返回文件中 testing 包的引用名称，未导入时返回 ""
+func testingImportName(file *ast.File) string {
+	for _, imp := range file.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path != "testing" {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "testing"
+	}
+	return ""
+}
+
+// testingParam returns the parameter name of a function whose only
+// parameter is *testing.<typ>, or "" when the signature does not match
+// testingParam 返回唯一参数为 *testing.<typ> 的函数的参数名，签名不匹配时返回 ""
+func testingParam(fn *ast.FuncDecl, testing, typ string) string {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) > 1 || !isTestingPointer(params[0].Type, testing, typ) {
+		return ""
+	}
+	if len(params[0].Names) == 0 {
+		return "_"
+	}
+	return params[0].Names[0].Name
+}
+
+// isTestingPointer reports whether expr is *testing.<typ>, honouring a
+// renamed or dot import of the testing package
+// isTestingPointer 判断表达式是否为 *testing.<typ>，支持重命名导入和点导入
+func isTestingPointer(expr ast.Expr, testing, typ string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok || testing == "" {
+		return false
+	}
+	switch x := star.X.(type) {
+	case *ast.SelectorExpr:
+		pkg, ok := x.X.(*ast.Ident)
+		return ok && pkg.Name == testing && x.Sel.Name == typ
+	case *ast.Ident:
+		return testing == "." && x.Name == typ
+	}
+	return false
+}
+
+// findSubtests collects <param>.Run("name", func(...) {...}) calls in a
+// test body, recursing into the subtest functions. Subtests with computed
+// names (table-driven tests) cannot be run individually and are skipped.
+// findSubtests 收集测试函数体中 <param>.Run("name", func(...) {...}) 调用并递归进入子测试函数。
+// 名称为计算值的子测试（表驱动测试）无法单独运行，会被跳过
+func findSubtests(fset *token.FileSet, body *ast.BlockStmt, param, parent string) []Subtest {
+	subtests := []Subtest{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Run" {
+			return true
+		}
+		if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != param {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+
+		full := parent + "/" + name
+		subtests = append(subtests, Subtest{Name: full, Line: fset.Position(call.Pos()).Line})
+
+		// Nested subtests use the parameter of the subtest function
+		// 嵌套子测试使用子测试函数的参数
+		if fn, ok := call.Args[1].(*ast.FuncLit); ok && len(fn.Type.Params.List) == 1 && len(fn.Type.Params.List[0].Names) == 1 {
+			inner := fn.Type.Params.List[0].Names[0].Name
+			if inner != "_" {
+				subtests = append(subtests, findSubtests(fset, fn.Body, inner, full)...)
+			}
+			return false
+		}
+		return true
+	})
+	return subtests
+}
@@ -0,0 +1,144 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+	"syscall/js"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultTodoMarkers are the markers ScanTodos looks for without a marker list
+// defaultTodoMarkers 是未提供标记列表时 ScanTodos 查找的标记
+var defaultTodoMarkers = []string{"TODO", "FIXME", "HACK", "BUG"}
+
+// ScanTodos finds the comment lines of a Go file starting with a marker such
+// as TODO or FIXME.
+// Args: file content, JSON array of markers (optional, TODO, FIXME, HACK and BUG by default).
+// 查找 Go 文件中以 TODO、FIXME 等标记开头的注释行
+// 参数: 文件内容、标记的 JSON 数组（可选，默认为 TODO、FIXME、HACK 与 BUG）
+func ScanTodos(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	markers := defaultTodoMarkers
+	if len(args) > 1 && args[1].Truthy() {
+		if err := json.Unmarshal([]byte(args[1].String()), &markers); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse markers: %s", err.Error()))
+		}
+	}
+
+	src := args[0].String()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "x.go", src, parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse file: %s", err.Error()))
+	}
+
+	todos := []Todo{}
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			todos = append(todos, scanComment(runePosition(fset, src, c.Pos()), c.Text, markers)...)
+		}
+	}
+
+	result, err := json.Marshal(todos)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// Todo is a marked comment line
+// Todo 表示带标记的注释行
+type Todo struct {
+	Marker string `json:"marker"` // marker as listed, e.g. TODO or 待办
+	Author string `json:"author"` // name in parentheses after the marker, e.g. alice for TODO(alice):
+	Text   string `json:"text"`   // rest of the line after the marker and separator
+	Line   int    `json:"line"`   // 1-based line of the marker
+	Column int    `json:"column"` // 1-based rune column of the marker
+}
+
+// scanComment checks every line of a comment, so each line of a /* */ block
+// can carry its own marker. A marker is matched at the start of the line,
+// after the comment delimiter, a leading * and spaces, and must be followed
+// by an optional (author), then a colon, a space or the end of the line:
+// TODOS and TODO-list are not TODOs. CJK markers need no separator, since
+// text written in those scripts has no spaces between words.
+// scanComment 检查注释的每一行，因此 /* */ 块的每一行都可以有自己的标记。标记在行首匹配，即注释分隔符、行首的 *
+// 与空格之后，其后可以跟 (作者)，然后必须是冒号、空格或行尾：TODOS 与 TODO-list 不是 TODO。
+// 中日韩文字的标记不需要分隔符，因为这些文字的词之间没有空格
+func scanComment(start Position, text string, markers []string) []Todo {
+	var todos []Todo
+	for i, line := range strings.Split(text, "\n") {
+		column := 1
+		if i == 0 {
+			column = start.Column
+			line = line[2:] // the // or /* delimiter
+			column += 2
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		if i > 0 && strings.HasPrefix(trimmed, "*") && !strings.HasPrefix(trimmed, "*/") {
+			trimmed = strings.TrimLeft(trimmed[1:], " \t")
+		}
+		column += utf8.RuneCountInString(line) - utf8.RuneCountInString(trimmed)
+
+		for _, marker := range markers {
+			todo, ok := matchTodo(trimmed, marker)
+			if ok {
+				todo.Line, todo.Column = start.Line+i, column
+				todos = append(todos, todo)
+				break
+			}
+		}
+	}
+	return todos
+}
+
+// matchTodo matches a marker at the start of a comment line
+// matchTodo 在注释行的开头匹配标记
+func matchTodo(line, marker string) (Todo, bool) {
+	rest, ok := strings.CutPrefix(line, marker)
+	if marker == "" || !ok {
+		return Todo{}, false
+	}
+	rest = strings.TrimSuffix(strings.TrimRight(rest, " \t"), "*/")
+
+	todo := Todo{Marker: marker}
+	if strings.HasPrefix(rest, "(") {
+		author, after, found := strings.Cut(rest[1:], ")")
+		if !found {
+			return Todo{}, false
+		}
+		todo.Author, rest = strings.TrimSpace(author), after
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(marker)
+	next, size := utf8.DecodeRuneInString(rest)
+	switch {
+	case rest == "":
+	case next == ':' || next == '：':
+		rest = rest[size:]
+	case unicode.IsSpace(next):
+	case isCJK(last):
+	default:
+		return Todo{}, false
+	}
+
+	todo.Text = strings.TrimSpace(rest)
+	return todo, true
+}
+
+// isCJK reports whether a rune is a Chinese, Japanese or Korean character
+// isCJK 判断字符是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
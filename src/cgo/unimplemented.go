@@ -0,0 +1,145 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"syscall/js"
+)
+
+// UnimplementedInterfaces reports the exported interfaces of the workspace
+// that no concrete type implements.
+// Args: workspace files (JSON array of {path, content}), test files included.
+// 报告工作空间中没有任何具体类型实现的导出接口
+// 参数: 工作空间文件（{path, content} 的 JSON 数组），包括测试文件
+func UnimplementedInterfaces(this js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return createErrorJSON("no files provided")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+
+	idx := newImplIndex()
+	idx.update(files, nil)
+
+	result, err := json.Marshal(unimplementedInterfaces(idx))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// UnimplementedInterface is the declaration of an interface without implementers
+// UnimplementedInterface 表示没有实现类型的接口的声明
+type UnimplementedInterface struct {
+	Interface string `json:"interface"`
+	Package   string `json:"package"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+}
+
+// unimplementedInterfaces takes the interfaces of the implementation index
+// that have no implementer in any package, test packages included. An
+// interface used as a parameter or result type is kept: it describes what a
+// function accepts or returns, which callers outside the workspace may
+// implement.
+// unimplementedInterfaces 从实现索引中取出在任何包（包括测试包）中都没有实现类型的接口。用作参数或返回值类型的
+// 接口会被保留：它描述了函数接受或返回的内容，工作空间之外的调用方可能会实现它
+func unimplementedInterfaces(idx *implIndex) []UnimplementedInterface {
+	used := signatureTypes(idx.ws)
+	result := []UnimplementedInterface{}
+	for _, item := range idx.sorted() {
+		if len(item.Implementations) > 0 || !ast.IsExported(item.Interface) {
+			continue
+		}
+		obj := idx.ws.packages[item.Package].types.Scope().Lookup(item.Interface)
+		if used[obj] {
+			continue
+		}
+		result = append(result, UnimplementedInterface{
+			Interface: item.Interface,
+			Package:   item.Package,
+			Path:      item.Path,
+			Line:      item.Line,
+		})
+	}
+	return result
+}
+
+// signatureTypes collects the named types mentioned by the parameters and
+// results of the functions and methods declared in the workspace, such as
+// Greeter in func Greet(gs []Greeter). Methods of an interface mentioning the
+// interface itself do not count, or a fluent interface would keep itself.
+// signatureTypes 收集工作空间中声明的函数和方法的参数与返回值所引用的命名类型，例如 func Greet(gs []Greeter) 中的
+// Greeter。接口方法引用接口自身时不计入，否则链式调用的接口会让自己被保留
+func signatureTypes(ws *workspace) map[types.Object]bool {
+	used := map[types.Object]bool{}
+	for _, pkg := range ws.sortedPackages() {
+		if pkg.info == nil {
+			continue
+		}
+		for _, def := range pkg.info.Defs {
+			fn, ok := def.(*types.Func)
+			if !ok {
+				continue
+			}
+			sig := fn.Type().(*types.Signature)
+			var owner types.Object
+			if recv := sig.Recv(); recv != nil {
+				if named, ok := recv.Type().(*types.Named); ok && types.IsInterface(named) {
+					owner = named.Obj()
+				}
+			}
+			for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+				for i := 0; i < tuple.Len(); i++ {
+					markNamedTypes(tuple.At(i).Type(), owner, used, map[types.Type]bool{})
+				}
+			}
+		}
+	}
+	return used
+}
+
+// markNamedTypes marks the named types a type is built from, except owner
+// markNamedTypes 标记构成类型的命名类型，owner 除外
+func markNamedTypes(t types.Type, owner types.Object, used map[types.Object]bool, seen map[types.Type]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	switch t := t.(type) {
+	case *types.Named:
+		if t.Obj() != owner {
+			used[t.Obj()] = true
+		}
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			markNamedTypes(t.TypeArgs().At(i), owner, used, seen)
+		}
+	case *types.Pointer:
+		markNamedTypes(t.Elem(), owner, used, seen)
+	case *types.Slice:
+		markNamedTypes(t.Elem(), owner, used, seen)
+	case *types.Array:
+		markNamedTypes(t.Elem(), owner, used, seen)
+	case *types.Chan:
+		markNamedTypes(t.Elem(), owner, used, seen)
+	case *types.Map:
+		markNamedTypes(t.Key(), owner, used, seen)
+		markNamedTypes(t.Elem(), owner, used, seen)
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				markNamedTypes(tuple.At(i).Type(), owner, used, seen)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ParseVendorModules parses vendor/modules.txt, the list of vendored
+// modules written by go mod vendor.
+// Args: modules.txt content.
+// 解析 go mod vendor 生成的已 vendor 模块列表 vendor/modules.txt
+// 参数: modules.txt 内容
+func ParseVendorModules(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+
+	result, err := json.Marshal(parseVendorModules(args[0].String()))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// VendorModule is a module listed in vendor/modules.txt
+// VendorModule 表示 vendor/modules.txt 中列出的模块
+type VendorModule struct {
+	Path           string   `json:"path"`
+	Version        string   `json:"version"`        // empty for a module replaced at all versions
+	ReplacePath    string   `json:"replacePath"`    // replacement after =>, empty if not replaced
+	ReplaceVersion string   `json:"replaceVersion"` // empty for directory replacements
+	Explicit       bool     `json:"explicit"`       // "## explicit", required by go.mod
+	GoVersion      string   `json:"goVersion"`      // from "## go 1.x", the go directive of the module
+	Packages       []string `json:"packages"`       // vendored packages of the module
+	Line           int      `json:"line"`           // 1-based line of the "# module" header
+}
+
+// parseVendorModules follows the parser of the go command: a "# path
+// [version] [=> path [version]]" header starts a module, "## " lines hold
+// annotations separated by ";", and the other lines name its packages.
+// Unknown annotations and lines that fit none of these are skipped, as newer
+// go versions may add them.
+// parseVendorModules 与 go 命令的解析方式一致："# path [version] [=> path [version]]" 开始一个模块，
+// "## " 行包含以 ";" 分隔的注解，其余行为该模块的包。未知注解以及不符合上述格式的行会被跳过，
+// 因为更新的 go 版本可能会添加它们
+func parseVendorModules(content string) []VendorModule {
+	modules := []VendorModule{}
+	var current *VendorModule
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "## "):
+			if current == nil {
+				continue
+			}
+			for _, annotation := range strings.Split(line[len("## "):], ";") {
+				annotation = strings.TrimSpace(annotation)
+				if annotation == "explicit" {
+					current.Explicit = true
+				} else if version, ok := strings.CutPrefix(annotation, "go "); ok {
+					current.GoVersion = strings.TrimSpace(version)
+				}
+			}
+
+		case strings.HasPrefix(line, "# "):
+			current = nil
+			f := strings.Fields(line[len("# "):])
+			if len(f) < 2 {
+				continue
+			}
+			mod := VendorModule{Path: f[0], Packages: []string{}, Line: i + 1}
+			if semver.IsValid(f[1]) {
+				mod.Version = f[1]
+				f = f[2:]
+			} else {
+				f = f[1:]
+			}
+			if len(f) > 0 && f[0] == "=>" {
+				switch {
+				case len(f) == 2:
+					mod.ReplacePath = f[1]
+				case len(f) == 3 && semver.IsValid(f[2]):
+					mod.ReplacePath, mod.ReplaceVersion = f[1], f[2]
+				default:
+					continue
+				}
+			} else if len(f) > 0 {
+				continue
+			}
+			modules = append(modules, mod)
+			current = &modules[len(modules)-1]
+
+		default:
+			f := strings.Fields(line)
+			if current != nil && len(f) == 1 && module.CheckImportPath(f[0]) == nil {
+				current.Packages = append(current.Packages, f[0])
+			}
+		}
+	}
+	return modules
+}
@@ -0,0 +1,354 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// CheckGoVersion reports whether a target Go version satisfies the go
+// directive of a go.mod file (target >= declared).
+// Args: go.mod content, target Go version (e.g. 1.22.3 or go1.22.3).
+// 检查目标 Go 版本是否满足 go.mod 的 go 指令（目标版本 >= 声明版本）
+// 参数: go.mod 内容、目标 Go 版本（如 1.22.3 或 go1.22.3）
+func CheckGoVersion(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+	if len(args) < 2 {
+		return createErrorJSON("target go version is required")
+	}
+
+	target := strings.TrimPrefix(args[1].String(), "go")
+	if !modfile.GoVersionRE.MatchString(target) {
+		return createErrorJSON(fmt.Sprintf("invalid target go version: %s", args[1].String()))
+	}
+
+	// A module without a go directive has no requirement to satisfy
+	// 没有 go 指令的模块没有需要满足的版本要求
+	check := GoVersionCheck{Target: target, Compatible: true}
+	if modFile.Go != nil {
+		check.Declared = modFile.Go.Version
+		check.Compatible = compareGoVersions(target, check.Declared) >= 0
+	}
+
+	result, err := json.Marshal(check)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// GoVersionCheck is the result of CheckGoVersion
+// GoVersionCheck 是 CheckGoVersion 的结果
+type GoVersionCheck struct {
+	Declared   string `json:"declared"` // go directive in go.mod
+	Target     string `json:"target"`
+	Compatible bool   `json:"compatible"`
+}
+
+// MinGoVersion reports the minimum Go version implied by the directives
+// of a go.mod file and whether the go directive satisfies it.
+// Args: go.mod content.
+// 报告 go.mod 中的指令所要求的最低 Go 版本，以及 go 指令是否满足该版本
+// 参数: go.mod 内容
+func MinGoVersion(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+
+	result, err := json.Marshal(minGoVersion(modFile))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// GoVersionRequirement is the result of MinGoVersion
+// GoVersionRequirement 是 MinGoVersion 的结果
+type GoVersionRequirement struct {
+	Declared   string                 `json:"declared"`  // go directive, "" when missing
+	Effective  string                 `json:"effective"` // declared version, 1.16 when missing
+	Minimum    string                 `json:"minimum"`   // "" when no directive needs a newer Go
+	Satisfied  bool                   `json:"satisfied"`
+	Directives []DirectiveRequirement `json:"directives"` // ordered by line
+}
+
+// DirectiveRequirement is a directive that needs a minimum Go version
+// DirectiveRequirement 表示需要最低 Go 版本的指令
+type DirectiveRequirement struct {
+	Directive string `json:"directive"`
+	Version   string `json:"version"`
+	Line      int    `json:"line"` // 1-based line of its first use
+}
+
+// directiveGoVersions are the Go versions that introduced go.mod directives
+// directiveGoVersions 是引入各 go.mod 指令的 Go 版本
+var directiveGoVersions = map[string]string{
+	"retract":   "1.16",
+	"toolchain": "1.21",
+	"godebug":   "1.23",
+	"tool":      "1.24",
+	"ignore":    "1.25",
+}
+
+// defaultGoVersion is the version the go command assumes for a main module
+// without a go directive
+// defaultGoVersion 是主模块没有 go 指令时 go 命令假定的版本
+const defaultGoVersion = "1.16"
+
+// minGoVersion lists the first use of every directive newer than go 1.16
+// and the highest version they need. That version is compared with the
+// declared one, or with 1.16 when the go directive is missing, which is what
+// the go command assumes then.
+// minGoVersion 列出每个比 go 1.16 新的指令的首次使用位置，以及它们所需的最高版本。
+// 所需版本与声明的版本比较；缺少 go 指令时与 1.16 比较，这正是 go 命令此时假定的版本
+func minGoVersion(modFile *modfile.File) GoVersionRequirement {
+	requirement := GoVersionRequirement{Effective: defaultGoVersion, Satisfied: true, Directives: []DirectiveRequirement{}}
+	if modFile.Go != nil {
+		requirement.Declared = modFile.Go.Version
+		requirement.Effective = modFile.Go.Version
+	}
+
+	for _, stmt := range modFile.Syntax.Stmt {
+		var verb string
+		var line int
+		switch stmt := stmt.(type) {
+		case *modfile.Line:
+			verb, line = stmt.Token[0], stmt.Start.Line
+		case *modfile.LineBlock:
+			verb, line = stmt.Token[0], stmt.Start.Line
+		}
+		version, ok := directiveGoVersions[verb]
+		if !ok || slices.ContainsFunc(requirement.Directives, func(d DirectiveRequirement) bool { return d.Directive == verb }) {
+			continue
+		}
+
+		requirement.Directives = append(requirement.Directives, DirectiveRequirement{Directive: verb, Version: version, Line: line})
+		if requirement.Minimum == "" || compareGoVersions(version, requirement.Minimum) > 0 {
+			requirement.Minimum = version
+		}
+	}
+
+	if requirement.Minimum != "" {
+		requirement.Satisfied = compareGoVersions(requirement.Effective, requirement.Minimum) >= 0
+	}
+	return requirement
+}
+
+// CompareVersions compares the version of a require with another module
+// version, typically the latest one from the module proxy.
+// Args: current version, other version.
+// 比较依赖的版本与另一个模块版本（通常是模块代理返回的最新版本）
+// 参数: 当前版本、另一个版本
+func CompareVersions(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return createErrorJSON("two versions are required")
+	}
+	current, other := args[0].String(), args[1].String()
+	for _, v := range []string{current, other} {
+		if !semver.IsValid(v) {
+			return createErrorJSON(fmt.Sprintf("invalid version: %s", v))
+		}
+	}
+
+	// Pseudo-versions are prereleases and sort the way the go command orders
+	// them: v1.3.1-0.20230101120000-abcdef123456 is after v1.3.0 and before
+	// v1.3.1. "+incompatible" is build metadata, which semver ignores, so
+	// v2.0.0+incompatible equals v2.0.0.
+	// 伪版本是预发布版本，排序与 go 命令一致：v1.3.1-0.20230101120000-abcdef123456 在 v1.3.0
+	// 之后、v1.3.1 之前。"+incompatible" 是构建元数据，semver 比较时忽略，因此 v2.0.0+incompatible 等于 v2.0.0
+	cmp := semver.Compare(current, other)
+	result, err := json.Marshal(VersionComparison{Result: cmp, Upgrade: cmp < 0})
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// VersionComparison is the result of CompareVersions
+// VersionComparison 是 CompareVersions 的结果
+type VersionComparison struct {
+	Result  int  `json:"result"`  // -1, 0 or +1 comparing current with other
+	Upgrade bool `json:"upgrade"` // other is newer than current
+}
+
+// FilterExcludedVersions removes the versions excluded by a go.mod from a
+// list of upgrade candidates.
+// Args: go.mod content, JSON object mapping module paths to candidate versions.
+// 从升级候选版本中移除 go.mod 排除的版本
+// 参数: go.mod 内容、模块路径到候选版本列表的 JSON 对象
+func FilterExcludedVersions(this js.Value, args []js.Value) any {
+	modFile, errJSON := parseModArg(args)
+	if errJSON != "" {
+		return errJSON
+	}
+
+	candidates := map[string][]string{}
+	if len(args) > 1 && args[1].Truthy() {
+		if err := json.Unmarshal([]byte(args[1].String()), &candidates); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse candidates: %s", err.Error()))
+		}
+	}
+
+	result, err := json.Marshal(filterExcludedVersions(modFile, candidates))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// ExcludedVersions lists, for one module, the candidate versions that can be
+// selected and the versions excluded by the go.mod
+// ExcludedVersions 列出一个模块可以选择的候选版本以及 go.mod 排除的版本
+type ExcludedVersions struct {
+	Path       string   `json:"path"`
+	Current    string   `json:"current"`    // required version, "" when not required
+	Candidates []string `json:"candidates"` // candidates surviving the excludes, in input order
+	Excluded   []string `json:"excluded"`   // every version excluded for the module, sorted
+	Upgrade    string   `json:"upgrade"`    // newest surviving candidate newer than current, "" without one
+}
+
+// filterExcludedVersions reports every module that has candidates or excludes,
+// sorted by path. Candidates must equal an excluded version exactly to be
+// dropped: the go command does not treat v1.2.0 and v1.2.0+incompatible as
+// the same exclude.
+// filterExcludedVersions 报告每个有候选版本或排除项的模块，按路径排序。候选版本必须与排除版本完全相同才会被移除:
+// go 命令不会把 v1.2.0 与 v1.2.0+incompatible 视为同一个排除项
+func filterExcludedVersions(modFile *modfile.File, candidates map[string][]string) []ExcludedVersions {
+	excluded := map[string][]string{}
+	for _, exc := range modFile.Exclude {
+		excluded[exc.Mod.Path] = append(excluded[exc.Mod.Path], exc.Mod.Version)
+	}
+	current := map[string]string{}
+	for _, req := range modFile.Require {
+		current[req.Mod.Path] = req.Mod.Version
+	}
+
+	paths := make([]string, 0, len(candidates)+len(excluded))
+	for p := range candidates {
+		paths = append(paths, p)
+	}
+	for p := range excluded {
+		if _, ok := candidates[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	slices.Sort(paths)
+
+	modules := []ExcludedVersions{}
+	for _, p := range paths {
+		versions := ExcludedVersions{Path: p, Current: current[p], Candidates: []string{}, Excluded: []string{}}
+		versions.Excluded = append(versions.Excluded, excluded[p]...)
+		semver.Sort(versions.Excluded)
+		versions.Excluded = slices.Compact(versions.Excluded)
+
+		for _, v := range candidates[p] {
+			if slices.Contains(versions.Excluded, v) {
+				continue
+			}
+			versions.Candidates = append(versions.Candidates, v)
+			if !semver.IsValid(v) || (versions.Current != "" && semver.Compare(v, versions.Current) <= 0) {
+				continue
+			}
+			if versions.Upgrade == "" || semver.Compare(v, versions.Upgrade) > 0 {
+				versions.Upgrade = v
+			}
+		}
+		modules = append(modules, versions)
+	}
+	return modules
+}
+
+// compareGoVersions compares two Go versions the way the go command does:
+// 1.21 < 1.21rc1 < 1.21.0 < 1.21.1. Both versions must match
+// modfile.GoVersionRE; the result is -1, 0 or +1.
+// compareGoVersions 按 go 命令的规则比较两个 Go 版本: 1.21 < 1.21rc1 < 1.21.0 < 1.21.1。
+// 两个版本都必须匹配 modfile.GoVersionRE，结果为 -1、0 或 +1
+func compareGoVersions(a, b string) int {
+	va, vb := parseGoVersion(a), parseGoVersion(b)
+	if c := compareInts(va.major, vb.major); c != 0 {
+		return c
+	}
+	if c := compareInts(va.minor, vb.minor); c != 0 {
+		return c
+	}
+	if c := compareInts(va.stage, vb.stage); c != 0 {
+		return c
+	}
+	return compareInts(va.number, vb.number)
+}
+
+// goVersion is a parsed Go version. stage orders the release stages of a
+// minor version: language version, alpha, beta, rc, then patch releases;
+// number is the prerelease or patch number within the stage.
+// goVersion 是解析后的 Go 版本。stage 表示小版本的发布阶段顺序:
+// 语言版本、alpha、beta、rc、补丁版本；number 是阶段内的预发布号或补丁号
+type goVersion struct {
+	major, minor  int
+	stage, number int
+}
+
+// Release stages in increasing order
+// 按从低到高排列的发布阶段
+var goVersionStages = map[string]int{"": 0, "alpha": 1, "beta": 2, "rc": 3}
+
+const goVersionStageRelease = 4
+
+// parseGoVersion splits a version matching modfile.GoVersionRE
+// parseGoVersion 拆分匹配 modfile.GoVersionRE 的版本
+func parseGoVersion(v string) goVersion {
+	var parsed goVersion
+	major, rest, _ := strings.Cut(v, ".")
+	parsed.major, _ = strconv.Atoi(major)
+
+	// Split the minor number from a patch or prerelease suffix
+	// 将小版本号与补丁号或预发布后缀分开
+	i := 0
+	for i < len(rest) && '0' <= rest[i] && rest[i] <= '9' {
+		i++
+	}
+	parsed.minor, _ = strconv.Atoi(rest[:i])
+	rest = rest[i:]
+
+	switch {
+	case strings.HasPrefix(rest, "."):
+		parsed.stage = goVersionStageRelease
+		parsed.number, _ = strconv.Atoi(rest[1:])
+	case rest != "":
+		j := strings.IndexAny(rest, "0123456789")
+		if j < 0 {
+			j = len(rest)
+		}
+		parsed.stage = goVersionStages[rest[:j]]
+		parsed.number, _ = strconv.Atoi(rest[j:])
+	}
+	return parsed
+}
+
+// compareInts returns -1, 0 or +1
+// compareInts 返回 -1、0 或 +1
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
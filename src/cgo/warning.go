@@ -0,0 +1,262 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Warning kinds reported in ModFile.Warnings
+// ModFile.Warnings 中报告的警告类型
+const (
+	WarningDuplicateRequire = "duplicate-require"
+	WarningMalformedGodebug = "malformed-godebug"
+	WarningInvalidToolchain = "invalid-toolchain"
+	WarningToolchainBelowGo = "toolchain-below-go"
+	WarningInvalidExclude   = "invalid-exclude"
+	WarningReplaceCycle     = "replace-cycle"
+	WarningReplaceDowngrade = "replace-downgrade"
+	WarningDirectiveVersion = "directive-version"
+	WarningMissingGo        = "missing-go-directive"
+	WarningExcludedRequire  = "excluded-require" // reported by AddExclude
+	WarningMalformedSum     = "malformed-sum"    // reported by CheckSum for go.sum lines
+	WarningMissingLicense   = "missing-license"  // reported by JoinLicenses
+	WarningFlaggedLicense   = "flagged-license"  // reported by JoinLicenses
+)
+
+// Warning is a problem found in go.mod that does not prevent parsing
+// Warning 表示 go.mod 中不影响解析的问题
+type Warning struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Line    int    `json:"line"` // 1-based line of the offending entry
+}
+
+// checkDuplicateRequires reports every require after the first one for the
+// same module path. Versions are not compared, so v2.0.0 and
+// v2.0.0+incompatible of the same path are still duplicates.
+// checkDuplicateRequires 对同一模块路径第一次之后的每个 require 报告警告。
+// 不比较版本，因此同一路径的 v2.0.0 与 v2.0.0+incompatible 仍视为重复
+func checkDuplicateRequires(modFile *modfile.File) []Warning {
+	var warnings []Warning
+	first := make(map[string]*modfile.Require)
+	for _, req := range modFile.Require {
+		prev, ok := first[req.Mod.Path]
+		if !ok {
+			first[req.Mod.Path] = req
+			continue
+		}
+
+		start, _ := linePosition(req.Syntax)
+		prevStart, _ := linePosition(prev.Syntax)
+		warnings = append(warnings, Warning{
+			Kind: WarningDuplicateRequire,
+			Message: fmt.Sprintf("%s %s is already required at line %d (%s)",
+				req.Mod.Path, req.Mod.Version, prevStart.Line, prev.Mod.Version),
+			Line: start.Line,
+		})
+	}
+	return warnings
+}
+
+// invalidExcludeMessage describes an exclude line whose version modfile
+// rejected. Pre-release and build metadata suffixes are valid semver and
+// modfile accepts them; a version that is valid semver but still rejected
+// is reported as non-canonical rather than invalid.
+// invalidExcludeMessage 描述版本被 modfile 拒绝的 exclude 行。预发布与构建元数据后缀是合法的 semver，
+// modfile 会接受它们；合法的 semver 仍被拒绝时报告为非规范版本而不是无效版本
+func invalidExcludeMessage(modPath, line string) string {
+	fields := strings.Fields(strings.TrimPrefix(line, "exclude"))
+	version := ""
+	if len(fields) > 1 {
+		version = fields[1]
+	}
+	if semver.IsValid(version) {
+		return fmt.Sprintf("exclude %s %s is not a canonical version", modPath, version)
+	}
+	return fmt.Sprintf("exclude %s has invalid version %q: must be a semantic version such as v1.2.3, the exclude has no effect", modPath, version)
+}
+
+// checkReplaceDowngrades reports self-replaces (A => A vX) pinning a module
+// below the version this go.mod requires, which drops the API that code
+// written against the required version may use. semver.Compare orders
+// pseudo-versions by the version they are based on, so
+// v1.2.4-0.20240101000000-abcdef123456 is newer than v1.2.3 and older than v1.2.4.
+// Replacements by another module path are forks with versions of their own
+// and are not compared.
+// checkReplaceDowngrades 报告将模块固定到低于本 go.mod 所需版本的自我替换（A => A vX），
+// 这会去掉按所需版本编写的代码可能使用的 API。semver.Compare 按伪版本所基于的版本排序，
+// 因此 v1.2.4-0.20240101000000-abcdef123456 比 v1.2.3 新、比 v1.2.4 旧。
+// 替换为其他模块路径时属于拥有独立版本的 fork，不进行比较
+func checkReplaceDowngrades(modFile *modfile.File) []Warning {
+	// A versioned replace takes precedence over the wildcard one of its path
+	// 带版本的 replace 优先于同一路径的通配 replace
+	versioned := make(map[module.Version]bool)
+	for _, rep := range modFile.Replace {
+		if rep.Old.Version != "" {
+			versioned[rep.Old] = true
+		}
+	}
+
+	var warnings []Warning
+	for _, rep := range modFile.Replace {
+		if rep.New.Path != rep.Old.Path || rep.New.Version == "" {
+			continue
+		}
+		for _, req := range modFile.Require {
+			// A versioned replace only applies to that version
+			// 带版本的 replace 只作用于该版本
+			if req.Mod.Path != rep.Old.Path || (rep.Old.Version != "" && rep.Old.Version != req.Mod.Version) {
+				continue
+			}
+			if rep.Old.Version == "" && versioned[req.Mod] {
+				continue
+			}
+			if semver.Compare(rep.New.Version, req.Mod.Version) >= 0 {
+				continue
+			}
+
+			start, _ := linePosition(rep.Syntax)
+			reqStart, _ := linePosition(req.Syntax)
+			warnings = append(warnings, Warning{
+				Kind: WarningReplaceDowngrade,
+				Message: fmt.Sprintf("replace pins %s to %s, older than %s required at line %d",
+					rep.Old.Path, rep.New.Version, req.Mod.Version, reqStart.Line),
+				Line: start.Line,
+			})
+		}
+	}
+	return warnings
+}
+
+// checkReplaceCycles reports groups of modules whose replace directives
+// point at each other, such as A => B with B => A. The go command does not
+// apply replacements transitively, so a cycle resolves differently from what
+// it suggests and is usually a mistake. A self-replace (A => A vX) pins a
+// version and local directory replacements end the chain, so neither forms
+// an edge. Each group is reported once, at its first replace line.
+// checkReplaceCycles 报告 replace 指令相互指向的模块组，例如 A => B 与 B => A。
+// go 命令不会传递地应用替换，因此循环的实际解析结果与其字面含义不同，通常是错误。
+// 自我替换（A => A vX）用于固定版本，本地目录替换则终止替换链，二者都不构成边。
+// 每个模块组只在其第一条 replace 行报告一次
+func checkReplaceCycles(modFile *modfile.File) []Warning {
+	edges := make(map[string][]string)
+	lines := make(map[string]int) // module path -> first replace line
+	for _, rep := range modFile.Replace {
+		if replaceKind(rep) == ReplaceKindLocal || rep.New.Path == rep.Old.Path {
+			continue
+		}
+		edges[rep.Old.Path] = append(edges[rep.Old.Path], rep.New.Path)
+		if start, _ := linePosition(rep.Syntax); lines[rep.Old.Path] == 0 || start.Line < lines[rep.Old.Path] {
+			lines[rep.Old.Path] = start.Line
+		}
+	}
+
+	var warnings []Warning
+	for _, group := range stronglyConnected(edges) {
+		if len(group) < 2 {
+			continue
+		}
+		line := 0
+		for _, path := range group {
+			if l := lines[path]; l > 0 && (line == 0 || l < line) {
+				line = l
+			}
+		}
+		warnings = append(warnings, Warning{
+			Kind:    WarningReplaceCycle,
+			Message: fmt.Sprintf("replace directives form a cycle between %s", strings.Join(group, ", ")),
+			Line:    line,
+		})
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Line < warnings[j].Line })
+	return warnings
+}
+
+// stronglyConnected returns the strongly connected components of a graph
+// with Tarjan's algorithm, each sorted by node
+// stronglyConnected 使用 Tarjan 算法返回图的强连通分量，每个分量按节点排序
+func stronglyConnected(edges map[string][]string) [][]string {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var groups [][]string
+
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = len(index)
+		low[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range edges[node] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				low[node] = min(low[node], low[next])
+			} else if onStack[next] {
+				low[node] = min(low[node], index[next])
+			}
+		}
+
+		if low[node] == index[node] {
+			var group []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				group = append(group, top)
+				if top == node {
+					break
+				}
+			}
+			sort.Strings(group)
+			groups = append(groups, group)
+		}
+	}
+
+	nodes := make([]string, 0, len(edges))
+	for node := range edges {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		if _, ok := index[node]; !ok {
+			visit(node)
+		}
+	}
+	return groups
+}
+
+// checkDirectiveVersions reports every directive that needs a newer Go than
+// the go directive declares, e.g. tool with go 1.22: older go commands fail
+// to parse such a go.mod.
+// checkDirectiveVersions 报告所需 Go 版本高于 go 指令声明版本的指令，例如 go 1.22 下的 tool：
+// 较旧的 go 命令无法解析这样的 go.mod
+func checkDirectiveVersions(modFile *modfile.File) []Warning {
+	requirement := minGoVersion(modFile)
+	declared := "go " + requirement.Effective
+	if requirement.Declared == "" {
+		declared = "no go directive (go " + defaultGoVersion + " assumed)"
+	}
+
+	var warnings []Warning
+	for _, directive := range requirement.Directives {
+		if compareGoVersions(requirement.Effective, directive.Version) >= 0 {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Kind:    WarningDirectiveVersion,
+			Message: fmt.Sprintf("%s directive requires go %s or later, go.mod declares %s", directive.Directive, directive.Version, declared),
+			Line:    directive.Line,
+		})
+	}
+	return warnings
+}
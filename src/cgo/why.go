@@ -0,0 +1,147 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// ExplainRequire explains why the main module needs a module, like go mod
+// why -m, from pre-fetched go.mod files.
+// Args: JSON object mapping module@version to go.mod content, main go.mod
+// content, target module path.
+// 根据预先获取的 go.mod 文件解释主模块为什么需要某个模块，类似 go mod why -m
+// 参数: module@version 到 go.mod 内容的 JSON 对象、主模块 go.mod 内容、目标模块路径
+func ExplainRequire(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return createErrorJSON("go.mod contents, main go.mod and target module are required")
+	}
+
+	var mods map[string]string
+	if err := json.Unmarshal([]byte(args[0].String()), &mods); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse go.mod contents: %s", err.Error()))
+	}
+
+	mainFile, errJSON := parseModArg(args[1:])
+	if errJSON != "" {
+		return errJSON
+	}
+	if mainFile.Module == nil {
+		return createErrorJSON("main go.mod has no module directive")
+	}
+
+	result, err := json.Marshal(explainRequire(mods, mainFile, args[2].String()))
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// RequireExplanation is the result of ExplainRequire
+// RequireExplanation 是 ExplainRequire 的结果
+type RequireExplanation struct {
+	Target   string         `json:"target"`
+	Required bool           `json:"required"` // false when no direct dependency leads to the target
+	Chains   []RequireChain `json:"chains"`   // sorted by length, then by Via
+	Missing  []string       `json:"missing"`  // go.mod files not supplied, chains through them are unknown
+}
+
+// RequireChain is a shortest chain of requires from the main module to the target
+// RequireChain 表示从主模块到目标模块的最短 require 链
+type RequireChain struct {
+	Via  string   `json:"via"`  // direct dependency of the main module that introduces the target
+	Path []string `json:"path"` // main module, then module@version up to the target
+}
+
+// explainRequire searches breadth-first from each direct require of the
+// main module, so every introducing direct dependency gets its own shortest
+// chain. Each go.mod is parsed once across the searches.
+// explainRequire 从主模块的每个直接依赖出发广度优先搜索，因此每个引入目标的直接依赖
+// 都有各自的最短链。每个 go.mod 在所有搜索中只解析一次
+func explainRequire(mods map[string]string, mainFile *modfile.File, target string) RequireExplanation {
+	explanation := RequireExplanation{Target: target, Chains: []RequireChain{}, Missing: []string{}}
+	mainPath := mainFile.Module.Mod.Path
+
+	parsed := make(map[module.Version][]module.Version)
+	missing := make(map[string]bool)
+	requires := func(mod module.Version) []module.Version {
+		if reqs, ok := parsed[mod]; ok {
+			return reqs
+		}
+		parsed[mod] = nil
+		content, ok := mods[mod.Path+"@"+mod.Version]
+		if !ok {
+			missing[mod.Path+"@"+mod.Version] = true
+			return nil
+		}
+		modFile, err := modfile.ParseLax("go.mod", []byte(content), nil)
+		if err != nil {
+			return nil
+		}
+		for _, req := range modFile.Require {
+			parsed[mod] = append(parsed[mod], req.Mod)
+		}
+		return parsed[mod]
+	}
+
+	for _, req := range mainFile.Require {
+		if req.Indirect {
+			continue
+		}
+		if req.Mod.Path == target {
+			explanation.Chains = append(explanation.Chains, RequireChain{
+				Via:  target,
+				Path: []string{mainPath, req.Mod.String()},
+			})
+			continue
+		}
+
+		// parent records the module each one was first reached from
+		// parent 记录每个模块第一次被哪个模块引入
+		parent := map[module.Version]module.Version{req.Mod: {}}
+		queue := []module.Version{req.Mod}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			if current.Path == target {
+				chain := []string{}
+				for mod := current; mod.Path != ""; mod = parent[mod] {
+					chain = append([]string{mod.String()}, chain...)
+				}
+				explanation.Chains = append(explanation.Chains, RequireChain{
+					Via:  req.Mod.Path,
+					Path: append([]string{mainPath}, chain...),
+				})
+				break
+			}
+			for _, next := range requires(current) {
+				if _, seen := parent[next]; !seen && next.Path != mainPath {
+					parent[next] = current
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(explanation.Chains, func(i, j int) bool {
+		a, b := explanation.Chains[i], explanation.Chains[j]
+		if len(a.Path) != len(b.Path) {
+			return len(a.Path) < len(b.Path)
+		}
+		return a.Via < b.Via
+	})
+	for key := range missing {
+		explanation.Missing = append(explanation.Missing, key)
+	}
+	sort.Strings(explanation.Missing)
+	explanation.Required = len(explanation.Chains) > 0
+	return explanation
+}
@@ -0,0 +1,128 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ParseWork parses a go.work file and returns the result as JSON.
+// Args: go.work content, mode (optional): "use" returns only the directories
+// of the use directives as a JSON array of strings, which is enough for the
+// first render of a workspace with hundreds of modules; the full WorkFile by
+// default.
+// 解析 go.work 文件并以 JSON 形式返回结果
+// 参数: go.work 内容、模式（可选）："use" 只以字符串 JSON 数组返回 use 指令的目录，足以完成包含数百个模块的
+// 工作区的首次渲染；默认返回完整的 WorkFile
+func ParseWork(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return createErrorJSON("no content provided")
+	}
+	content := args[0].String()
+	mode := ""
+	if len(args) > 1 && args[1].Truthy() {
+		mode = args[1].String()
+	}
+	if mode != "" && mode != WorkModeUse {
+		return createErrorJSON(fmt.Sprintf("unknown mode: %s", mode))
+	}
+
+	// Check if file is empty
+	// 检查文件是否为空
+	if len(content) == 0 {
+		return createErrorJSON("go.work file is empty")
+	}
+
+	// Parse go.work file
+	// 解析 go.work 文件
+	workFile, err := modfile.ParseWork("go.work", []byte(content), nil)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse go.work: %s", err.Error()))
+	}
+
+	var result []byte
+	if mode == WorkModeUse {
+		result, err = json.Marshal(workUseDirs(workFile))
+	} else {
+		result, err = json.Marshal(createWorkInfo(workFile))
+	}
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// WorkModeUse is the ParseWork mode returning only the use directories
+// WorkModeUse 是 ParseWork 只返回 use 目录的模式
+const WorkModeUse = "use"
+
+// workUseDirs returns the directories of the use directives in file order,
+// skipping the positions, module paths and replaces of the full result
+// workUseDirs 按文件顺序返回 use 指令的目录，跳过完整结果中的位置、模块路径与 replace
+func workUseDirs(workFile *modfile.WorkFile) []string {
+	dirs := make([]string, 0, len(workFile.Use))
+	for _, use := range workFile.Use {
+		dirs = append(dirs, use.Path)
+	}
+	return dirs
+}
+
+// createWorkInfo converts a parsed go.work file into WorkFile
+// createWorkInfo 将解析后的 go.work 文件转换为 WorkFile
+func createWorkInfo(workFile *modfile.WorkFile) *WorkFile {
+	workInfo := &WorkFile{}
+
+	// Set Go version if available
+	// 设置 Go 版本（如果可用）
+	if workFile.Go != nil {
+		workInfo.Go = workFile.Go.Version
+	}
+
+	// Set toolchain if available
+	// 设置工具链（如果可用）
+	if workFile.Toolchain != nil {
+		workInfo.Toolchain = workFile.Toolchain.Name
+	}
+
+	// Process use directives
+	// 处理 use 指令
+	for _, use := range workFile.Use {
+		start, end := linePosition(use.Syntax)
+		workInfo.Use = append(workInfo.Use, Use{
+			Path:       use.Path,
+			ModulePath: use.ModulePath,
+			Start:      start,
+			End:        end,
+		})
+	}
+
+	// Process replaced modules
+	// 处理替换的模块
+	for _, rep := range workFile.Replace {
+		workInfo.Replace = append(workInfo.Replace, newReplaceInfo(rep))
+	}
+
+	return workInfo
+}
+
+// Use is a use directive in go.work
+// Use 表示 go.work 中的 use 指令
+type Use struct {
+	Path       string   `json:"path"`       // use ./api
+	ModulePath string   `json:"modulePath"` // module path declared in the used directory, if known
+	Start      Position `json:"start"`
+	End        Position `json:"end"`
+}
+
+type WorkFile struct {
+	Go        string        `json:"go"`        // go 1.21
+	Toolchain string        `json:"toolchain"` // toolchain go1.21
+	Use       []Use         `json:"use"`       // use ./api
+	Replace   []ReplaceInfo `json:"replace"`
+}
@@ -0,0 +1,290 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"syscall/js"
+
+	"golang.org/x/mod/modfile"
+)
+
+// FindWorkspaceCycles reports workspace modules that depend on each other.
+// Requires are resolved the way the go command does in workspace mode:
+// a workspace module is used for its own path, and other paths go through
+// the replaces of go.work and then of the members, so a local replace
+// pointing at a member makes an edge too. Unlike the replace-cycle warning,
+// which only follows replace directives within one go.mod, this follows
+// requires across modules.
+// Args: JSON array of {path, content} of the members' go.mod files, go.work
+// as a JSON {path, content} object (optional).
+// 报告相互依赖的工作区模块。按 go 命令在工作区模式下的方式解析 require：工作区模块用于其自身路径，
+// 其他路径依次经过 go.work 与各成员的 replace，因此指向成员的本地 replace 同样构成边。
+// 与只跟随单个 go.mod 中 replace 指令的 replace-cycle 警告不同，这里跨模块跟随 require
+// 参数: 成员 go.mod 文件的 {path, content} JSON 数组、go.work 的 {path, content} JSON 对象（可选）
+func FindWorkspaceCycles(this js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return createErrorJSON("no files provided")
+	}
+
+	var files []SourceFile
+	if err := json.Unmarshal([]byte(args[0].String()), &files); err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to parse files: %s", err.Error()))
+	}
+
+	var work *SourceFile
+	if len(args) > 1 && args[1].Truthy() {
+		work = &SourceFile{}
+		if err := json.Unmarshal([]byte(args[1].String()), work); err != nil {
+			return createErrorJSON(fmt.Sprintf("failed to parse go.work: %s", err.Error()))
+		}
+	}
+
+	cycles, err := findWorkspaceCycles(files, work)
+	if err != nil {
+		return createErrorJSON(err.Error())
+	}
+
+	result, err := json.Marshal(cycles)
+	if err != nil {
+		return createErrorJSON(fmt.Sprintf("failed to marshal result: %s", err.Error()))
+	}
+
+	return string(result)
+}
+
+// WorkspaceCycles is the result of FindWorkspaceCycles
+// WorkspaceCycles 是 FindWorkspaceCycles 的结果
+type WorkspaceCycles struct {
+	Cycles  []ModuleCycle `json:"cycles"`
+	Skipped []string      `json:"skipped"` // go.mod files that failed to parse or have no module directive
+}
+
+// ModuleCycle is a group of workspace modules depending on each other and
+// one cycle through them
+// ModuleCycle 表示相互依赖的一组工作区模块以及经过它们的一个循环
+type ModuleCycle struct {
+	Modules []string    `json:"modules"` // module paths of the group, sorted
+	Path    []string    `json:"path"`    // the cycle, starting and ending with the first module, e.g. [a, b, a]
+	Steps   []CycleStep `json:"steps"`   // the require behind each edge of Path
+}
+
+// CycleStep is a require of one workspace module resolving to another
+// CycleStep 表示一个工作区模块解析到另一个工作区模块的 require
+type CycleStep struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Require string `json:"require"` // required module path, differs from To when replaced
+	Replace string `json:"replace"` // replacement target as written, empty when not replaced
+	GoMod   string `json:"goMod"`   // go.mod file of From
+	Line    int    `json:"line"`    // line of the require in GoMod
+}
+
+// workspaceMember is a parsed workspace module
+// workspaceMember 表示已解析的工作区模块
+type workspaceMember struct {
+	goMod string
+	dir   string
+	file  *modfile.File
+}
+
+// workspaceReplace is a replace directive with a local target resolved
+// against the directory of the file declaring it
+// workspaceReplace 表示本地目标已相对声明它的文件目录解析的 replace 指令
+type workspaceReplace struct {
+	old    modfile.Replace
+	target string // module path, or the cleaned directory of a local replace
+	local  bool
+}
+
+// findWorkspaceCycles builds the graph of workspace modules and reports a
+// cycle for each strongly connected group of two or more modules. The
+// replaces of go.work come first; among the members' replaces the first
+// matching one in file order wins, and a replace of a specific version
+// beats a wildcard one in the same file, as in the go command.
+// findWorkspaceCycles 构建工作区模块图，并为每个包含两个及以上模块的强连通分量报告一个循环。
+// 先检查 go.work 的 replace；各成员的 replace 中按文件顺序使用第一个匹配的，
+// 同一文件中指定版本的 replace 优先于通配的 replace，与 go 命令相同
+func findWorkspaceCycles(files []SourceFile, work *SourceFile) (*WorkspaceCycles, error) {
+	result := &WorkspaceCycles{Cycles: []ModuleCycle{}, Skipped: []string{}}
+
+	var replaces [][]workspaceReplace
+	if work != nil {
+		workFile, err := modfile.ParseWork(work.Path, []byte(work.Content), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse go.work: %s", err.Error())
+		}
+		replaces = append(replaces, resolveReplaces(workFile.Replace, slashDir(work.Path)))
+	}
+
+	members := map[string]workspaceMember{}
+	byDir := map[string]string{}
+	var order []string
+	for _, file := range files {
+		modFile, err := parseModContent(file.Content)
+		if err != nil || modFile.Module == nil {
+			result.Skipped = append(result.Skipped, file.Path)
+			continue
+		}
+		dir := slashDir(file.Path)
+		members[modFile.Module.Mod.Path] = workspaceMember{goMod: file.Path, dir: dir, file: modFile}
+		byDir[dir] = modFile.Module.Mod.Path
+		order = append(order, modFile.Module.Mod.Path)
+		replaces = append(replaces, resolveReplaces(modFile.Replace, dir))
+	}
+
+	edges := map[string][]string{}
+	steps := map[[2]string]CycleStep{}
+	for _, from := range order {
+		member := members[from]
+		for _, req := range member.file.Require {
+			to, replace := resolveWorkspaceRequire(req, members, byDir, replaces)
+			if to == "" || to == from {
+				continue
+			}
+			key := [2]string{from, to}
+			if _, ok := steps[key]; ok {
+				continue
+			}
+			start, _ := linePosition(req.Syntax)
+			steps[key] = CycleStep{From: from, To: to, Require: req.Mod.Path, Replace: replace, GoMod: member.goMod, Line: start.Line}
+			edges[from] = append(edges[from], to)
+		}
+	}
+
+	for _, group := range stronglyConnected(edges) {
+		if len(group) < 2 {
+			continue
+		}
+		cycle := ModuleCycle{Modules: group, Path: shortestCycle(group, edges), Steps: []CycleStep{}}
+		for i := 1; i < len(cycle.Path); i++ {
+			cycle.Steps = append(cycle.Steps, steps[[2]string{cycle.Path[i-1], cycle.Path[i]}])
+		}
+		result.Cycles = append(result.Cycles, cycle)
+	}
+	return result, nil
+}
+
+// resolveWorkspaceRequire returns the workspace module a require resolves
+// to, empty when it is outside the workspace, and the replacement on the way
+// resolveWorkspaceRequire 返回 require 解析到的工作区模块（在工作区之外时为空），以及途经的替换
+func resolveWorkspaceRequire(req *modfile.Require, members map[string]workspaceMember, byDir map[string]string, replaces [][]workspaceReplace) (string, string) {
+	if _, ok := members[req.Mod.Path]; ok {
+		return req.Mod.Path, ""
+	}
+	for _, fileReplaces := range replaces {
+		rep, ok := matchWorkspaceReplace(fileReplaces, req.Mod.Path, req.Mod.Version)
+		if !ok {
+			continue
+		}
+		replace := rep.old.New.Path
+		if rep.old.New.Version != "" {
+			replace += " " + rep.old.New.Version
+		}
+		if rep.local {
+			return byDir[rep.target], replace
+		}
+		if _, ok := members[rep.target]; ok {
+			return rep.target, replace
+		}
+		return "", replace
+	}
+	return "", ""
+}
+
+// matchWorkspaceReplace finds the replace of one file applying to
+// path@version, preferring one for that exact version
+// matchWorkspaceReplace 查找单个文件中适用于 path@version 的 replace，优先使用指定该版本的
+func matchWorkspaceReplace(replaces []workspaceReplace, modPath, version string) (workspaceReplace, bool) {
+	var wildcard *workspaceReplace
+	for i, rep := range replaces {
+		if rep.old.Old.Path != modPath {
+			continue
+		}
+		if rep.old.Old.Version == version {
+			return rep, true
+		}
+		if rep.old.Old.Version == "" && wildcard == nil {
+			wildcard = &replaces[i]
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return workspaceReplace{}, false
+}
+
+// resolveReplaces resolves the local targets of replaces against dir
+// resolveReplaces 相对 dir 解析 replace 的本地目标
+func resolveReplaces(replaces []*modfile.Replace, dir string) []workspaceReplace {
+	resolved := make([]workspaceReplace, 0, len(replaces))
+	for _, rep := range replaces {
+		item := workspaceReplace{old: *rep, target: rep.New.Path}
+		if replaceKind(rep) == ReplaceKindLocal {
+			item.local = true
+			item.target = joinSlashDir(dir, rep.New.Path)
+		}
+		resolved = append(resolved, item)
+	}
+	return resolved
+}
+
+// slashDir returns the directory of a file path with forward slashes, as
+// the extension may pass Windows paths
+// slashDir 以正斜杠返回文件路径所在目录，扩展可能传入 Windows 路径
+func slashDir(file string) string {
+	return path.Dir(strings.ReplaceAll(file, `\`, "/"))
+}
+
+// joinSlashDir resolves a directory against base unless it is absolute,
+// either rooted or starting with a Windows drive letter
+// joinSlashDir 相对 base 解析目录，除非它是绝对路径（以根目录或 Windows 盘符开头）
+func joinSlashDir(base, dir string) string {
+	dir = strings.ReplaceAll(dir, `\`, "/")
+	if strings.HasPrefix(dir, "/") || (len(dir) >= 2 && dir[1] == ':') {
+		return path.Clean(dir)
+	}
+	return path.Join(base, dir)
+}
+
+// shortestCycle returns the shortest cycle through the first module of a
+// group, found by a breadth-first search that stays inside the group
+// shortestCycle 通过只在分量内进行的广度优先搜索，返回经过分量第一个模块的最短循环
+func shortestCycle(group []string, edges map[string][]string) []string {
+	inGroup := map[string]bool{}
+	for _, node := range group {
+		inGroup[node] = true
+	}
+
+	start := group[0]
+	prev := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[node] {
+			if next == start {
+				cycle := []string{start}
+				for n := node; n != start; n = prev[n] {
+					cycle = append(cycle, n)
+				}
+				cycle = append(cycle, start)
+				// Reverse the walk back into the order of the edges
+				// 将回溯的路径反转为边的顺序
+				for i, j := 1, len(cycle)-2; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return cycle
+			}
+			if _, seen := prev[next]; !seen && inGroup[next] {
+				prev[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return []string{start}
+}
@@ -2,6 +2,8 @@ import * as vscode from 'vscode';
 import { IsInWorkspace, IsTestFile } from './pkg/cond';
 import { findImplementations, findImplementedInterfaces, findMethodImplementedInterfaces } from './core/navigator/interface';
 import { findReferences, getOtherReferenceLocation } from './core/navigator/reference';
+import { ImplementationIndex } from './core/navigator/implementation';
+import { findTests, TestKind } from './core/run/test';
 
 /**
  * ▶ Run
@@ -51,6 +53,123 @@ export function Args(range: vscode.Range, uri: vscode.Uri): vscode.CodeLens {
     });
 }
 
+/**
+ * ▶ Run Test
+ * 创建运行测试函数的 CodeLens
+ * Create CodeLens for running a test function
+ * @param range 代码范围 (code range)
+ * @param uri 文档 URI (document URI)
+ * @param name 测试完整名称 (full test name)
+ * @param kind 测试类型 (test kind)
+ * @returns CodeLens 实例 (CodeLens instance)
+ */
+export function RunTest(range: vscode.Range, uri: vscode.Uri, name: string, kind: TestKind): vscode.CodeLens {
+    return new vscode.CodeLens(range, {
+        title: kind === 'benchmark' ? '▶ Run Benchmark' : '▶ Run Test',
+        command: 'gopp.runTest',
+        arguments: [uri, name, kind]
+    });
+}
+
+/**
+ * ▶ Run Fuzz
+ * 创建运行模糊测试的 CodeLens
+ * Create CodeLens for fuzzing a fuzz target
+ * @param range 代码范围 (code range)
+ * @param uri 文档 URI (document URI)
+ * @param name 模糊测试目标名称 (fuzz target name)
+ * @returns CodeLens 实例 (CodeLens instance)
+ */
+export function RunFuzz(range: vscode.Range, uri: vscode.Uri, name: string): vscode.CodeLens {
+    return new vscode.CodeLens(range, {
+        title: '▶ Run Fuzz',
+        command: 'gopp.runTest',
+        arguments: [uri, name, 'fuzz']
+    });
+}
+
+/**
+ * ▶ Run Example
+ * 创建运行示例函数并检查其输出的 CodeLens
+ * Create CodeLens for running an example function and checking its output
+ * @param range 代码范围 (code range)
+ * @param uri 文档 URI (document URI)
+ * @param name 示例函数名称 (example function name)
+ * @param unordered 输出是否不检查顺序 (whether the output is checked in any order)
+ * @returns CodeLens 实例 (CodeLens instance)
+ */
+export function RunExample(range: vscode.Range, uri: vscode.Uri, name: string, unordered: boolean): vscode.CodeLens {
+    return new vscode.CodeLens(range, {
+        title: unordered ? '▶ Run Example (unordered output)' : '▶ Run Example',
+        command: 'gopp.runTest',
+        arguments: [uri, name, 'example']
+    });
+}
+
+/**
+ * 🐞 Debug Test
+ * 创建调试测试函数的 CodeLens
+ * Create CodeLens for debugging a test function
+ * @param range 代码范围 (code range)
+ * @param uri 文档 URI (document URI)
+ * @param name 测试完整名称 (full test name)
+ * @param kind 测试类型 (test kind)
+ * @returns CodeLens 实例 (CodeLens instance)
+ */
+export function DebugTest(range: vscode.Range, uri: vscode.Uri, name: string, kind: TestKind): vscode.CodeLens {
+    return new vscode.CodeLens(range, {
+        title: kind === 'benchmark' ? '🐞 Debug Benchmark' : '🐞 Debug Test',
+        command: 'gopp.debugTest',
+        arguments: [uri, name, kind]
+    });
+}
+
+/**
+ * 为测试文件中的测试函数和子测试创建运行/调试 CodeLens，模糊测试目标另有运行模糊测试的 CodeLens，
+ * 其运行/调试 CodeLens 只执行种子语料；示例函数只有带输出注释时才会被 go test 运行，
+ * 其运行 CodeLens 同时显示在函数和输出注释上
+ * Create run/debug CodeLenses for the tests and subtests of a test file. Fuzz targets also get a
+ * CodeLens that fuzzes, their run/debug CodeLenses only run the seed corpus. Examples are only run
+ * by go test when they have an output comment, their run CodeLens is shown on the function and on the comment
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 测试文件 (test file)
+ * @param codeLenses CodeLens数组 (CodeLens array)
+ */
+export async function Tests(
+    ctx: vscode.ExtensionContext,
+    document: vscode.TextDocument,
+    codeLenses: vscode.CodeLens[]
+) {
+    const tests = await findTests(ctx, document);
+    for (const test of tests) {
+        if (test.kind === 'example') {
+            if (!test.output || !test.outputLine) {
+                continue;
+            }
+            const unordered = test.output === 'unordered';
+            const line = test.line - 1;
+            const range = new vscode.Range(line, 0, line, document.lineAt(line).text.length);
+            codeLenses.push(RunExample(range, document.uri, test.name, unordered));
+            codeLenses.push(DebugTest(range, document.uri, test.name, test.kind));
+            const outputRange = new vscode.Range(test.outputLine - 1, 0, (test.outputEndLine ?? test.outputLine) - 1, 0);
+            codeLenses.push(RunExample(outputRange, document.uri, test.name, unordered));
+            continue;
+        }
+
+        const entries = [{ name: test.name, line: test.line }, ...test.subtests];
+        for (const entry of entries) {
+            const line = entry.line - 1;
+            const range = new vscode.Range(line, 0, line, document.lineAt(line).text.length);
+            const kind = test.kind === 'fuzz' ? 'test' : test.kind;
+            codeLenses.push(RunTest(range, document.uri, entry.name, kind));
+            codeLenses.push(DebugTest(range, document.uri, entry.name, kind));
+            if (test.kind === 'fuzz') {
+                codeLenses.push(RunFuzz(range, document.uri, entry.name));
+            }
+        }
+    }
+}
+
 /**
  * Ⓖ - 生成按钮
  * Ⓖ - Generate button
@@ -83,11 +202,23 @@ export function G(
                 command: 'gopp.generateInterfaceStubs',
                 arguments: [structName, filePath, lineNumber + 1]
             },
+            {
+                label: 'Generate JSON Tags',
+                description: '按配置的命名风格为结构体字段生成 JSON 标签',
+                command: 'gopp.generateJsonTags',
+                arguments: [structName, filePath, lineNumber + 1]
+            },
             {
                 label: 'Generate Struct Tags',
                 description: '为结构体字段生成标签',
                 command: 'gopp.generateStructTags',
-                arguments: [structName, filePath, lineNumber + 1, fields]
+                arguments: [structName, filePath, lineNumber + 1]
+            },
+            {
+                label: 'Align Struct Fields',
+                description: '与 gofmt 相同地对齐字段与行尾注释',
+                command: 'gopp.alignStructFields',
+                arguments: [structName, filePath, lineNumber + 1]
             },
             {
                 label: 'Generate Option Pattern Code',
@@ -185,6 +316,140 @@ export async function I(
     }
 }
 
+/**
+ * N implementations - 接口实现数量按钮
+ * N implementations - Interface implementation count button
+ * 基于工作空间索引，不依赖 gopls
+ * Based on the workspace index, does not depend on gopls
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 当前文档 (current document)
+ * @param interfaceName 接口名称 (interface name)
+ * @param lineNumber 匹配的行号 (matching line number)
+ * @param range 匹配的范围 (matching range)
+ * @param codeLenses CodeLens数组 (CodeLens array)
+ */
+export async function Implementations(
+    ctx: vscode.ExtensionContext,
+    document: vscode.TextDocument,
+    interfaceName: string,
+    lineNumber: number,
+    range: vscode.Range,
+    codeLenses: vscode.CodeLens[]
+) {
+    const found = await ImplementationIndex.lookup(ctx, document, interfaceName, lineNumber);
+    if (!found || found.implementations.length === 0) {
+        return;
+    }
+
+    const count = found.implementations.length;
+    codeLenses.push(new vscode.CodeLens(range, {
+        title: count === 1 ? '1 implementation' : `${count} implementations`,
+        command: 'gopp.listInterfaceImplementations',
+        arguments: [found.info, found.implementations]
+    }));
+}
+
+/**
+ * implements Greeter.SayHello - 跳转到接口方法按钮
+ * implements Greeter.SayHello - Go to interface method button
+ * 多个接口时显示快速选择菜单
+ * Shows a quick-pick when the method satisfies several interfaces
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 当前文档 (current document)
+ * @param methodName 方法名称 (method name)
+ * @param lineNumber 匹配的行号 (matching line number)
+ * @param range 匹配的范围 (matching range)
+ * @param codeLenses CodeLens数组 (CodeLens array)
+ */
+export async function Implements(
+    ctx: vscode.ExtensionContext,
+    document: vscode.TextDocument,
+    methodName: string,
+    lineNumber: number,
+    range: vscode.Range,
+    codeLenses: vscode.CodeLens[]
+) {
+    const methods = await ImplementationIndex.lookupMethod(ctx, document, methodName, lineNumber);
+    if (methods.length === 0) {
+        return;
+    }
+
+    if (methods.length === 1) {
+        const target = methods[0].method;
+        const location = new vscode.Location(vscode.Uri.file(target.path), new vscode.Position(target.line - 1, 0));
+        codeLenses.push(new vscode.CodeLens(range, {
+            title: `implements ${methods[0].interface}.${methodName}`,
+            command: 'editor.action.goToLocations',
+            arguments: [document.uri, new vscode.Position(lineNumber, 0), [location], 'goto', 'No interface methods found']
+        }));
+        return;
+    }
+
+    codeLenses.push(new vscode.CodeLens(range, {
+        title: `implements ${methods.length} interfaces`,
+        command: 'gopp.listMethodImplementations',
+        arguments: [methodName, methods.map(m => ({
+            structName: m.interface,
+            filePath: m.method.path,
+            lineNumber: m.method.line
+        }))]
+    }));
+}
+
+/**
+ * Implement Greeter - 生成接口方法按钮
+ * Implement Greeter - Generate interface methods button
+ * 仅在类型已实现接口的部分方法时显示
+ * Shown only when the type already declares some of the interface methods
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 当前文档 (current document)
+ * @param typeName 类型名称 (type name)
+ * @param lineNumber 匹配的行号 (matching line number)
+ * @param range 匹配的范围 (matching range)
+ * @param codeLenses CodeLens数组 (CodeLens array)
+ */
+export async function Implement(
+    ctx: vscode.ExtensionContext,
+    document: vscode.TextDocument,
+    typeName: string,
+    lineNumber: number,
+    range: vscode.Range,
+    codeLenses: vscode.CodeLens[]
+) {
+    const interfaces = await ImplementationIndex.lookupPartial(ctx, document, typeName, lineNumber);
+    for (const entry of interfaces) {
+        codeLenses.push(new vscode.CodeLens(range, {
+            title: `Implement ${entry.interface}`,
+            command: 'gopp.implementInterface',
+            arguments: [typeName, document.uri.fsPath, `${entry.package}.${entry.interface}`]
+        }));
+    }
+}
+
+/**
+ * Generate mock for Greeter - 生成接口 mock 按钮
+ * Generate mock for Greeter - Generate interface mock button
+ * @param document 当前文档 (current document)
+ * @param interfaceName 接口名称 (interface name)
+ * @param range 匹配的范围 (matching range)
+ * @param codeLenses CodeLens数组 (CodeLens array)
+ */
+export function Mock(
+    document: vscode.TextDocument,
+    interfaceName: string,
+    range: vscode.Range,
+    codeLenses: vscode.CodeLens[]
+) {
+    if (!IsInWorkspace(document) || IsTestFile(document)) {
+        return;
+    }
+    codeLenses.push(new vscode.CodeLens(range, {
+        title: `Generate mock for ${interfaceName}`,
+        command: 'gopp.generateMock',
+        arguments: [interfaceName, document.uri.fsPath]
+    }));
+}
+
 /**
  * Ⓡ - 引用按钮
  * Ⓡ - References button
@@ -4,21 +4,35 @@ import {
     registerCommandGenerateOptions,
     registerCommandGenerateOptionCode,
     registerCommandGenerateInterfaceStubs,
+    registerCommandApplyStubs,
+    registerCommandGenerateMock,
+    registerCommandExtractInterface,
+    registerCommandImplementInterface,
     registerCommandShowStructOptions,
     registerCommandGenerateStructTags,
+    registerCommandAlignStructFields,
+    registerCommandGenerateJsonTags,
     registerCommandFuncTest
 } from './generate';
 import {
     registerCommandNavigateToInterface,
     registerCommandNavigateToInterfaceMethod,
     registerCommandListInterfaceImplementations,
-    registerCommandListMethodImplementations
+    registerCommandListMethodImplementations,
+    registerCommandShowInterfaceMethodSet,
+    registerCommandCompareInterfaces,
+    registerCommandFindInterfaceMethodUsages,
+    registerCommandShowImplementationMatrix
 } from './interface';
 import {
     registerCommandRunMain,
     registerCommandDebugMain,
-    registerCommandSetMainArgs
+    registerCommandSetMainArgs,
+    registerCommandRunTest,
+    registerCommandDebugTest
 } from './main';
+import { registerCommandTidyFormat, registerCommandNormalizeReplacePaths, registerCommandPromoteIndirect, registerCommandExcludeVersion, registerCommandSwitchToolchain, registerCommandExportDependencies, registerCommandRenameModule, registerCommandSplitWorkspace, registerCommandCopyImportPath } from './go_mod';
+import { registerCommandConvertBuildConstraints } from './constraint';
 
 export function DisposeCommands(ctx : vscode.ExtensionContext) : Array<vscode.Disposable> {
     return [
@@ -28,8 +42,14 @@ export function DisposeCommands(ctx : vscode.ExtensionContext) : Array<vscode.Di
         // 注册各种生成命令
         registerCommandGenerateOptions('gopp.generateOptions'),
         registerCommandGenerateOptionCode('gopp.generateOptionCode'),
-        registerCommandGenerateInterfaceStubs('gopp.generateInterfaceStubs'),
-        registerCommandGenerateStructTags('gopp.generateStructTags'), // 生成结构体标签
+        registerCommandGenerateInterfaceStubs(ctx, 'gopp.generateInterfaceStubs'),
+        registerCommandImplementInterface(ctx, 'gopp.implementInterface'), // 补全接口方法
+        registerCommandApplyStubs('gopp.applyStubs'), // 插入生成的桩方法
+        registerCommandGenerateMock(ctx, 'gopp.generateMock'), // 生成接口 mock
+        registerCommandExtractInterface(ctx, 'gopp.extractInterface'), // 从类型提取接口
+        registerCommandGenerateStructTags(ctx, 'gopp.generateStructTags'), // 生成结构体标签
+        registerCommandGenerateJsonTags(ctx, 'gopp.generateJsonTags'), // 生成 JSON 标签
+        registerCommandAlignStructFields(ctx, 'gopp.alignStructFields'), // 对齐结构体字段
         registerCommandShowStructOptions('gopp.showStructOptions'), // 显示结构选项
         registerCommandFuncTest('gopp.executeFunctionTest'), // 生成函数测试
 
@@ -38,11 +58,33 @@ export function DisposeCommands(ctx : vscode.ExtensionContext) : Array<vscode.Di
         registerCommandNavigateToInterfaceMethod('gopp.navigateToInterfaceMethod'), // 跳转到接口方法
         registerCommandListInterfaceImplementations('gopp.listInterfaceImplementations'), // 列出接口实现
         registerCommandListMethodImplementations('gopp.listMethodImplementations'), // 列出方法实现
+        registerCommandShowInterfaceMethodSet(ctx, 'gopp.showInterfaceMethodSet'), // 查看接口方法集
+        registerCommandCompareInterfaces(ctx, 'gopp.compareInterfaces'), // 比较接口可赋值性
+        registerCommandFindInterfaceMethodUsages(ctx, 'gopp.findInterfaceMethodUsages'), // 查找接口方法的使用
+        registerCommandShowImplementationMatrix(ctx, 'gopp.showImplementationMatrix'), // 接口实现矩阵
 
         // main函数相关命令
         registerCommandRunMain(ctx, 'gopp.runMain'), // 运行main函数
         registerCommandDebugMain(ctx, 'gopp.debugMain'), // 调试main函数
         registerCommandSetMainArgs(ctx, 'gopp.setMainArgs'), // 设置main函数参数
+
+        // 测试函数相关命令
+        registerCommandRunTest('gopp.runTest'), // 运行测试函数
+        registerCommandDebugTest('gopp.debugTest'), // 调试测试函数
+
+        // go.mod 相关命令
+        registerCommandTidyFormat(ctx, 'gopp.tidyFormatGoMod'), // 整理 go.mod
+        registerCommandNormalizeReplacePaths(ctx, 'gopp.normalizeReplacePaths'), // 规范化 replace 路径
+        registerCommandPromoteIndirect(ctx, 'gopp.promoteIndirect'), // 将间接依赖提升为直接依赖
+        registerCommandExcludeVersion(ctx, 'gopp.excludeVersion'), // 排除依赖的版本
+        registerCommandSwitchToolchain('gopp.switchToolchain'), // 切换工具链
+        registerCommandExportDependencies(ctx, 'gopp.exportDependencies'), // 导出依赖报告
+        registerCommandRenameModule(ctx, 'gopp.renameModule'), // 重命名模块
+        registerCommandSplitWorkspace(ctx, 'gopp.splitWorkspace'), // 拆分为 go.work 工作区
+        registerCommandCopyImportPath(ctx, 'gopp.copyImportPath'), // 复制包的导入路径
+
+        // 构建约束相关命令
+        registerCommandConvertBuildConstraints(ctx, 'gopp.convertBuildConstraints'), // 统一构建约束形式
     ];
 }
 
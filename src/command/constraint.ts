@@ -0,0 +1,116 @@
+import * as vscode from 'vscode';
+import * as path from 'path';
+import { Logger } from '../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+
+const logger = Logger.withContext('ConstraintCommands');
+
+/**
+ * 构建约束改写结果（由 WASM 返回）
+ * Result of rewriting the build constraints of a file (returned by WASM)
+ */
+interface ConstraintConversion {
+    content: string;
+    changed: boolean;
+    warnings: { kind: string; message: string; line: number }[];
+}
+
+/**
+ * 注册命令以将选中文件的构建约束统一为 gopp.buildConstraints.keep 指定的形式，
+ * 删除多余的另一种形式；约束无效或不一致的文件只报告，不修改
+ * Register command to rewrite the build constraints of the selected files to the form set by
+ * gopp.buildConstraints.keep, removing the redundant other form; files with invalid or
+ * mismatched constraints are reported instead of fixed
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandConvertBuildConstraints(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (resource?: vscode.Uri, selected?: vscode.Uri[]) => {
+        const resources = selected && selected.length > 0 ? selected : resource ? [resource] : [];
+        if (resources.length === 0 && vscode.window.activeTextEditor) {
+            resources.push(vscode.window.activeTextEditor.document.uri);
+        }
+
+        const files = await goFiles(resources);
+        if (files.length === 0) {
+            vscode.window.showWarningMessage('请选择 Go 文件或目录 (Please select Go files or folders)');
+            return;
+        }
+
+        const keep = vscode.workspace.getConfiguration('gopp.buildConstraints').get<string>('keep', 'go:build');
+        const edit = new vscode.WorkspaceEdit();
+        const skipped: { uri: vscode.Uri; message: string; line: number }[] = [];
+        for (const uri of files) {
+            try {
+                const document = await vscode.workspace.openTextDocument(uri);
+                const result = await WasmExecutor.callFunction<string>(
+                    ctx,
+                    GoWasmFunction.ConvertBuildConstraintsFunc,
+                    document.getText(),
+                    keep
+                );
+
+                const data = JSON.parse(result);
+                if (data.error !== undefined) {
+                    skipped.push({ uri, message: data.error, line: 1 });
+                    continue;
+                }
+
+                const conversion = data as ConstraintConversion;
+                if (conversion.warnings.length > 0) {
+                    skipped.push(...conversion.warnings.map(warning => ({ uri, message: warning.message, line: warning.line })));
+                } else if (conversion.changed) {
+                    edit.replace(uri, new vscode.Range(0, 0, document.lineCount, 0), conversion.content);
+                }
+            } catch (error) {
+                logger.error(`改写构建约束时出错: ${uri.fsPath}`, error);
+            }
+        }
+
+        const changed = edit.size;
+        if (changed > 0) {
+            await vscode.workspace.applyEdit(edit);
+        }
+        if (skipped.length === 0) {
+            vscode.window.showInformationMessage(`已改写 ${changed} 个文件的构建约束 (Rewrote build constraints of ${changed} files)`);
+            return;
+        }
+
+        const action = await vscode.window.showWarningMessage(
+            `已改写 ${changed} 个文件，${skipped.length} 处约束需要手动处理 (Rewrote ${changed} files, ${skipped.length} constraints need manual fixes)`,
+            '查看 (Show)'
+        );
+        if (!action) {
+            return;
+        }
+
+        const picked = await vscode.window.showQuickPick(skipped.map(item => ({
+            label: `${path.basename(item.uri.fsPath)}:${item.line}`,
+            description: vscode.workspace.asRelativePath(item.uri),
+            detail: item.message,
+            item
+        })), { placeHolder: '约束无效或不一致的文件 (Files with invalid or mismatched constraints)' });
+        if (picked) {
+            const position = new vscode.Position(Math.max(picked.item.line - 1, 0), 0);
+            await vscode.window.showTextDocument(picked.item.uri, { selection: new vscode.Range(position, position) });
+        }
+    });
+}
+
+/**
+ * 展开选中的资源：目录替换为其中的 Go 文件，跳过非 Go 文件
+ * Expand the selected resources: folders are replaced by the Go files they contain, non-Go files are skipped
+ * @param resources 选中的文件或目录 (selected files or folders)
+ */
+async function goFiles(resources: vscode.Uri[]): Promise<vscode.Uri[]> {
+    const files: vscode.Uri[] = [];
+    for (const uri of resources) {
+        const stat = await vscode.workspace.fs.stat(uri);
+        if (stat.type & vscode.FileType.Directory) {
+            files.push(...await vscode.workspace.findFiles(new vscode.RelativePattern(uri, '**/*.go'), '**/vendor/**'));
+        } else if (uri.fsPath.endsWith('.go')) {
+            files.push(uri);
+        }
+    }
+    return files;
+}
@@ -1,8 +1,11 @@
 import * as vscode from 'vscode';
-import * as cp from 'child_process';
 import * as path from 'path';
-import { InterfaceInfo, StructOption, StructField } from '../types';
-import { findInterfaces } from '../pkg/file';
+import { StructOption, StructField } from '../types';
+import { GoStubs, applyStubs, implementInterface } from '../core/codegenerate/implement';
+import { TagCase, generateStructTags, alignStructFields, tagSettings } from '../core/codegenerate/tag';
+import { ImplementationIndex } from '../core/navigator/implementation';
+import { generateMock, writeMock } from '../core/codegenerate/mock';
+import { extractInterface, applyExtractedInterface } from '../core/codegenerate/extract';
 
 /**
  * 注册命令以生成选项菜单
@@ -16,11 +19,13 @@ export function registerCommandGenerateOptions(cmd: string): vscode.Disposable {
         // 根据文件类型提供不同的选项
         const options = isTestFile ? [
             { label: 'Implement Interface Methods', description: 'Implement specified interface for struct', command: 'gopp.generateInterfaceStubs' },
-            { label: 'Generate Option Pattern Code', description: 'Generate Option pattern code', command: 'gopp.generateOptionCode' }
+            { label: 'Generate Option Pattern Code', description: 'Generate Option pattern code', command: 'gopp.generateOptionCode' },
+            { label: 'Extract Interface', description: 'Extract an interface from the exported methods of struct', command: 'gopp.extractInterface' }
         ] : [
             { label: 'Implement Interface Methods', description: 'Implement specified interface for struct', command: 'gopp.generateInterfaceStubs' },
             { label: 'Generate Unit Tests', description: 'Generate test file for current file', command: 'go.test.generate.file' },
-            { label: 'Generate Option Pattern Code', description: 'Generate Option pattern code', command: 'gopp.generateOptionCode' }
+            { label: 'Generate Option Pattern Code', description: 'Generate Option pattern code', command: 'gopp.generateOptionCode' },
+            { label: 'Extract Interface', description: 'Extract an interface from the exported methods of struct', command: 'gopp.extractInterface' }
         ];
 
         const selection = await vscode.window.showQuickPick(options, {
@@ -130,21 +135,18 @@ ${unselectedFields.map(field => `    ${field.name} ${field.type}`).join('\n')}
 /**
  * 注册命令以生成接口实现
  * Register command to generate interface implementations
+ * @param ctx 扩展上下文 (extension context)
  * @param cmd 命令名称 (command name)
  */
-export function registerCommandGenerateInterfaceStubs(cmd: string): vscode.Disposable {
+export function registerCommandGenerateInterfaceStubs(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
     return vscode.commands.registerCommand(cmd, async (structName: string, filePath: string, line: number) => {
         // 查找所有接口
-        const workspacePath = vscode.workspace.workspaceFolders?.[0]?.uri.fsPath;
-        if (!workspacePath) {
-            return;
-        }
-
-        const interfaces = await findInterfaces(workspacePath);
-        const items = interfaces.map((iface: InterfaceInfo) => ({
-            label: iface.name,
-            description: iface.filePath,
-            filePath: iface.filePath // Include filePath property
+        const interfaces = await ImplementationIndex.interfaces(ctx);
+        const items = interfaces.map(iface => ({
+            label: iface.interface,
+            description: iface.package,
+            detail: iface.path,
+            interfacePath: `${iface.package}.${iface.interface}`
         }));
 
         const selected = await vscode.window.showQuickPick(items, {
@@ -156,52 +158,175 @@ export function registerCommandGenerateInterfaceStubs(cmd: string): vscode.Dispo
             return;
         }
 
-        // 获取当前文档信息
+        try {
+            await implementInterface(ctx, filePath, structName, selected.interfacePath);
+        } catch (err) {
+            const errorMsg = err instanceof Error ? err.message : String(err);
+            vscode.window.showErrorMessage(`生成接口实现失败: ${errorMsg}`);
+        }
+    });
+}
+
+/**
+ * 注册命令以为类型实现指定接口
+ * Register command to implement a given interface on a type
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandImplementInterface(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (typeName: string, filePath: string, interfacePath: string) => {
+        try {
+            await implementInterface(ctx, filePath, typeName, interfacePath);
+        } catch (err) {
+            const errorMsg = err instanceof Error ? err.message : String(err);
+            vscode.window.showErrorMessage(`生成接口实现失败: ${errorMsg}`);
+        }
+    });
+}
+
+/**
+ * 注册命令以为接口生成 mock，默认放在接口所在的包中，也可以选择 mocks 子包或其他目录
+ * Register command to generate a mock of an interface, placed in the package of the interface by default,
+ * or in a mocks sub-package or another directory
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandGenerateMock(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (interfaceName?: string, filePath?: string) => {
+        // 未传入参数时使用光标处的接口名称
+        // Use the interface name under the cursor when no arguments are passed
         const editor = vscode.window.activeTextEditor;
-        if (!editor) {
+        if (!interfaceName || !filePath) {
+            const range = editor?.document.getWordRangeAtPosition(editor.selection.active, /[\w.]+/);
+            if (!editor || !range) {
+                vscode.window.showWarningMessage('请将光标放在接口名称上 (Place the cursor on an interface name)');
+                return;
+            }
+            interfaceName = editor.document.getText(range);
+            filePath = editor.document.uri.fsPath;
+        }
+
+        const dir = path.dirname(filePath);
+        const shortName = interfaceName.slice(interfaceName.lastIndexOf('.') + 1);
+        const targets = [
+            { label: '当前包 (Current package)', description: dir, dir },
+            { label: 'mocks 子包 (mocks sub-package)', description: path.join(dir, 'mocks'), dir: path.join(dir, 'mocks') },
+            { label: '选择目录... (Choose a folder...)', description: '', dir: '' }
+        ];
+        const target = await vscode.window.showQuickPick(targets, { placeHolder: `Generate mock for ${shortName}` });
+        if (!target) {
+            return;
+        }
+        let targetDir = target.dir;
+        if (targetDir === '') {
+            const folders = await vscode.window.showOpenDialog({
+                canSelectFiles: false,
+                canSelectFolders: true,
+                defaultUri: vscode.Uri.file(dir)
+            });
+            if (!folders || folders.length === 0) {
+                return;
+            }
+            targetDir = folders[0].fsPath;
+        }
+
+        const mockName = await vscode.window.showInputBox({
+            prompt: '请输入 mock 类型名称 (Mock type name)',
+            value: `Mock${shortName}`
+        });
+        if (!mockName) {
             return;
         }
 
         try {
-            // 获取接口所在包的导入路径
-            const pkgOutput = cp.execSync('go list -f "{{.ImportPath}}"', {
-                cwd: path.dirname(selected.filePath)
-            }).toString().trim();
-
-            // 构造完整的接口路径（使用包的导入路径）
-            const interfacePath = `${pkgOutput}.${selected.label}`;
-
-            const receiverType = `*${structName}`;
-            const cmd = `impl '${receiverType}' '${interfacePath}'`;
-
-            console.log(`Package path: ${pkgOutput}`); // 调试输出
-            console.log(`Interface path: ${interfacePath}`); // 调试输出
-            console.log(`Executing command: ${cmd}`); // 调试输出
-
-            const output = cp.execSync(cmd, {
-                cwd: path.dirname(editor.document.uri.fsPath),
-                env: { ...process.env, GO111MODULE: 'on' } // 确保启用了 Go modules
-            }).toString();
-
-            // 将生成的代码插入到当前光标位置
-            const position = editor.selection.active;
-            await editor.edit(editBuilder => {
-                editBuilder.insert(position, '\n' + output);
-            });
+            const mock = await generateMock(ctx, filePath, interfaceName, targetDir, mockName);
+            if (await writeMock(mock)) {
+                ImplementationIndex.invalidate();
+            }
+        } catch (err) {
+            const errorMsg = err instanceof Error ? err.message : String(err);
+            vscode.window.showErrorMessage(`生成 mock 失败: ${errorMsg}`);
+        }
+    });
+}
 
-            // 格式化插入的代码
-            await vscode.commands.executeCommand('editor.action.formatDocument');
+/**
+ * 注册命令以根据光标处类型的导出方法提取接口，默认放在类型上方，也可以选择其他文件
+ * Register command to extract an interface from the exported methods of the type under the cursor,
+ * placed above the type by default or in another file
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandExtractInterface(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (typeName?: string, filePath?: string) => {
+        // 未传入参数时使用光标处的类型名称
+        // Use the type name under the cursor when no arguments are passed
+        const editor = vscode.window.activeTextEditor;
+        if (!typeName || !filePath) {
+            const range = editor?.document.getWordRangeAtPosition(editor.selection.active, /\w+/);
+            if (!editor || !range) {
+                vscode.window.showWarningMessage('请将光标放在类型名称上 (Place the cursor on a type name)');
+                return;
+            }
+            typeName = editor.document.getText(range);
+            filePath = editor.document.uri.fsPath;
+        }
 
-            // 刷新接口缓存
-            if (workspacePath) {
-                await findInterfaces(workspacePath);
+        const interfaceName = await vscode.window.showInputBox({
+            prompt: '请输入接口名称 (Interface name)',
+            value: `${typeName}Interface`
+        });
+        if (!interfaceName) {
+            return;
+        }
+
+        const targets = [
+            { label: '类型上方 (Above the type)', description: path.basename(filePath), choose: false },
+            { label: '选择文件... (Choose a file...)', description: '', choose: true }
+        ];
+        const target = await vscode.window.showQuickPick(targets, { placeHolder: `Extract ${interfaceName} from ${typeName}` });
+        if (!target) {
+            return;
+        }
+        let targetPath: string | undefined;
+        if (target.choose) {
+            const picked = await vscode.window.showOpenDialog({
+                canSelectMany: false,
+                defaultUri: vscode.Uri.file(path.dirname(filePath)),
+                filters: { Go: ['go'] }
+            });
+            if (!picked || picked.length === 0) {
+                return;
+            }
+            targetPath = picked[0].fsPath;
+        }
+
+        try {
+            const extracted = await extractInterface(ctx, filePath, typeName, interfaceName, targetPath);
+            await applyExtractedInterface(extracted);
+            ImplementationIndex.invalidate();
+            if (extracted.warnings.length > 0) {
+                vscode.window.showWarningMessage(extracted.warnings.map(w => w.message).join('\n'));
             }
         } catch (err) {
-            // 增强错误信息
             const errorMsg = err instanceof Error ? err.message : String(err);
-            vscode.window.showErrorMessage(`生成接口实现失败: ${errorMsg}`);
-            console.error('完整错误信息:', err);
-            console.error('当前工作目录:', path.dirname(editor.document.uri.fsPath));
+            vscode.window.showErrorMessage(`提取接口失败: ${errorMsg}`);
+        }
+    });
+}
+
+/**
+ * 注册命令以插入 WASM 生成的桩方法
+ * Register command to insert stub methods generated by WASM
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandApplyStubs(cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (stubs: GoStubs) => {
+        try {
+            await applyStubs(stubs);
+        } catch (err) {
+            const errorMsg = err instanceof Error ? err.message : String(err);
+            vscode.window.showErrorMessage(`生成方法失败: ${errorMsg}`);
         }
     });
 }
@@ -232,95 +357,128 @@ export function registerCommandShowStructOptions(cmd : string) : vscode.Disposab
 /**
  * 注册命令以生成结构体标签
  * Register command to generate struct tags
+ * @param ctx 扩展上下文 (extension context)
  * @param cmd 命令名称 (command name)
  */
-export function registerCommandGenerateStructTags(cmd : string) : vscode.Disposable {
-    return vscode.commands.registerCommand(cmd, async (structName: string, filePath: string, line: number, fields: StructField[]) => {
+export function registerCommandGenerateStructTags(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (structName?: string, filePath?: string, line?: number) => {
         // 1. 弹出输入框让用户输入标签名称
         const tagName = await vscode.window.showInputBox({
             prompt: '请输入标签名称（例如：json, xml, yaml）',
-            placeHolder: 'json'
+            placeHolder: 'json',
+            value: 'json'
         });
 
         if (!tagName) {
             return;
         }
 
-        // 2. 询问值格式
-        const formatType = await vscode.window.showQuickPick(
-            [
-                { label: '驼峰格式', value: 'camel' },
-                { label: '下划线格式', value: 'snake' }
-            ],
-            { placeHolder: '请选择字段值格式' }
-        );
+        // 2. 询问值格式，默认使用配置中的格式
+        const { caseStyle } = tagSettings();
+        const formats: { label: string; value: TagCase }[] = [
+            { label: '下划线格式 (snake_case)', value: 'snake' },
+            { label: '驼峰格式 (camelCase)', value: 'camel' },
+            { label: '短横线格式 (kebab-case)', value: 'kebab' }
+        ];
+        formats.sort((a, b) => Number(b.value === caseStyle) - Number(a.value === caseStyle));
+        const formatType = await vscode.window.showQuickPick(formats, { placeHolder: '请选择字段值格式' });
 
         if (!formatType) {
             return;
         }
 
         // 3. 生成带标签的字段
+        await runGenerateStructTags(ctx, tagName, formatType.value, structName, filePath, line);
+    });
+}
+
+/**
+ * 注册命令以按配置的命名风格生成 JSON 标签
+ * Register command to generate JSON tags with the configured case style
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandGenerateJsonTags(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (structName?: string, filePath?: string, line?: number) => {
+        await runGenerateStructTags(ctx, 'json', tagSettings().caseStyle, structName, filePath, line);
+    });
+}
+
+/**
+ * 为指定结构体或光标所在结构体生成标签
+ * Generate tags for the given struct, or the struct under the cursor
+ * @param ctx 扩展上下文 (extension context)
+ * @param tagName 标签名称 (tag name)
+ * @param caseStyle 标签值命名风格 (case style)
+ * @param structName 结构体名称 (struct name)
+ * @param filePath 结构体所在文件 (file declaring the struct)
+ * @param line 结构体定义的行号，从 1 开始 (struct definition line, 1-based)
+ */
+async function runGenerateStructTags(
+    ctx: vscode.ExtensionContext,
+    tagName: string,
+    caseStyle: TagCase,
+    structName?: string,
+    filePath?: string,
+    line?: number
+): Promise<void> {
+    // 从命令面板调用时使用光标所在的结构体
+    // When invoked from the command palette, use the struct under the cursor
+    const editor = vscode.window.activeTextEditor;
+    const document = filePath
+        ? await vscode.workspace.openTextDocument(vscode.Uri.file(filePath))
+        : editor?.document;
+    if (!document) {
+        return;
+    }
+
+    try {
+        const count = await generateStructTags(
+            ctx,
+            document,
+            filePath ? structName || '' : '',
+            filePath ? line || 0 : (editor?.selection.active.line ?? 0) + 1,
+            tagName,
+            caseStyle
+        );
+        if (count === 0) {
+            vscode.window.showInformationMessage(`所有字段已带有 ${tagName} 标签 (All fields already have ${tagName} tags)`);
+        }
+    } catch (error) {
+        vscode.window.showErrorMessage(`生成标签失败: ${error}`);
+    }
+}
+
+/**
+ * 注册命令以对齐结构体字段的列与行尾注释
+ * Register command to align the columns and trailing comments of struct fields
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandAlignStructFields(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (structName?: string, filePath?: string, line?: number) => {
+        // 从命令面板调用时使用光标所在的结构体
+        // When invoked from the command palette, use the struct under the cursor
         const editor = vscode.window.activeTextEditor;
-        if (editor) {
-            const document = editor.document;
-            const changes: vscode.TextEdit[] = [];
-
-            // 仅处理导出字段
-            for (const field of fields.filter(f => f.isExported)) {
-                const line = field.line;
-                const lineText = document.lineAt(line).text;
-
-                // 根据选择的格式转换字段名
-                const tagValue = formatType.value === 'camel'
-                    ? field.name.charAt(0).toLowerCase() + field.name.slice(1)
-                    : field.name.replace(/([A-Z])/g, '_$1').toLowerCase().replace(/^_/, '');
-
-                const newTag = `${tagName}:"${tagValue}"`;
-
-                // 检查当前行是否已有标签
-                const currentTags = lineText.match(/`.*`/);
-
-                if (currentTags) {
-                    // 检查是否已存在相同的标签
-                    const existingTags = currentTags[0].slice(1, -1);
-                    const tagExists = new RegExp(`${tagName}:"[^"]*"`).test(existingTags);
-
-                    if (!tagExists) {
-                        // 已有其他标签，添加新标签
-                        const updatedTags = `\`${existingTags} ${newTag}\``;
-                        const tagStartPos = lineText.indexOf('`');
-                        changes.push(vscode.TextEdit.replace(
-                            new vscode.Range(line, tagStartPos, line, tagStartPos + currentTags[0].length),
-                            updatedTags
-                        ));
-                    }
-                } else {
-                    // 查找类型结束的位置 (可能在注释之前)
-                    const typeRegex = new RegExp(`\\s+${field.name}\\s+([^\\s/]+)`);
-                    const typeMatch = lineText.match(typeRegex);
-
-                    if (typeMatch) {
-                        // 找到类型名称的结束位置
-                        const typePos = lineText.indexOf(typeMatch[1]) + typeMatch[1].length;
-                        // 插入标签到类型之后
-                        changes.push(vscode.TextEdit.insert(
-                            new vscode.Position(line, typePos),
-                            ' `' + newTag + '`'
-                        ));
-                    } else {
-                        // 如果无法识别类型位置，则添加到行末
-                        changes.push(vscode.TextEdit.insert(
-                            new vscode.Position(line, lineText.length),
-                            ' `' + newTag + '`'
-                        ));
-                    }
-                }
-            }
+        const document = filePath
+            ? await vscode.workspace.openTextDocument(vscode.Uri.file(filePath))
+            : editor?.document;
+        if (!document) {
+            return;
+        }
 
-            // 应用所有修改
-            const edit = new vscode.WorkspaceEdit();
-            edit.set(document.uri, changes);
-            await vscode.workspace.applyEdit(edit);
+        try {
+            const count = await alignStructFields(
+                ctx,
+                document,
+                filePath ? structName || '' : '',
+                filePath ? line || 0 : (editor?.selection.active.line ?? 0) + 1
+            );
+            if (count === 0) {
+                vscode.window.showInformationMessage('结构体字段已对齐 (Struct fields are already aligned)');
+            }
+        } catch (error) {
+            vscode.window.showErrorMessage(`对齐结构体字段失败: ${error}`);
         }
     });
 }
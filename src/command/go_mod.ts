@@ -1,10 +1,12 @@
 import * as vscode from 'vscode';
 import * as path from 'path';
+import * as fs from 'fs';
 import { exec } from 'child_process';
 import { GoLibraryTreeData } from '../core/library/tree';
 import { ModItem } from '../core/library/item';
 import { Logger } from '../pkg/logger';
 import { findInFiles } from '../pkg/go';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
 
 // 记录器
 const logger = Logger.withContext('GoModCommands');
@@ -52,6 +54,400 @@ export function registerGoModCommands(context: vscode.ExtensionContext, modTreeP
     logger.info('Go模块命令注册完成');
 }
 
+/**
+ * 注册命令以整理当前 go.mod：对 require 块排序并格式化，不需要网络访问
+ * Register command to clean up the active go.mod: sort the require blocks and format it, without network access
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandTidyFormat(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || path.basename(editor.document.fileName) !== 'go.mod') {
+            vscode.window.showWarningMessage('请先打开 go.mod 文件 (Please open a go.mod file first)');
+            return;
+        }
+
+        const document = editor.document;
+        try {
+            const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.TidyFormatFunc, document.getText());
+            if (result.startsWith('{')) {
+                vscode.window.showErrorMessage(`整理 go.mod 失败: ${JSON.parse(result).error}`);
+                return;
+            }
+            if (result === document.getText()) {
+                return;
+            }
+
+            const fullRange = new vscode.Range(0, 0, document.lineCount, 0);
+            await editor.edit(editBuilder => editBuilder.replace(fullRange, result));
+        } catch (error) {
+            logger.error('整理 go.mod 时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以将当前 go.mod 中本地 replace 路径的反斜杠改为正斜杠，避免 go.mod 在不同平台间反复变化
+ * Register command to turn the backslashes of local replace paths in the active go.mod into forward slashes,
+ * so go.mod does not churn between platforms
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandNormalizeReplacePaths(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || path.basename(editor.document.fileName) !== 'go.mod') {
+            vscode.window.showWarningMessage('请先打开 go.mod 文件 (Please open a go.mod file first)');
+            return;
+        }
+
+        const document = editor.document;
+        try {
+            const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.NormalizeReplacePathsFunc, document.getText());
+            if (result.startsWith('{')) {
+                vscode.window.showErrorMessage(`规范化 replace 路径失败: ${JSON.parse(result).error}`);
+                return;
+            }
+            if (result === document.getText()) {
+                return;
+            }
+
+            const fullRange = new vscode.Range(0, 0, document.lineCount, 0);
+            await editor.edit(editBuilder => editBuilder.replace(fullRange, result));
+        } catch (error) {
+            logger.error('规范化 replace 路径时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以将当前 go.mod 的所有间接依赖提升为直接依赖，固定其版本；require 块结构保持不变
+ * Register command to promote every indirect dependency of the active go.mod to a direct require,
+ * pinning its version; the require blocks are kept as they are
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandPromoteIndirect(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || path.basename(editor.document.fileName) !== 'go.mod') {
+            vscode.window.showWarningMessage('请先打开 go.mod 文件 (Please open a go.mod file first)');
+            return;
+        }
+
+        const document = editor.document;
+        try {
+            const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.PromoteIndirectFunc, document.getText());
+            if (result.startsWith('{')) {
+                vscode.window.showErrorMessage(`提升间接依赖失败: ${JSON.parse(result).error}`);
+                return;
+            }
+            if (result === document.getText()) {
+                vscode.window.showInformationMessage('没有间接依赖 (No indirect dependencies)');
+                return;
+            }
+
+            const fullRange = new vscode.Range(0, 0, document.lineCount, 0);
+            await editor.edit(editBuilder => editBuilder.replace(fullRange, result));
+        } catch (error) {
+            logger.error('提升间接依赖时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以排除光标所在 require 的某个版本；排除的正是所需版本时先确认，因为 go 命令会改用更高的版本
+ * Register command to exclude a version of the require under the cursor; excluding the required version
+ * asks for confirmation first, since the go command then moves to a higher version
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandExcludeVersion(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || path.basename(editor.document.fileName) !== 'go.mod') {
+            vscode.window.showWarningMessage('请先打开 go.mod 文件 (Please open a go.mod file first)');
+            return;
+        }
+
+        const document = editor.document;
+        try {
+            const mod = JSON.parse(await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.ParseModFunc, document.getText()));
+            if (mod.error !== undefined) {
+                vscode.window.showErrorMessage(`解析 go.mod 失败: ${mod.error}`);
+                return;
+            }
+            const line = editor.selection.active.line + 1;
+            const req = (mod.require ?? []).find((r: any) => r.start.line <= line && line <= r.end.line);
+            if (!req) {
+                vscode.window.showWarningMessage('请将光标放在 require 行上 (Place the cursor on a require line)');
+                return;
+            }
+
+            const version = await vscode.window.showInputBox({
+                prompt: `排除 ${req.path} 的版本 (Version of ${req.path} to exclude)`,
+                value: req.version
+            });
+            if (!version) {
+                return;
+            }
+
+            const data = JSON.parse(await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.AddExcludeFunc, document.getText(), req.path, version));
+            if (data.error !== undefined) {
+                vscode.window.showErrorMessage(`添加 exclude 失败: ${data.error}`);
+                return;
+            }
+            if (data.warnings.length > 0) {
+                const choice = await vscode.window.showWarningMessage(
+                    data.warnings.map((w: any) => w.message).join('\n'), { modal: true }, '排除 (Exclude)');
+                if (!choice) {
+                    return;
+                }
+            }
+            if (data.content === document.getText()) {
+                return;
+            }
+
+            const fullRange = new vscode.Range(0, 0, document.lineCount, 0);
+            await editor.edit(editBuilder => editBuilder.replace(fullRange, data.content));
+        } catch (error) {
+            logger.error('添加 exclude 时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以重命名当前 go.mod 的模块路径，同时改写引用该模块及其子路径的 replace；
+ * Go 文件中的导入路径需要另行修改
+ * Register command to rename the module path of the active go.mod, rewriting the replaces
+ * referring to the module and its sub-paths; import paths in Go files have to be updated separately
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandRenameModule(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || path.basename(editor.document.fileName) !== 'go.mod') {
+            vscode.window.showWarningMessage('请先打开 go.mod 文件 (Please open a go.mod file first)');
+            return;
+        }
+
+        const document = editor.document;
+        const oldPath = document.getText().match(/^module\s+"?([^\s"]+)"?/m)?.[1];
+        if (!oldPath) {
+            vscode.window.showWarningMessage('go.mod 中没有 module 指令 (No module directive in go.mod)');
+            return;
+        }
+
+        const newPath = await vscode.window.showInputBox({
+            prompt: '新的模块路径 (New module path)',
+            value: oldPath,
+            valueSelection: [0, oldPath.length]
+        });
+        if (!newPath || newPath === oldPath) {
+            return;
+        }
+
+        try {
+            const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.RenameModuleFunc, document.getText(), oldPath, newPath);
+            if (result.startsWith('{')) {
+                vscode.window.showErrorMessage(`重命名模块失败: ${JSON.parse(result).error}`);
+                return;
+            }
+
+            const fullRange = new vscode.Range(0, 0, document.lineCount, 0);
+            await editor.edit(editBuilder => editBuilder.replace(fullRange, result));
+            vscode.window.showInformationMessage(
+                `模块已重命名为 ${newPath}，Go 文件中的导入路径未修改 (Module renamed to ${newPath}, import paths in Go files were not updated)`
+            );
+        } catch (error) {
+            logger.error('重命名模块时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以将当前 go.mod 拆分为 go.work 工作区：选择的子目录各自成为模块，模块路径为原模块路径加子目录，
+ * 因此已有的导入路径不变；已存在的文件不会被覆盖
+ * Register command to split the active go.mod into a go.work workspace: every chosen subdirectory becomes a module
+ * whose path is the module path plus the subdirectory, so existing import paths keep working; existing files are not overwritten
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandSplitWorkspace(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || path.basename(editor.document.fileName) !== 'go.mod') {
+            vscode.window.showWarningMessage('请先打开 go.mod 文件 (Please open a go.mod file first)');
+            return;
+        }
+
+        const document = editor.document;
+        const modulePath = document.getText().match(/^module\s+"?([^\s"]+)"?/m)?.[1];
+        if (!modulePath) {
+            vscode.window.showWarningMessage('go.mod 中没有 module 指令 (No module directive in go.mod)');
+            return;
+        }
+
+        const root = path.dirname(document.fileName);
+        const folders = await vscode.window.showOpenDialog({
+            canSelectFiles: false,
+            canSelectFolders: true,
+            canSelectMany: true,
+            defaultUri: vscode.Uri.file(root),
+            openLabel: '拆分为模块 (Split into modules)'
+        });
+        if (!folders || folders.length === 0) {
+            return;
+        }
+
+        const mapping: Record<string, string> = {};
+        for (const folder of folders) {
+            const dir = path.relative(root, folder.fsPath).split(path.sep).join('/');
+            if (dir === '' || dir.startsWith('..') || path.isAbsolute(dir)) {
+                vscode.window.showWarningMessage(`${folder.fsPath} 不是模块的子目录 (is not a subdirectory of the module)`);
+                return;
+            }
+            mapping[`${modulePath}/${dir}`] = dir;
+        }
+
+        try {
+            const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.SplitWorkspaceFunc, document.getText(), JSON.stringify(mapping));
+            const data = JSON.parse(result);
+            if (data.error !== undefined) {
+                vscode.window.showErrorMessage(`拆分工作区失败: ${data.error}`);
+                return;
+            }
+
+            const files = (data as { path: string; content: string }[]).map(file => ({
+                uri: vscode.Uri.file(path.join(root, file.path)),
+                content: file.content
+            }));
+            const existing: string[] = [];
+            for (const file of files) {
+                try {
+                    await vscode.workspace.fs.stat(file.uri);
+                    existing.push(path.relative(root, file.uri.fsPath));
+                } catch {
+                    // 文件不存在
+                    // The file does not exist
+                }
+            }
+            if (existing.length > 0) {
+                vscode.window.showErrorMessage(`以下文件已存在 (These files already exist): ${existing.join(', ')}`);
+                return;
+            }
+
+            const edit = new vscode.WorkspaceEdit();
+            for (const file of files) {
+                edit.createFile(file.uri);
+                edit.insert(file.uri, new vscode.Position(0, 0), file.content);
+            }
+            await vscode.workspace.applyEdit(edit);
+            vscode.window.showInformationMessage(
+                `已生成 go.work 和 ${files.length - 1} 个 go.mod，请在每个模块中运行 go mod tidy (Generated go.work and ${files.length - 1} go.mod files, run go mod tidy in each module)`
+            );
+        } catch (error) {
+            logger.error('拆分工作区时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以复制当前 Go 文件所在包的导入路径：向上查找最近的 go.mod，由其 module 指令与相对路径得出
+ * Register command to copy the import path of the package of the active Go file: the closest go.mod
+ * is looked up in the parent directories, and the path follows from its module directive and the relative directory
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandCopyImportPath(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (uri?: vscode.Uri) => {
+        const file = uri ?? vscode.window.activeTextEditor?.document.uri;
+        if (!file || !file.fsPath.endsWith('.go')) {
+            vscode.window.showWarningMessage('请先打开 Go 文件 (Please open a Go file first)');
+            return;
+        }
+
+        try {
+            const dir = path.dirname(file.fsPath);
+            let root = dir;
+            while (!fs.existsSync(path.join(root, 'go.mod'))) {
+                const parent = path.dirname(root);
+                if (parent === root) {
+                    vscode.window.showWarningMessage('未找到 go.mod (No go.mod found)');
+                    return;
+                }
+                root = parent;
+            }
+
+            const content = fs.readFileSync(path.join(root, 'go.mod'), 'utf8');
+            const mod = JSON.parse(await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.ParseModFunc, content));
+            if (mod.error !== undefined || !mod.module) {
+                vscode.window.showErrorMessage(`解析 go.mod 失败: ${mod.error ?? 'no module directive'}`);
+                return;
+            }
+
+            const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.ResolveImportPathFunc, mod.module, root, dir);
+            const data = JSON.parse(result);
+            if (data.error !== undefined) {
+                vscode.window.showErrorMessage(`解析导入路径失败: ${data.error}`);
+                return;
+            }
+
+            await vscode.env.clipboard.writeText(data.importPath);
+            vscode.window.setStatusBarMessage(`已复制 (Copied): ${data.importPath}`, 3000);
+        } catch (error) {
+            logger.error('复制导入路径时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以生成当前 go.mod 的依赖报告，结果在新编辑器中打开，可保存为 DEPENDENCIES.md
+ * Register command to generate a dependency report of the active go.mod, opened in a new editor to be saved as DEPENDENCIES.md
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandExportDependencies(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || path.basename(editor.document.fileName) !== 'go.mod') {
+            vscode.window.showWarningMessage('请先打开 go.mod 文件 (Please open a go.mod file first)');
+            return;
+        }
+
+        try {
+            const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.RenderModMarkdownFunc, editor.document.getText());
+            if (result.startsWith('{')) {
+                vscode.window.showErrorMessage(`生成依赖报告失败: ${JSON.parse(result).error}`);
+                return;
+            }
+
+            const document = await vscode.workspace.openTextDocument({ language: 'markdown', content: result });
+            await vscode.window.showTextDocument(document, { preview: false });
+        } catch (error) {
+            logger.error('生成依赖报告时出错:', error);
+        }
+    });
+}
+
+/**
+ * 注册命令以切换到 go.mod 中 toolchain 指令要求的工具链
+ * Register command to switch to the toolchain requested by the toolchain directive of go.mod
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandSwitchToolchain(cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, (name: string, directory: string) => {
+        // GOTOOLCHAIN=<name>+auto 使用该工具链，go.mod 需要更新版本时仍会自动切换
+        // GOTOOLCHAIN=<name>+auto uses the toolchain and still switches when go.mod needs a newer one
+        const terminal = vscode.window.createTerminal('Go Toolchain');
+        terminal.show();
+        terminal.sendText(`cd "${directory}"`);
+        terminal.sendText(`go env -w GOTOOLCHAIN=${name}+auto && go version`);
+    });
+}
+
 /**
  * 注册 Go Library 相关命令
  * @param context 扩展上下文
@@ -1,6 +1,27 @@
 import * as vscode from 'vscode';
 import * as path from 'path';
 import { InterfaceInfo, ImplementationInfo, MethodImplementationInfo } from '../types';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+import { readWorkspaceGoFiles } from '../core/navigator/implementation';
+import { showImplementationMatrix } from '../core/navigator/matrix';
+import { IsGoFile } from '../pkg/cond';
+
+/**
+ * 展开后的接口方法集（由 WASM 返回）
+ * Expanded interface method set (returned by WASM)
+ */
+interface MethodSet {
+    interface: string;        // 接口名称
+    package: string;          // 接口所在包
+    methods: {
+        name: string;         // 方法名称
+        signature: string;    // 方法签名
+        from: string;         // 声明方法的接口
+        path: string;
+        line: number;
+    }[];
+    unresolved: string[];     // 工作空间之外、无法展开的嵌入接口
+}
 
 /**
  * 注册命令以跳转到接口定义
@@ -155,3 +176,244 @@ export function registerCommandListMethodImplementations(cmd: string): vscode.Di
         }
     });
 }
+
+/**
+ * 注册命令以查看光标处接口的完整方法集，包括嵌入接口提升的方法
+ * Register command to peek the full method set of the interface under the cursor, promoted methods included
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandShowInterfaceMethodSet(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        const range = editor?.document.getWordRangeAtPosition(editor.selection.active, /[\w.]+/);
+        if (!editor || !range) {
+            vscode.window.showWarningMessage('请将光标放在接口名称上 (Place the cursor on an interface name)');
+            return;
+        }
+
+        const files = await readWorkspaceGoFiles();
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.InterfaceMethodSetFunc,
+            JSON.stringify(files),
+            editor.document.fileName,
+            editor.document.getText(range)
+        );
+
+        const data = JSON.parse(result);
+        if (data.error !== undefined) {
+            vscode.window.showErrorMessage(`展开接口方法集失败: ${data.error}`);
+            return;
+        }
+
+        const methodSet = data as MethodSet;
+        const items = methodSet.methods.map(method => ({
+            label: method.signature,
+            description: method.from,
+            detail: method.path ? `${path.basename(method.path)}:${method.line}` : undefined,
+            method
+        }));
+        const unresolved = methodSet.unresolved.length > 0 ? ` (unresolved: ${methodSet.unresolved.join(', ')})` : '';
+
+        const selected = await vscode.window.showQuickPick(items, {
+            placeHolder: `Method set of ${methodSet.interface}${unresolved}`
+        });
+        if (selected && selected.method.path) {
+            const document = await vscode.workspace.openTextDocument(vscode.Uri.file(selected.method.path));
+            const position = new vscode.Position(selected.method.line - 1, 0);
+            await vscode.window.showTextDocument(document, {
+                selection: new vscode.Range(position, position)
+            });
+        }
+    });
+}
+
+/**
+ * 两个接口的相互可赋值性（由 WASM 返回）
+ * Assignability between two interfaces (returned by WASM)
+ */
+interface InterfaceAssignability {
+    a: string;
+    b: string;
+    aToB: boolean;            // A 的值可以用在需要 B 的位置
+    bToA: boolean;            // B 的值可以用在需要 A 的位置
+    equivalent: boolean;      // 方法集相同，可以合并
+    missingInA: MethodGap[];  // 使 A 无法赋值给 B 的 B 的方法
+    missingInB: MethodGap[];  // 使 B 无法赋值给 A 的 A 的方法
+    unresolved: string[];     // 工作空间之外、方法未知的嵌入接口
+}
+
+/**
+ * 一个接口需要、另一个接口缺少或签名不同的方法
+ * Method one interface expects and the other lacks or declares with another signature
+ */
+interface MethodGap {
+    name: string;
+    want: string;             // 需要的签名
+    have: string;             // 同名方法的签名，没有时为空
+    path: string;
+    line: number;
+}
+
+/**
+ * 注册命令以比较光标处的接口与输入的接口，判断二者能否相互替代使用，用于合并相似的接口
+ * Register command to compare the interface under the cursor with another one, telling whether either
+ * can be used where the other is expected, which helps to consolidate similar interfaces
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandCompareInterfaces(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        const range = editor?.document.getWordRangeAtPosition(editor.selection.active, /[\w.]+/);
+        if (!editor || !range) {
+            vscode.window.showWarningMessage('请将光标放在接口名称上 (Place the cursor on an interface name)');
+            return;
+        }
+
+        const other = await vscode.window.showInputBox({
+            prompt: `与 ${editor.document.getText(range)} 比较的接口 (Interface to compare with)`,
+            placeHolder: 'Greeter or example.com/greet.Greeter'
+        });
+        if (!other) {
+            return;
+        }
+
+        const files = await readWorkspaceGoFiles();
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.CompareInterfacesFunc,
+            JSON.stringify(files),
+            editor.document.fileName,
+            editor.document.getText(range),
+            other
+        );
+
+        const data = JSON.parse(result);
+        if (data.error !== undefined) {
+            vscode.window.showErrorMessage(`比较接口失败: ${data.error}`);
+            return;
+        }
+
+        const cmp = data as InterfaceAssignability;
+        let verdict = `${cmp.a} and ${cmp.b} are not assignable to each other`;
+        if (cmp.equivalent) {
+            verdict = `${cmp.a} and ${cmp.b} are equivalent`;
+        } else if (cmp.aToB) {
+            verdict = `${cmp.a} can be used as ${cmp.b}`;
+        } else if (cmp.bToA) {
+            verdict = `${cmp.b} can be used as ${cmp.a}`;
+        }
+        if (cmp.unresolved.length > 0) {
+            verdict += ` (unresolved: ${cmp.unresolved.join(', ')})`;
+        }
+
+        const toItem = (gap: MethodGap, from: string, to: string) => ({
+            label: gap.want,
+            description: gap.have ? `${from} has ${gap.have}` : `missing in ${from}`,
+            detail: `needed for ${from} as ${to}`,
+            gap
+        });
+        const items = [
+            ...(cmp.aToB ? [] : cmp.missingInA.map(gap => toItem(gap, cmp.a, cmp.b))),
+            ...(cmp.bToA ? [] : cmp.missingInB.map(gap => toItem(gap, cmp.b, cmp.a)))
+        ];
+        if (items.length === 0) {
+            vscode.window.showInformationMessage(verdict);
+            return;
+        }
+
+        const selected = await vscode.window.showQuickPick(items, { placeHolder: verdict });
+        if (selected && selected.gap.path) {
+            const document = await vscode.workspace.openTextDocument(vscode.Uri.file(selected.gap.path));
+            const position = new vscode.Position(selected.gap.line - 1, 0);
+            await vscode.window.showTextDocument(document, {
+                selection: new vscode.Range(position, position)
+            });
+        }
+    });
+}
+
+/**
+ * 通过接口值使用接口方法的位置（由 WASM 返回）
+ * Use of an interface method through an interface value (returned by WASM)
+ */
+interface InterfaceCall {
+    path: string;
+    start: { line: number; column: number };  // 方法名起始位置
+    end: { line: number; column: number };    // 方法名结束位置
+    receiver: string;         // 接收者的静态类型
+    function: string;         // 所在函数，包级别时为空
+    call: boolean;            // 是否为调用，而不是方法值
+}
+
+/**
+ * 注册命令以在引用视图中列出通过接口值调用接口方法的位置，光标需位于 Greeter.SayHello 这样的限定方法名上
+ * Register command to list the uses of an interface method through interface values in the references view,
+ * with the cursor on a qualified method name such as Greeter.SayHello
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandFindInterfaceMethodUsages(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        const range = editor?.document.getWordRangeAtPosition(editor.selection.active, /[\w.]+/);
+        const name = editor && range ? editor.document.getText(range) : '';
+        const dot = name.lastIndexOf('.');
+        if (!editor || dot <= 0) {
+            vscode.window.showWarningMessage('请将光标放在接口方法名称上，例如 Greeter.SayHello (Place the cursor on an interface method such as Greeter.SayHello)');
+            return;
+        }
+
+        const files = await readWorkspaceGoFiles();
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.FindInterfaceCallsFunc,
+            JSON.stringify(files),
+            editor.document.fileName,
+            name.slice(0, dot),
+            name.slice(dot + 1)
+        );
+
+        const data = JSON.parse(result);
+        if (data.error !== undefined) {
+            vscode.window.showErrorMessage(`查找接口方法的使用失败: ${data.error}`);
+            return;
+        }
+
+        const calls = data as InterfaceCall[];
+        if (calls.length === 0) {
+            vscode.window.showInformationMessage(`No uses of ${name} through an interface value found`);
+            return;
+        }
+        const locations = calls.map(call => new vscode.Location(
+            vscode.Uri.file(call.path),
+            new vscode.Range(call.start.line - 1, call.start.column - 1, call.end.line - 1, call.end.column - 1)
+        ));
+        await vscode.commands.executeCommand('editor.action.showReferences',
+            editor.document.uri, editor.selection.active, locations);
+    });
+}
+
+/**
+ * 注册命令以显示当前包的接口实现矩阵
+ * Register command to show the implementation matrix of the current package
+ * @param ctx 扩展上下文 (extension context)
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandShowImplementationMatrix(ctx: vscode.ExtensionContext, cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || !IsGoFile(editor.document)) {
+            vscode.window.showWarningMessage('请先打开 Go 文件 (Please open a Go file first)');
+            return;
+        }
+
+        try {
+            await showImplementationMatrix(ctx, editor.document);
+        } catch (error) {
+            vscode.window.showErrorMessage(`生成接口实现矩阵失败: ${error}`);
+        }
+    });
+}
@@ -1,5 +1,6 @@
 import * as vscode from 'vscode';
 import * as path from 'path';
+import { testFlags, TestKind } from '../core/run/test';
 
 /**
  * 注册运行main函数命令
@@ -49,6 +50,76 @@ export function registerCommandSetMainArgs(ctx: vscode.ExtensionContext, cmd: st
     });
 }
 
+/**
+ * 注册运行测试函数命令
+ * Register run test function command
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandRunTest(cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (fileUri: vscode.Uri, name: string, kind: TestKind) => {
+        await runGoTest(fileUri, name, kind, false);
+    });
+}
+
+/**
+ * 注册调试测试函数命令
+ * Register debug test function command
+ * @param cmd 命令名称 (command name)
+ */
+export function registerCommandDebugTest(cmd: string): vscode.Disposable {
+    return vscode.commands.registerCommand(cmd, async (fileUri: vscode.Uri, name: string, kind: TestKind) => {
+        await runGoTest(fileUri, name, kind, true);
+    });
+}
+
+/**
+ * 运行或调试单个测试函数
+ * Run or debug a single test function
+ * @param fileUri 测试文件URI (test file URI)
+ * @param name 测试完整名称 (full test name)
+ * @param kind 测试类型 (test kind)
+ * @param debug 是否进入调试模式 (debug mode or not)
+ */
+async function runGoTest(fileUri: vscode.Uri, name: string, kind: TestKind, debug: boolean): Promise<void> {
+    if (!fileUri || !name) {
+        vscode.window.showErrorMessage('无法运行测试: 未提供测试名称');
+        return;
+    }
+
+    const directory = path.dirname(fileUri.fsPath);
+
+    if (debug) {
+        // 使用VS Code内置Go调试器以测试模式启动调试
+        // Use VS Code's built-in Go debugger to start debugging in test mode
+        const debugConfig = {
+            type: 'go',
+            name: name,
+            request: 'launch',
+            mode: 'test',
+            program: directory,
+            args: testFlags(name, kind, '-test.'),
+            cwd: directory
+        };
+
+        vscode.debug.startDebugging(undefined, debugConfig);
+    } else {
+        // 创建终端
+        // Create terminal
+        const terminal = vscode.window.createTerminal('Go Test ' + name);
+
+        // 切换到文件所在目录
+        // Change to file directory
+        terminal.sendText(`cd "${directory}"`);
+
+        // 匹配模式用单引号包裹，避免 shell 展开
+        // Quote patterns with single quotes to avoid shell expansion
+        const flags = testFlags(name, kind).map(flag => flag.startsWith('-') ? flag : `'${flag.replace(/'/g, `'\\''`)}'`);
+        terminal.sendText(`go test -v ${flags.join(' ')} .`);
+
+        terminal.show();
+    }
+}
+
 /**
  * 设置Go文件运行参数
  * Set Go file run arguments
@@ -0,0 +1,83 @@
+import * as vscode from 'vscode';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+import { readWorkspaceGoFiles } from '../navigator/implementation';
+
+/**
+ * WASM 根据具体类型生成的接口
+ * Interface generated by WASM from a concrete type
+ */
+export interface GoExtractedInterface {
+    path: string;             // 接收接口的文件
+    line: number;             // 插入位置之前的行（从 1 开始），0 表示追加到文件末尾
+    text: string;             // 接口声明及其文档注释
+    imports: string[];        // 文件中缺少的导入路径
+    methods: string[];        // 提取的方法名称
+    warnings: {
+        method: string;
+        types: string[];      // 签名中的未导出类型
+        message: string;
+        line: number;         // 方法声明所在行
+    }[];
+}
+
+/**
+ * 根据类型的导出方法生成接口
+ * Generate an interface from the exported methods of a type
+ * @param ctx 扩展上下文 (extension context)
+ * @param filePath 类型所在文件 (file declaring the type)
+ * @param typeName 类型名称 (type name)
+ * @param interfaceName 接口名称 (interface name)
+ * @param targetPath 接收接口的文件，默认放在类型上方 (file receiving the interface, above the type by default)
+ */
+export async function extractInterface(
+    ctx: vscode.ExtensionContext,
+    filePath: string,
+    typeName: string,
+    interfaceName: string,
+    targetPath?: string
+): Promise<GoExtractedInterface> {
+    const files = await readWorkspaceGoFiles();
+    const result = await WasmExecutor.callFunction<string>(
+        ctx,
+        GoWasmFunction.ExtractInterfaceFunc,
+        JSON.stringify(files),
+        filePath,
+        typeName,
+        interfaceName,
+        targetPath ?? ''
+    );
+
+    const data = JSON.parse(result);
+    if (data.error) {
+        throw new Error(data.error);
+    }
+    return data as GoExtractedInterface;
+}
+
+/**
+ * 插入提取的接口，并在 package 子句后补充缺少的导入
+ * Insert an extracted interface and add missing imports after the package clause
+ * @param extracted 提取的接口 (extracted interface)
+ */
+export async function applyExtractedInterface(extracted: GoExtractedInterface): Promise<void> {
+    const uri = vscode.Uri.file(extracted.path);
+    const document = await vscode.workspace.openTextDocument(uri);
+    const edit = new vscode.WorkspaceEdit();
+
+    if (extracted.imports.length > 0) {
+        const packageLine = document.getText().split('\n').findIndex(line => /^package\s+\w+/.test(line));
+        if (packageLine >= 0) {
+            const imports = extracted.imports.map(p => `import "${p}"`).join('\n');
+            edit.insert(uri, new vscode.Position(packageLine + 1, 0), `\n${imports}\n`);
+        }
+    }
+
+    const position = extracted.line > 0
+        ? new vscode.Position(extracted.line - 1, 0)
+        : document.lineAt(document.lineCount - 1).range.end;
+    edit.insert(uri, position, extracted.text);
+
+    await vscode.workspace.applyEdit(edit);
+    const editor = await vscode.window.showTextDocument(document);
+    editor.revealRange(new vscode.Range(position, position), vscode.TextEditorRevealType.InCenterIfOutsideViewport);
+}
@@ -0,0 +1,77 @@
+import * as vscode from 'vscode';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+import { ImplementationIndex, readWorkspaceGoFiles } from '../navigator/implementation';
+
+/**
+ * WASM 生成的桩方法
+ * Stub methods generated by WASM
+ */
+export interface GoStubs {
+    path: string;             // 插入桩方法的文件
+    text: string;             // 追加到文件末尾的源码
+    imports: string[];        // 文件中缺少的导入路径
+}
+
+/**
+ * 将桩方法追加到文件末尾，并在 package 子句后补充缺少的导入
+ * Append stubs to the end of the file and add missing imports after the package clause
+ * @param stubs 桩方法 (stubs)
+ */
+export async function applyStubs(stubs: GoStubs): Promise<void> {
+    const uri = vscode.Uri.file(stubs.path);
+    const document = await vscode.workspace.openTextDocument(uri);
+    const edit = new vscode.WorkspaceEdit();
+
+    if (stubs.imports.length > 0) {
+        const packageLine = document.getText().split('\n').findIndex(line => /^package\s+\w+/.test(line));
+        if (packageLine >= 0) {
+            const imports = stubs.imports.map(p => `import "${p}"`).join('\n');
+            edit.insert(uri, new vscode.Position(packageLine + 1, 0), `\n${imports}\n`);
+        }
+    }
+
+    const end = document.lineAt(document.lineCount - 1).range.end;
+    edit.insert(uri, end, stubs.text);
+
+    await vscode.workspace.applyEdit(edit);
+    await vscode.window.showTextDocument(document);
+}
+
+/**
+ * 为类型生成尚未实现的接口方法，方法体为 panic("not implemented")
+ * Generate the interface methods a type does not implement yet, with panic("not implemented") bodies
+ * @param ctx 扩展上下文 (extension context)
+ * @param filePath 类型所在文件 (file declaring the type)
+ * @param typeName 类型名称 (type name)
+ * @param interfacePath 接口，形如 example.com/greet.Greeter (interface, e.g. example.com/greet.Greeter)
+ */
+export async function implementInterface(
+    ctx: vscode.ExtensionContext,
+    filePath: string,
+    typeName: string,
+    interfacePath: string
+): Promise<void> {
+    const files = await readWorkspaceGoFiles();
+    const result = await WasmExecutor.callFunction<string>(
+        ctx,
+        GoWasmFunction.GenerateStubsFunc,
+        JSON.stringify(files),
+        filePath,
+        typeName,
+        interfacePath
+    );
+
+    const data = JSON.parse(result);
+    if (data.error) {
+        throw new Error(data.error);
+    }
+
+    const stubs = data as GoStubs;
+    if (stubs.text === '') {
+        vscode.window.showInformationMessage(`${typeName} already implements ${interfacePath}`);
+        return;
+    }
+
+    await applyStubs(stubs);
+    ImplementationIndex.invalidate();
+}
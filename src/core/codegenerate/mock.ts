@@ -0,0 +1,76 @@
+import * as vscode from 'vscode';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+import { readWorkspaceGoFiles } from '../navigator/implementation';
+
+/**
+ * WASM 生成的接口 mock
+ * Interface mock generated by WASM
+ */
+export interface GoMock {
+    path: string;             // 建议的文件路径 <dir>/mock_<interface>.go
+    package: string;          // 文件的包名
+    name: string;             // mock 类型名称
+    text: string;             // 完整的文件源码，包括 package 子句和导入
+}
+
+/**
+ * 为接口生成 mock：每个方法对应一个函数字段，方法委托给这些字段
+ * Generate a mock of an interface: one func field per method, with methods delegating to them
+ * @param ctx 扩展上下文 (extension context)
+ * @param filePath 接口所在文件 (file declaring the interface)
+ * @param interfaceName 接口名称 (interface name)
+ * @param dir 放置 mock 的包目录 (directory of the package receiving the mock)
+ * @param mockName mock 类型名称，默认为 Mock 加接口名 (mock type name, Mock + interface name by default)
+ */
+export async function generateMock(
+    ctx: vscode.ExtensionContext,
+    filePath: string,
+    interfaceName: string,
+    dir: string,
+    mockName?: string
+): Promise<GoMock> {
+    const files = await readWorkspaceGoFiles();
+    const result = await WasmExecutor.callFunction<string>(
+        ctx,
+        GoWasmFunction.GenerateMockFunc,
+        JSON.stringify(files),
+        filePath,
+        interfaceName,
+        dir,
+        mockName ?? ''
+    );
+
+    const data = JSON.parse(result);
+    if (data.error) {
+        throw new Error(data.error);
+    }
+    return data as GoMock;
+}
+
+/**
+ * 将 mock 写入文件，文件已存在时先确认是否覆盖
+ * Write a mock to its file, asking before overwriting an existing file
+ * @param mock 生成的 mock (generated mock)
+ * @returns 是否已写入 (whether the file was written)
+ */
+export async function writeMock(mock: GoMock): Promise<boolean> {
+    const uri = vscode.Uri.file(mock.path);
+    try {
+        await vscode.workspace.fs.stat(uri);
+        const overwrite = await vscode.window.showWarningMessage(
+            `${mock.path} 已存在，是否覆盖？(${mock.path} already exists, overwrite it?)`,
+            { modal: true },
+            '覆盖 (Overwrite)'
+        );
+        if (!overwrite) {
+            return false;
+        }
+    } catch {
+        // 文件不存在
+        // The file does not exist
+    }
+
+    await vscode.workspace.fs.writeFile(uri, Buffer.from(mock.text, 'utf-8'));
+    await vscode.window.showTextDocument(await vscode.workspace.openTextDocument(uri));
+    return true;
+}
@@ -0,0 +1,118 @@
+import * as vscode from 'vscode';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+
+/**
+ * 标签值命名风格
+ * Case style of tag values
+ */
+export type TagCase = 'snake' | 'camel' | 'kebab';
+
+/**
+ * WASM 返回的单行标签编辑，列为 UTF-16 偏移
+ * Single-line tag edit returned by WASM, columns are UTF-16 offsets
+ */
+interface TagEdit {
+    line: number;             // 行号（从 1 开始）
+    start: number;            // 起始列
+    end: number;              // 结束列
+    text: string;             // 替换文本
+}
+
+/**
+ * 读取结构体标签配置
+ * Read the struct tag settings
+ */
+export function tagSettings(): { caseStyle: TagCase; skipEmbedded: boolean; skipUnexported: boolean } {
+    const config = vscode.workspace.getConfiguration('gopp.structTags');
+    return {
+        caseStyle: config.get<TagCase>('caseStyle', 'snake'),
+        skipEmbedded: config.get<boolean>('skipEmbedded', true),
+        skipUnexported: config.get<boolean>('skipUnexported', true)
+    };
+}
+
+/**
+ * 为结构体中尚未带有该标签的字段添加标签，保留已有标签
+ * Add a tag to the struct fields that do not carry it yet, keeping existing tags
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 结构体所在文档 (document declaring the struct)
+ * @param structName 结构体名称，为空时使用行号查找 (struct name, the line is used when empty)
+ * @param line 结构体内的行号，从 1 开始 (line inside the struct, 1-based)
+ * @param tag 标签名称，如 json (tag name, e.g. json)
+ * @param caseStyle 标签值命名风格 (case style of the tag values)
+ * @returns 添加的标签数 (number of tags added)
+ */
+export async function generateStructTags(
+    ctx: vscode.ExtensionContext,
+    document: vscode.TextDocument,
+    structName: string,
+    line: number,
+    tag: string,
+    caseStyle: TagCase
+): Promise<number> {
+    const { skipEmbedded, skipUnexported } = tagSettings();
+    const result = await WasmExecutor.callFunction<string>(
+        ctx,
+        GoWasmFunction.GenerateStructTagsFunc,
+        document.getText(),
+        structName,
+        line,
+        JSON.stringify({ tag, case: caseStyle, skipEmbedded, skipUnexported })
+    );
+
+    const data = JSON.parse(result);
+    if (!Array.isArray(data)) {
+        throw new Error(data.error);
+    }
+
+    return applyTagEdits(document, data as TagEdit[]);
+}
+
+/**
+ * 与 gofmt 相同地对齐字段的名称、类型、标签与行尾注释，空行保持不变
+ * Align the names, types, tags and trailing comments of struct fields the way gofmt does, keeping blank lines
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 结构体所在文档 (document declaring the struct)
+ * @param structName 结构体名称，为空时使用行号查找 (struct name, the line is used when empty)
+ * @param line 结构体内的行号，从 1 开始 (line inside the struct, 1-based)
+ * @returns 改变的行数 (number of lines changed)
+ */
+export async function alignStructFields(
+    ctx: vscode.ExtensionContext,
+    document: vscode.TextDocument,
+    structName: string,
+    line: number
+): Promise<number> {
+    const result = await WasmExecutor.callFunction<string>(
+        ctx,
+        GoWasmFunction.AlignStructFieldsFunc,
+        document.getText(),
+        structName,
+        line
+    );
+
+    const data = JSON.parse(result);
+    if (!Array.isArray(data)) {
+        throw new Error(data.error);
+    }
+
+    return applyTagEdits(document, data as TagEdit[]);
+}
+
+/**
+ * 应用 WASM 返回的单行编辑
+ * Apply the single-line edits returned by WASM
+ * @param document 结构体所在文档 (document declaring the struct)
+ * @param edits 单行编辑 (single-line edits)
+ * @returns 编辑数 (number of edits)
+ */
+async function applyTagEdits(document: vscode.TextDocument, edits: TagEdit[]): Promise<number> {
+    if (edits.length > 0) {
+        const edit = new vscode.WorkspaceEdit();
+        for (const e of edits) {
+            edit.replace(document.uri, new vscode.Range(e.line - 1, e.start, e.line - 1, e.end), e.text);
+        }
+        await vscode.workspace.applyEdit(edit);
+    }
+    return edits.length;
+}
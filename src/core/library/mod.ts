@@ -36,17 +36,110 @@ export interface ModSimpleInfo {
     Module: string; // Module name 模块名称
     Version: string; // Module version 模块版本
     Indirect: boolean; // Indicates if it's an indirect dependency 是否是间接依赖
+    Block?: number; // Index of the require block 所属 require 块的序号
+    Comment?: string; // Comments attached to the line 行上附加的注释
+    PseudoTime?: string; // Commit time of a pseudo-version (RFC 3339) 伪版本的提交时间
+    PseudoRev?: string; // Commit hash of a pseudo-version 伪版本的提交哈希
+    Incompatible?: boolean; // +incompatible version, the module has no go.mod for its major version +incompatible 版本，模块在该主版本没有 go.mod
+    Invalid?: boolean; // Whether the module path is malformed 模块路径是否不合法
+    InvalidReason?: string; // Why the module path is malformed 模块路径不合法的原因
+    Category?: string; // extended-stdlib, internal or third-party 模块类别
+    ExcludedVersions?: string[]; // Versions excluded by exclude directives, requires only 被 exclude 指令排除的版本，仅 require 条目有
+    Start?: ModPosition; // Start of the directive line 指令行起始位置
+    End?: ModPosition; // End of the directive line 指令行结束位置
+}
+
+// go.mod 中的位置 (从 1 开始)
+// Position in go.mod (1-based)
+export interface ModPosition {
+    Line: number; // Line number 行号
+    Column: number; // Column (rune offset) 列号 (字符偏移)
 }
 
 // 解析 go.mod 文件的信息
 export interface ModFileInfo {
     Module: string; // Module name 模块名称
+    MajorVersion: number; // Major version from the module path suffix, 1 without one 模块路径后缀中的主版本号，无后缀时为 1
     Go: string; // Go version used by the module 模块使用的 Go 版本
+    GoVersionValid: boolean; // Whether the go version is well-formed Go 版本格式是否合法
+    GoStart?: ModPosition; // Position of the go directive go 指令的位置
     Toolchain: string; // toolchain go1.21
+    ToolchainVersion: string; // Go version of the toolchain, empty when invalid or default 工具链的 Go 版本，无效或为 default 时为空
+    ToolchainStart?: ModPosition; // Position of the toolchain directive toolchain 指令的位置
+    Godebug: ModGodebugInfo[]; // godebug settings godebug 设置
     Require: ModSimpleInfo[]; // Required modules 依赖的模块
-    Replace: ModSimpleInfo[]; // Replaced modules 替换的模块
+    Replace: ModReplaceInfo[]; // Replaced modules 替换的模块
     Exclude: ModSimpleInfo[]; // Excluded modules 排除的模块
-    Tool: ModSimpleInfo[]; // Tool used for the module 模块使用的工具
+    ExcludeOnly: ModExcludeGroup[]; // Excluded modules that are not required 被排除但未被依赖的模块
+    Tool: ModToolInfo[]; // Tool used for the module 模块使用的工具
+    Ignore: string[]; // Directories ignored by the go command, as written 被 go 命令忽略的目录，保持原样
+    Retract: ModRetractInfo[]; // Retracted versions 撤回的版本
+    Warnings: ModWarning[]; // Problems found while parsing 解析时发现的问题
+    BlockComments: Record<number, ModBlockComment>; // Comments of require blocks keyed by block index require 块的注释，以块序号为键
+}
+
+// require 块的注释原文，包括 //，用于重建 go.mod 时保留
+// Comments of a require block as written, // included, kept when go.mod is rebuilt
+export interface ModBlockComment {
+    line: number; // Line of the require keyword require 关键字所在行
+    before: string[]; // Comment lines above the block 块上方的注释行
+    suffix: string[]; // Comment after "require (" "require (" 之后的注释
+}
+
+// 未被 require 的模块被排除的版本
+// Excluded versions of a module that is not required
+export interface ModExcludeGroup {
+    Module: string; // Module path 模块路径
+    Versions: string[]; // Excluded versions in file order 按文件顺序排列的被排除版本
+}
+
+// tool 指令及其命令名称
+// Tool directive and the name of its command
+export interface ModToolInfo {
+    Module: string; // Package path 包路径
+    Name: string; // Command name for go tool, e.g. stringer go tool 使用的命令名称
+    Line: number; // Line of the directive 指令所在行
+    Category?: string; // extended-stdlib, internal or third-party 包类别
+    Start?: ModPosition; // Start of the directive line 指令行起始位置
+    End?: ModPosition; // End of the directive line 指令行结束位置
+}
+
+// go.mod 中不影响解析的问题
+// Problem in go.mod that does not prevent parsing
+export interface ModWarning {
+    Kind: string; // Warning kind, e.g. duplicate-require 警告类型
+    Message: string; // Warning message 警告信息
+    Line: number; // 1-based line number 行号 (从 1 开始)
+}
+
+// godebug key=value 设置
+// godebug key=value setting
+export interface ModGodebugInfo {
+    Key: string; // Setting name, e.g. panicnil 设置名称
+    Value: string; // Setting value 设置值
+    Start?: ModPosition; // Start of the directive line 指令行起始位置
+}
+
+// replace 指令: Old => New
+// Replace directive: Old => New
+export interface ModReplaceInfo {
+    OldPath: string; // Replaced module path 被替换的模块路径
+    OldVersion: string; // Replaced version, empty for wildcard 被替换的版本，通配时为空
+    NewPath: string; // Replacement path 替换后的路径
+    NewVersion: string; // Replacement version, empty for local path 替换后的版本，本地路径时为空
+    Kind: string; // local or module 本地路径或模块
+    Invalid?: boolean; // Whether the old or module target path is malformed 被替换路径或模块目标路径是否不合法
+    InvalidReason?: string; // Why the path is malformed 路径不合法的原因
+    Start?: ModPosition; // Start of the directive line 指令行起始位置
+}
+
+// 撤回的版本区间，单个版本时 Low === High
+// Retracted version range, Low === High for a single version
+export interface ModRetractInfo {
+    Low: string; // Lowest retracted version 撤回区间下限
+    High: string; // Highest retracted version 撤回区间上限
+    Rationale: string; // Reason for the retraction 撤回原因
+    Start?: ModPosition; // Start of the directive line 指令行起始位置
 }
 
 export const enum ModType {
@@ -226,24 +319,44 @@ export class GoModule {
             // Handle case inconsistency issues between field names
             const fileInfo: ModFileInfo = {
                 Module: rawData.module || rawData.Module || '',
+                MajorVersion: rawData.majorVersion ?? rawData.MajorVersion ?? 1,
                 Go: rawData.go || rawData.Go || '',
+                GoVersionValid: rawData.goVersionValid || rawData.GoVersionValid || false,
+                GoStart: this.normalizePosition(rawData.goStart || rawData.GoStart),
                 Toolchain: rawData.toolchain || rawData.Toolchain || '',
+                ToolchainVersion: rawData.toolchainVersion || rawData.ToolchainVersion || '',
+                ToolchainStart: this.normalizePosition(rawData.toolchainStart || rawData.ToolchainStart),
+                Godebug: this.normalizeGodebug(rawData.godebug || rawData.Godebug),
                 Require: this.normalizeArray(rawData.require || rawData.Require),
-                Replace: this.normalizeArray(rawData.replace || rawData.Replace),
+                Replace: this.normalizeReplace(rawData.replace || rawData.Replace),
                 Exclude: this.normalizeArray(rawData.exclude || rawData.Exclude),
-                Tool: this.normalizeArray(rawData.tool || rawData.Tool)
+                ExcludeOnly: this.normalizeExcludeGroups(rawData.excludeOnly || rawData.ExcludeOnly),
+                Tool: this.normalizeTools(rawData.tool || rawData.Tool),
+                Ignore: rawData.ignore || rawData.Ignore || [],
+                Retract: this.normalizeRetract(rawData.retract || rawData.Retract),
+                Warnings: this.normalizeWarnings(rawData.warnings || rawData.Warnings),
+                BlockComments: rawData.blockComments || rawData.BlockComments || {}
             };
             return fileInfo;
         } catch (error) {
             logger.error(`解析 go.mod 文件失败 ${goMod}: ${error}`);
             return {
                 Module: '',
+                MajorVersion: 1,
                 Go: '',
+                GoVersionValid: false,
                 Toolchain: '',
+                ToolchainVersion: '',
+                Godebug: [],
                 Require: [],
                 Replace: [],
                 Exclude: [],
-                Tool: []
+                ExcludeOnly: [],
+                Tool: [],
+                Ignore: [],
+                Retract: [],
+                Warnings: [],
+                BlockComments: {}
             };
         }
     }
@@ -258,18 +371,136 @@ export class GoModule {
         return arr.map(item => ({
             Module: item.path || item.Path || '',
             Version: item.version || item.Version || '',
-            Indirect: item.indirect || item.Indirect || false
+            Indirect: item.indirect || item.Indirect || false,
+            Block: item.block || item.Block || 0,
+            Comment: item.comment || item.Comment || '',
+            PseudoTime: item.pseudoTime || item.PseudoTime || '',
+            PseudoRev: item.pseudoRev || item.PseudoRev || '',
+            Incompatible: item.incompatible || item.Incompatible || false,
+            Invalid: item.invalid || item.Invalid || false,
+            InvalidReason: item.invalidReason || item.InvalidReason || '',
+            Category: item.category || item.Category || '',
+            ExcludedVersions: item.excludedVersions || item.ExcludedVersions || [],
+            Start: this.normalizePosition(item.start || item.Start),
+            End: this.normalizePosition(item.end || item.End)
+        }));
+    }
+
+    // 标准化仅排除模块格式
+    // Normalize excludes-only format
+    private normalizeExcludeGroups(arr: any[] | undefined): ModExcludeGroup[] {
+        if (!arr || !Array.isArray(arr)) {
+            return [];
+        }
+
+        return arr.map(item => ({
+            Module: item.path || item.Path || '',
+            Versions: item.versions || item.Versions || []
+        }));
+    }
+
+    // 标准化 tool 格式
+    // Normalize tool format
+    private normalizeTools(arr: any[] | undefined): ModToolInfo[] {
+        if (!arr || !Array.isArray(arr)) {
+            return [];
+        }
+
+        return arr.map(item => ({
+            Module: item.path || item.Path || '',
+            Name: item.name || item.Name || '',
+            Line: item.line || item.Line || 0,
+            Category: item.category || item.Category || '',
+            Start: this.normalizePosition(item.start || item.Start),
+            End: this.normalizePosition(item.end || item.End)
+        }));
+    }
+
+    // 标准化 godebug 格式
+    // Normalize godebug format
+    private normalizeGodebug(arr: any[] | undefined): ModGodebugInfo[] {
+        if (!arr || !Array.isArray(arr)) {
+            return [];
+        }
+
+        return arr.map(item => ({
+            Key: item.key || item.Key || '',
+            Value: item.value || item.Value || '',
+            Start: this.normalizePosition(item.start || item.Start)
+        }));
+    }
+
+    // 标准化替换格式
+    // Normalize replace format
+    private normalizeReplace(arr: any[] | undefined): ModReplaceInfo[] {
+        if (!arr || !Array.isArray(arr)) {
+            return [];
+        }
+
+        return arr.map(item => ({
+            OldPath: item.oldPath || item.OldPath || '',
+            OldVersion: item.oldVersion || item.OldVersion || '',
+            NewPath: item.newPath || item.NewPath || '',
+            NewVersion: item.newVersion || item.NewVersion || '',
+            Kind: item.kind || item.Kind || '',
+            Invalid: item.invalid || item.Invalid || false,
+            InvalidReason: item.invalidReason || item.InvalidReason || '',
+            Start: this.normalizePosition(item.start || item.Start)
         }));
     }
 
+    // 标准化撤回版本格式
+    // Normalize retract format
+    private normalizeRetract(arr: any[] | undefined): ModRetractInfo[] {
+        if (!arr || !Array.isArray(arr)) {
+            return [];
+        }
+
+        return arr.map(item => ({
+            Low: item.low || item.Low || '',
+            High: item.high || item.High || '',
+            Rationale: item.rationale || item.Rationale || '',
+            Start: this.normalizePosition(item.start || item.Start)
+        }));
+    }
+
+    // 标准化警告格式
+    // Normalize warning format
+    private normalizeWarnings(arr: any[] | undefined): ModWarning[] {
+        if (!arr || !Array.isArray(arr)) {
+            return [];
+        }
+
+        return arr.map(item => ({
+            Kind: item.kind || item.Kind || '',
+            Message: item.message || item.Message || '',
+            Line: item.line || item.Line || 0
+        }));
+    }
+
+    // 标准化位置格式
+    // Normalize position format
+    private normalizePosition(pos: any): ModPosition | undefined {
+        if (!pos) {
+            return undefined;
+        }
+
+        return {
+            Line: pos.line || pos.Line || 0,
+            Column: pos.column || pos.Column || 0
+        };
+    }
+
     private async callGoFunction(ctx: vscode.ExtensionContext, content: string): Promise<string> {
         try {
-            // 使用枚举类型来指定函数名
-            // Use enum type to specify function name
+            // 使用枚举类型来指定函数名，并传入设置中的模块类别规则
+            // Use enum type to specify function name, passing the module category rules of the settings
+            const rules = vscode.workspace.getConfiguration('gopp.dependencies').get<{ prefix: string; category: string }[]>('categories', []);
             return await WasmExecutor.callFunction<string>(
                 ctx,
                 GoWasmFunction.ParseModFunc,
-                content
+                content,
+                JSON.stringify(rules)
             );
         } catch (error) {
             logger.error(`WASM execution failed: ${error}`);
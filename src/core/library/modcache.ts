@@ -0,0 +1,504 @@
+import * as vscode from 'vscode';
+import * as fs from 'fs';
+import * as path from 'path';
+import { execSync } from 'child_process';
+import { Logger } from '../../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+import { httpClient } from '../../pkg/http';
+
+const logger = Logger.withContext('library/modcache');
+
+/**
+ * 依赖树节点（由 WASM 返回）
+ * Dependency tree node (returned by WASM)
+ */
+export interface DependencyNode {
+    path: string;             // 模块路径
+    version: string;          // 模块版本
+    indirect: boolean;        // 父模块以 // indirect 依赖
+    missing: boolean;         // 模块缓存中没有 go.mod
+    error: string;            // go.mod 解析失败的原因
+    cycle: boolean;           // 已是祖先节点，未展开
+    duplicate: boolean;       // 已在树的其他位置展开
+    children: DependencyNode[];
+}
+
+// 模块缓存目录，首次使用时通过 go env 获取
+// Module cache directory, read from go env on first use
+let modCacheDir: string | undefined;
+
+/**
+ * 获取模块缓存目录
+ * Get the module cache directory
+ */
+function moduleCacheDir(): string {
+    if (modCacheDir === undefined) {
+        try {
+            modCacheDir = execSync('go env GOMODCACHE', { encoding: 'utf-8' }).trim();
+        } catch (error) {
+            logger.error('go env GOMODCACHE error: ', error);
+            modCacheDir = '';
+        }
+    }
+    return modCacheDir;
+}
+
+// 模块代理地址，首次使用时通过 go env 获取
+// Module proxy URL, read from go env on first use
+let modProxy: string | undefined;
+
+// 最新版本查询结果，按模块路径缓存
+// Latest version lookups, cached by module path
+const latestVersions = new Map<string, { time: number; version: Promise<string | undefined> }>();
+
+// 最新版本缓存有效期
+// Lifetime of cached latest versions
+const LATEST_VERSION_TTL = 10 * 60 * 1000;
+
+/**
+ * 获取模块代理地址：GOPROXY 中第一个 HTTP(S) 代理，没有时使用 proxy.golang.org
+ * Get the module proxy: the first HTTP(S) proxy of GOPROXY, proxy.golang.org when there is none
+ */
+function moduleProxy(): string {
+    if (modProxy === undefined) {
+        modProxy = 'https://proxy.golang.org';
+        try {
+            const proxies = execSync('go env GOPROXY', { encoding: 'utf-8' }).trim().split(/[,|]/);
+            const proxy = proxies.find(p => p.startsWith('http://') || p.startsWith('https://'));
+            if (proxy) {
+                modProxy = proxy.replace(/\/+$/, '');
+            }
+        } catch (error) {
+            logger.error('go env GOPROXY error: ', error);
+        }
+    }
+    return modProxy;
+}
+
+/**
+ * 从模块代理查询模块的最新版本
+ * Query the latest version of a module from the module proxy
+ * @param modulePath 模块路径 (module path)
+ * @returns 最新版本，查询失败时为 undefined (latest version, undefined when the lookup fails)
+ */
+export function fetchLatestVersion(modulePath: string): Promise<string | undefined> {
+    const cached = latestVersions.get(modulePath);
+    if (cached && Date.now() - cached.time < LATEST_VERSION_TTL) {
+        return cached.version;
+    }
+
+    const url = `${moduleProxy()}/${escapeModulePath(modulePath)}/@latest`;
+    const version = httpClient.Request<{ Version: string }>(url)
+        .then(info => info.Version)
+        .catch(error => {
+            logger.warn(`查询最新版本失败 ${modulePath}: ${error}`);
+            return undefined;
+        });
+    latestVersions.set(modulePath, { time: Date.now(), version });
+    return version;
+}
+
+/**
+ * 比较当前版本与最新版本
+ * Compare the current version with the latest one
+ * @param ctx 扩展上下文 (extension context)
+ * @param current 当前版本 (current version)
+ * @param latest 最新版本 (latest version)
+ * @returns 是否可以升级，版本无效时为 undefined (whether an upgrade is available, undefined for invalid versions)
+ */
+export async function isUpgradeAvailable(ctx: vscode.ExtensionContext, current: string, latest: string): Promise<boolean | undefined> {
+    const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.CompareVersionsFunc, current, latest);
+    const data = JSON.parse(result);
+    if (data.error !== undefined) {
+        logger.warn(`比较版本失败: ${data.error}`);
+        return undefined;
+    }
+    return data.upgrade;
+}
+
+/**
+ * 一个模块可选择的候选版本与被排除的版本
+ * Candidate versions of a module that can be selected and the excluded versions
+ */
+export interface ExcludedVersions {
+    path: string;
+    current: string;          // 依赖的版本，未依赖时为空 / Required version, empty when not required
+    candidates: string[];     // 未被排除的候选版本 / Candidates surviving the excludes
+    excluded: string[];       // 该模块所有被排除的版本 / Every version excluded for the module
+    upgrade: string;          // 比当前版本新的最新候选版本 / Newest surviving candidate newer than current
+}
+
+/**
+ * 从升级候选版本中移除 go.mod 排除的版本
+ * Remove the versions excluded by a go.mod from upgrade candidates
+ * @param ctx 扩展上下文 (extension context)
+ * @param content go.mod 内容 (go.mod content)
+ * @param candidates 模块路径到候选版本的映射 (module paths to candidate versions)
+ * @returns 每个模块的结果，失败时为 undefined (result per module, undefined on failure)
+ */
+export async function filterExcludedVersions(ctx: vscode.ExtensionContext, content: string, candidates: Record<string, string[]>): Promise<ExcludedVersions[] | undefined> {
+    const result = await WasmExecutor.callFunction<string>(ctx, GoWasmFunction.FilterExcludedVersionsFunc, content, JSON.stringify(candidates));
+    const data = JSON.parse(result);
+    if (data.error !== undefined) {
+        logger.warn(`过滤排除版本失败: ${data.error}`);
+        return undefined;
+    }
+    return data;
+}
+
+/**
+ * 模块缓存中的路径转义：大写字母转换为 ! 加小写字母
+ * Module cache path escaping: capital letters become ! followed by the lowercase letter
+ * @param s 模块路径或版本 (module path or version)
+ */
+function escapeModulePath(s: string): string {
+    return s.replace(/[A-Z]/g, c => '!' + c.toLowerCase());
+}
+
+/**
+ * 从模块缓存读取 module@version 的 go.mod
+ * Read the go.mod of module@version from the module cache
+ * @param modulePath 模块路径 (module path)
+ * @param version 模块版本 (module version)
+ * @returns go.mod 内容，不存在时为 undefined (go.mod content, undefined when absent)
+ */
+function readCachedGoMod(modulePath: string, version: string): string | undefined {
+    const dir = moduleCacheDir();
+    if (!dir) {
+        return undefined;
+    }
+    const file = path.join(dir, 'cache', 'download', escapeModulePath(modulePath), '@v', `${escapeModulePath(version)}.mod`);
+    try {
+        return fs.readFileSync(file, 'utf-8');
+    } catch {
+        return undefined;
+    }
+}
+
+/**
+ * 从模块缓存收集依赖树所需的 go.mod，逐层读取到指定深度
+ * Collect the go.mod files of a dependency tree from the module cache, level by level up to a depth
+ * @param roots 根模块 module@version 列表 (root modules as module@version)
+ * @param depth 最大深度 (maximum depth)
+ * @returns module@version 到 go.mod 内容的映射 (map of module@version to go.mod content)
+ */
+function collectGoMods(roots: string[], depth: number): Record<string, string> {
+    const mods: Record<string, string> = {};
+    let level = roots;
+    for (let i = 0; i < depth && level.length > 0; i++) {
+        const next: string[] = [];
+        for (const key of level) {
+            if (key in mods) {
+                continue;
+            }
+            const at = key.lastIndexOf('@');
+            const content = readCachedGoMod(key.substring(0, at), key.substring(at + 1));
+            if (content === undefined) {
+                continue;
+            }
+            mods[key] = content;
+
+            next.push(...requireLines(content));
+        }
+        level = next;
+    }
+    return mods;
+}
+
+/**
+ * 提取 go.mod 中 require 行的 module@version，完整解析交给 WASM
+ * Extract module@version of the require lines of a go.mod, WASM does the full parse
+ * @param content go.mod 内容 (go.mod content)
+ * @param directOnly 是否跳过 // indirect 依赖 (whether to skip // indirect requires)
+ */
+function requireLines(content: string, directOnly = false): string[] {
+    const requires: string[] = [];
+    for (const match of content.matchAll(/^\s*(?:require\s+)?([^\s()]+)\s+(v\S+)(.*)$/gm)) {
+        if (['module', 'go', 'toolchain', 'replace', 'exclude', 'retract'].includes(match[1])) {
+            continue;
+        }
+        if (!directOnly || !/\/\/\s*indirect\b/.test(match[3])) {
+            requires.push(`${match[1]}@${match[2]}`);
+        }
+    }
+    return requires;
+}
+
+/**
+ * 解析模块的依赖树
+ * Resolve the dependency tree of a module
+ * @param ctx 扩展上下文 (extension context)
+ * @param modulePath 模块路径 (module path)
+ * @param version 模块版本 (module version)
+ * @param depth 读取 go.mod 的最大深度 (maximum depth of go.mod files read)
+ * @returns 依赖树，失败时为 undefined (dependency tree, undefined on failure)
+ */
+export async function resolveDependencyTree(
+    ctx: vscode.ExtensionContext,
+    modulePath: string,
+    version: string,
+    depth = 3
+): Promise<DependencyNode | undefined> {
+    try {
+        const mods = collectGoMods([`${modulePath}@${version}`], depth);
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.DependencyTreeFunc,
+            JSON.stringify(mods),
+            `${modulePath}@${version}`
+        );
+
+        const data = JSON.parse(result);
+        if (data.error !== undefined && data.path === undefined) {
+            logger.error(`解析依赖树失败: ${data.error}`);
+            return undefined;
+        }
+        return data as DependencyNode;
+    } catch (error) {
+        logger.error('解析依赖树时发生错误', error);
+        return undefined;
+    }
+}
+
+/**
+ * 依赖引入原因（由 WASM 返回）
+ * Why a module is required (returned by WASM)
+ */
+export interface RequireExplanation {
+    target: string;           // 目标模块路径
+    required: boolean;        // 是否有直接依赖引入目标模块
+    chains: { via: string; path: string[] }[]; // 每个引入目标的直接依赖对应的最短 require 链
+    missing: string[];        // 模块缓存中没有的 go.mod，经过它们的链未知
+}
+
+/**
+ * 解释主模块为什么需要某个模块，类似 go mod why -m，只读取模块缓存
+ * Explain why the main module needs a module, like go mod why -m, reading only the module cache
+ * @param ctx 扩展上下文 (extension context)
+ * @param mainGoMod 主模块 go.mod 内容 (main go.mod content)
+ * @param target 目标模块路径 (target module path)
+ * @param depth 读取 go.mod 的最大深度 (maximum depth of go.mod files read)
+ * @returns 引入原因，失败时为 undefined (explanation, undefined on failure)
+ */
+export async function explainRequire(
+    ctx: vscode.ExtensionContext,
+    mainGoMod: string,
+    target: string,
+    depth = 5
+): Promise<RequireExplanation | undefined> {
+    try {
+        const mods = collectGoMods(requireLines(mainGoMod, true), depth);
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.ExplainRequireFunc,
+            JSON.stringify(mods),
+            mainGoMod,
+            target
+        );
+
+        const data = JSON.parse(result);
+        if (data.error !== undefined) {
+            logger.error(`解释依赖引入原因失败: ${data.error}`);
+            return undefined;
+        }
+        return data as RequireExplanation;
+    } catch (error) {
+        logger.error('解释依赖引入原因时发生错误', error);
+        return undefined;
+    }
+}
+
+/**
+ * require 的包被导入的位置（由 WASM 返回）
+ * Where the packages of a require are imported (returned by WASM)
+ */
+export interface RequireUsage {
+    path: string;             // 模块路径
+    version: string;          // 模块版本
+    usage: 'test-only' | 'build' | 'both';
+    imports: string[];        // 项目导入的该模块的包
+}
+
+// 每个模块目录中非测试文件与测试文件的导入路径，Go 文件保存后失效
+// Import paths of the non-test and test files per module directory, invalidated when a Go file is saved
+const importLists = new Map<string, { build: string[]; test: string[] }>();
+
+/**
+ * 清除缓存的导入路径
+ * Clear the cached import paths
+ */
+export function invalidateImportLists(): void {
+    importLists.clear();
+}
+
+/**
+ * 通过 go list 分别列出模块中非测试文件和测试文件的导入路径
+ * List the import paths of the non-test and the test files of a module with go list
+ * @param dir 模块目录 (module directory)
+ */
+function listImportsByKind(dir: string): { build: string[]; test: string[] } {
+    const cached = importLists.get(dir);
+    if (cached) {
+        return cached;
+    }
+
+    // 测试导入以制表符开头
+    // Test imports start with a tab
+    const format = '{{join .Imports "\\n"}}{{range .TestImports}}\n\t{{.}}{{end}}{{range .XTestImports}}\n\t{{.}}{{end}}';
+    const build = new Set<string>();
+    const test = new Set<string>();
+    try {
+        const stdout = execSync(`go list -e -f '${format}' ./...`, { cwd: dir, encoding: 'utf-8' });
+        for (const line of stdout.split('\n')) {
+            if (line.startsWith('\t')) {
+                test.add(line.trim());
+            } else if (line.trim() !== '') {
+                build.add(line.trim());
+            }
+        }
+    } catch (error) {
+        logger.error('go list 获取导入路径失败', error);
+    }
+    const lists = { build: [...build], test: [...test] };
+    importLists.set(dir, lists);
+    return lists;
+}
+
+/**
+ * 判断各个 require 是只被测试导入、只被构建导入还是两者都有
+ * Tell whether each require is imported from tests only, from the build only, or both
+ * @param ctx 扩展上下文 (extension context)
+ * @param goModPath go.mod 文件路径 (go.mod file path)
+ * @param content go.mod 内容 (go.mod content)
+ * @returns 被导入的 require，失败时为 undefined (imported requires, undefined on failure)
+ */
+export async function classifyRequireUsage(
+    ctx: vscode.ExtensionContext,
+    goModPath: string,
+    content: string
+): Promise<RequireUsage[] | undefined> {
+    try {
+        const lists = listImportsByKind(path.dirname(goModPath));
+        if (lists.build.length === 0 && lists.test.length === 0) {
+            return undefined;
+        }
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.ClassifyRequireUsageFunc,
+            content,
+            JSON.stringify(lists.build),
+            JSON.stringify(lists.test)
+        );
+
+        const data = JSON.parse(result);
+        if (!Array.isArray(data)) {
+            logger.error(`判断依赖使用方式失败: ${data.error}`);
+            return undefined;
+        }
+        return data as RequireUsage[];
+    } catch (error) {
+        logger.error('判断依赖使用方式时发生错误', error);
+        return undefined;
+    }
+}
+
+// 许可证文件名，按优先级排列
+// License file names, in order of preference
+const LICENSE_FILES = ['LICENSE', 'LICENSE.md', 'LICENSE.txt', 'LICENCE', 'COPYING', 'COPYING.md'];
+
+// 许可证文本特征与对应的 SPDX 标识符，较具体的在前，使 AGPL 与 LGPL 不被识别为 GPL
+// License text patterns and their SPDX identifiers, more specific first so AGPL and LGPL are not taken for GPL
+const LICENSE_PATTERNS: [RegExp, string][] = [
+    [/GNU AFFERO GENERAL PUBLIC LICENSE/i, 'AGPL-3.0'],
+    [/GNU LESSER GENERAL PUBLIC LICENSE\s+Version 2\.1/i, 'LGPL-2.1'],
+    [/GNU LESSER GENERAL PUBLIC LICENSE/i, 'LGPL-3.0'],
+    [/GNU GENERAL PUBLIC LICENSE\s+Version 2/i, 'GPL-2.0'],
+    [/GNU GENERAL PUBLIC LICENSE/i, 'GPL-3.0'],
+    [/Mozilla Public License,? (?:Version|v\.?) 2\.0/i, 'MPL-2.0'],
+    [/Apache License,?\s+Version 2\.0/i, 'Apache-2.0'],
+    [/Permission is hereby granted, free of charge/i, 'MIT'],
+    [/Permission to use, copy, modify, and\/or distribute/i, 'ISC'],
+    [/Neither the name of/i, 'BSD-3-Clause'],
+    [/Redistribution and use in source and binary forms/i, 'BSD-2-Clause'],
+    [/This is free and unencumbered software released into the public domain/i, 'Unlicense']
+];
+
+// 许可证识别结果，按 module@version 缓存；版本内容不可变，无需失效
+// Detected licenses cached by module@version; a version never changes, so nothing is invalidated
+const licenses = new Map<string, string>();
+
+/**
+ * 从模块缓存中 module@version 目录下的许可证文件识别许可证
+ * Detect the license of module@version from the license file of its directory in the module cache
+ * @param modulePath 模块路径 (module path)
+ * @param version 模块版本 (module version)
+ * @returns SPDX 标识符，未找到或无法识别时为空 (SPDX identifier, empty when absent or unknown)
+ */
+function detectModuleLicense(modulePath: string, version: string): string {
+    const key = `${modulePath}@${version}`;
+    const cached = licenses.get(key);
+    if (cached !== undefined) {
+        return cached;
+    }
+
+    const dir = moduleCacheDir();
+    const moduleDir = path.join(dir, `${escapeModulePath(modulePath)}@${escapeModulePath(version)}`);
+    if (!dir || !fs.existsSync(moduleDir)) {
+        // 模块尚未下载时不缓存，下载后再次识别
+        // Not cached while the module is not downloaded, so it is detected again afterwards
+        return '';
+    }
+
+    let license = '';
+    for (const name of LICENSE_FILES) {
+        const file = path.join(moduleDir, name);
+        if (fs.existsSync(file)) {
+            const text = fs.readFileSync(file, 'utf-8');
+            license = LICENSE_PATTERNS.find(([pattern]) => pattern.test(text))?.[1] ?? '';
+            break;
+        }
+    }
+    licenses.set(key, license);
+    return license;
+}
+
+/**
+ * 扫描模块缓存，为 go.mod 的依赖附加许可证；gopp.dependencies.flaggedLicenses 中的许可证
+ * 与缺少许可证信息的依赖在 warnings 中报告
+ * Scan the module cache and attach licenses to the requires of a go.mod; licenses listed in
+ * gopp.dependencies.flaggedLicenses and requires without license information are reported in the warnings
+ * @param ctx 扩展上下文 (extension context)
+ * @param content go.mod 内容 (go.mod content)
+ * @returns JoinLicenses 的结果，失败时为 undefined (result of JoinLicenses, undefined on failure)
+ */
+export async function joinModuleLicenses(ctx: vscode.ExtensionContext, content: string): Promise<any | undefined> {
+    try {
+        const found: Record<string, string> = {};
+        for (const key of requireLines(content)) {
+            const at = key.lastIndexOf('@');
+            const license = detectModuleLicense(key.substring(0, at), key.substring(at + 1));
+            if (license) {
+                found[key.substring(0, at)] = license;
+            }
+        }
+
+        const flagged = vscode.workspace.getConfiguration('gopp.dependencies').get<string[]>('flaggedLicenses', ['GPL', 'AGPL']);
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.JoinLicensesFunc,
+            content,
+            JSON.stringify(found),
+            JSON.stringify(flagged)
+        );
+
+        const data = JSON.parse(result);
+        if (data.error !== undefined || data.errors !== undefined) {
+            logger.error(`附加许可证失败: ${data.error ?? JSON.stringify(data.errors)}`);
+            return undefined;
+        }
+        return data;
+    } catch (error) {
+        logger.error('附加许可证时发生错误', error);
+        return undefined;
+    }
+}
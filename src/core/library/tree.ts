@@ -4,10 +4,17 @@ import { ModItem } from './item';
 import * as fs from 'fs';
 import * as path from 'path';
 import * as vscode from 'vscode';
-import { GoModule, ModCmdInfo } from './mod';
+import { GoModule, ModCmdInfo, ModExcludeGroup } from './mod';
 import { Dependencies, DependencyCmdInfo } from './dependencies';
 import { getResourceUri } from '../../pkg/resource';
 
+// 依赖类别对应的图标颜色，third-party 使用默认颜色
+// Icon colors of the dependency categories, third-party keeps the default color
+const categoryColors: Record<string, string> = {
+    'extended-stdlib': 'charts.blue',
+    'internal': 'charts.green'
+};
+
 const logger = Logger.withContext('library/treedata');
 
 
@@ -20,6 +27,9 @@ enum TreeLabel {
     Tools = 'Tools',
     Replaces = 'Replaces',
     Excludes = 'Excludes',
+    ExcludesOnly = 'Excludes Only',
+    Godebugs = 'Godebugs',
+    BuildIgnored = 'Build Ignored',
 }
 
 
@@ -276,7 +286,8 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
         // 如果是根级别的特殊节点，直接返回null
         // If it's a root-level special node, return null
         if ([this._sdkItem.label, TreeLabel.Modules, TreeLabel.Dependencies, TreeLabel.IndirectDependencies,
-            TreeLabel.Tools, TreeLabel.Replaces, TreeLabel.Excludes].includes(element.label as TreeLabel)) {
+            TreeLabel.Tools, TreeLabel.Replaces, TreeLabel.Excludes, TreeLabel.ExcludesOnly,
+            TreeLabel.BuildIgnored].includes(element.label as TreeLabel)) {
             return null;
         }
 
@@ -316,6 +327,49 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
         }
     }
 
+    /**
+     * 按 go.mod 中 require 的类别为依赖项目录图标着色，未列在任何 go.mod 中的依赖使用普通图标
+     * Color the folder icon of a dependency by the category of its require in go.mod,
+     * dependencies not listed in any go.mod get the plain icon
+     * @param modPath 模块路径 (module path)
+     */
+    private categoryIcon(modPath: string): vscode.ThemeIcon {
+        const require = this._modCmdInfos.flatMap(m => m.FileInfo.Require).find(r => r.Module === modPath);
+        const color = require?.Category ? categoryColors[require.Category] : undefined;
+        return color ? new vscode.ThemeIcon('folder', new vscode.ThemeColor(color)) : vscode.ThemeIcon.Folder;
+    }
+
+    /**
+     * 所有 go.mod 中被排除但未被任何 go.mod 依赖的模块，同一模块的版本合并
+     * Modules excluded but not required by any go.mod, with the versions of a module merged
+     */
+    private excludesOnly(): ModExcludeGroup[] {
+        const required = new Set(this._modCmdInfos.flatMap(m => m.FileInfo.Require).map(r => r.Module));
+        const groups = new Map<string, Set<string>>();
+        for (const group of this._modCmdInfos.flatMap(m => m.FileInfo.ExcludeOnly ?? [])) {
+            if (required.has(group.Module)) {
+                continue;
+            }
+            const versions = groups.get(group.Module) ?? new Set<string>();
+            group.Versions.forEach(v => versions.add(v));
+            groups.set(group.Module, versions);
+        }
+        return [...groups].map(([module, versions]) => ({ Module: module, Versions: [...versions] }));
+    }
+
+    /**
+     * 依赖项的描述：选中的版本，以及 go.mod 中排除了该模块的多少个版本
+     * Description of a dependency: the selected version and how many of its versions go.mod excludes
+     * @param modPath 模块路径 (module path)
+     * @param version 选中的版本 (selected version)
+     */
+    private dependencyDescription(modPath: string, version: string): string {
+        const excluded = new Set(this._modCmdInfos.flatMap(m => m.FileInfo.Require)
+            .filter(r => r.Module === modPath)
+            .flatMap(r => r.ExcludedVersions ?? []));
+        return excluded.size > 0 ? `${version} (${excluded.size} excluded)` : version;
+    }
+
     /**
      * 判断是否是依赖项的子项
      * @param element 元素
@@ -374,7 +428,7 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
         // 获取所有替换项，并按 module 字段去重
         // Get all replaces and deduplicate by module field
         const replaces = modfileInfos.flatMap(info => info.Replace).
-            filter((replace, index, self) => index === self.findIndex(t => t.Module === replace.Module));
+            filter((replace, index, self) => index === self.findIndex(t => t.OldPath === replace.OldPath));
         if (replaces.length > 0) {
             const uri = vscode.Uri.file(TreeLabel.Replaces).with({scheme: 'modules'});
             let item = this._itemMap.get(uri.fsPath);
@@ -402,6 +456,52 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
             }
             rootItems.push(item);
         }
+
+        // 被排除但未被依赖的模块单独列出
+        // Modules excluded but not required are listed separately
+        const excludesOnly = this.excludesOnly();
+        if (excludesOnly.length > 0) {
+            const uri = vscode.Uri.file(TreeLabel.ExcludesOnly).with({scheme: 'modules'});
+            let item = this._itemMap.get(uri.fsPath);
+            if (!item) {
+                item = new ModItem(TreeLabel.ExcludesOnly, uri, true);
+                item.iconPath = getResourceUri(this._ctx, 'icons/exclude.svg');
+                item.description = excludesOnly.length.toString();
+                this._itemMap.set(item.resourceUri.fsPath, item);
+            }
+            rootItems.push(item);
+        }
+
+        // 获取所有 godebug 设置，并按 key 字段去重
+        // Get all godebug settings and deduplicate by key field
+        const godebugs = modfileInfos.flatMap(info => info.Godebug).
+            filter((godebug, index, self) => index === self.findIndex(g => g.Key === godebug.Key));
+        if (godebugs.length > 0) {
+            const uri = vscode.Uri.file(TreeLabel.Godebugs).with({scheme: 'modules'});
+            let item = this._itemMap.get(uri.fsPath);
+            if (!item) {
+                item = new ModItem(TreeLabel.Godebugs, uri, true);
+                item.iconPath = new vscode.ThemeIcon('debug');
+                item.description = godebugs.length.toString();
+                this._itemMap.set(item.resourceUri.fsPath, item);
+            }
+            rootItems.push(item);
+        }
+
+        // 获取所有被 ignore 指令忽略的目录，按所在模块区分
+        // Get all directories of ignore directives, per module
+        const ignored = this._modCmdInfos.reduce((count, mod) => count + mod.FileInfo.Ignore.length, 0);
+        if (ignored > 0) {
+            const uri = vscode.Uri.file(TreeLabel.BuildIgnored).with({scheme: 'modules'});
+            let item = this._itemMap.get(uri.fsPath);
+            if (!item) {
+                item = new ModItem(TreeLabel.BuildIgnored, uri, true);
+                item.iconPath = new vscode.ThemeIcon('eye-closed');
+                this._itemMap.set(item.resourceUri.fsPath, item);
+            }
+            item.description = ignored.toString();
+            rootItems.push(item);
+        }
         return rootItems;
     }
 
@@ -437,8 +537,8 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
                         return item;
                     }
                     item = new ModItem(dep.Path, vscode.Uri.parse(dep.Dir), true);
-                    item.iconPath = vscode.ThemeIcon.Folder;
-                    item.description = dep.Version;
+                    item.iconPath = this.categoryIcon(dep.Path);
+                    item.description = this.dependencyDescription(dep.Path, dep.Version);
                     this._itemMap.set(dep.Dir, item);
                     return item;
                 });
@@ -452,8 +552,8 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
                         return item;
                     }
                     const modItem = new ModItem(dep.Path, vscode.Uri.file(dep.Dir), true);
-                    modItem.iconPath = vscode.ThemeIcon.Folder;
-                    modItem.description = dep.Version;
+                    modItem.iconPath = this.categoryIcon(dep.Path);
+                    modItem.description = this.dependencyDescription(dep.Path, dep.Version);
                     this._itemMap.set(dep.Dir, modItem);
                     return modItem;
                 });
@@ -466,9 +566,12 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
                     if (item) {
                         return item;
                     }
-                    const modItem = new ModItem(tool.Module, vscode.Uri.file(tool.Module), false);
+                    // 以命令名称为标签，完整包路径作为描述
+                    // Label with the command name, the full package path as description
+                    const modItem = new ModItem(tool.Name || tool.Module, vscode.Uri.file(tool.Module), false);
                     modItem.iconPath = vscode.ThemeIcon.File;
-                    modItem.description = tool.Version;
+                    modItem.description = tool.Module;
+                    modItem.tooltip = `go tool ${tool.Name || tool.Module}`;
                     modItem.command = null;
                     this._itemMap.set(tool.Module, modItem);
                     return modItem;
@@ -479,15 +582,15 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
             return this._modCmdInfos.flatMap(m => m.FileInfo.Replace).
                 filter((replace, index, self) => self.indexOf(replace) === index). // 去重
                 map(replace => {
-                    const item = this._itemMap.get(replace.Module);
+                    const item = this._itemMap.get(replace.OldPath);
                     if (item) {
                         return item;
                     }
-                    const modItem = new ModItem(replace.Module, vscode.Uri.file(replace.Module), false);
+                    const modItem = new ModItem(replace.OldPath, vscode.Uri.file(replace.OldPath), false);
                     modItem.iconPath = vscode.ThemeIcon.File;
-                    modItem.description = replace.Version;
+                    modItem.description = `${replace.OldVersion} => ${replace.NewPath} ${replace.NewVersion}`.trim();
                     modItem.command = null;
-                    this._itemMap.set(replace.Module, modItem);
+                    this._itemMap.set(replace.OldPath, modItem);
                     return modItem;
                 }
                 );
@@ -510,6 +613,60 @@ export class GoLibraryTreeData implements vscode.TreeDataProvider<ModItem>, vsco
                 );
         }
 
+        if (element.label === TreeLabel.ExcludesOnly) {
+            return this.excludesOnly().map(group => {
+                const key = `excludes-only:${group.Module}`;
+                const item = this._itemMap.get(key);
+                if (item) {
+                    return item;
+                }
+                const modItem = new ModItem(group.Module, vscode.Uri.file(group.Module), false);
+                modItem.iconPath = vscode.ThemeIcon.File;
+                modItem.description = group.Versions.join(', ');
+                modItem.command = null;
+                this._itemMap.set(key, modItem);
+                return modItem;
+            });
+        }
+
+        if (element.label === TreeLabel.Godebugs) {
+            return this._modCmdInfos.flatMap(m => m.FileInfo.Godebug).
+                filter((godebug, index, self) => index === self.findIndex(g => g.Key === godebug.Key)). // 去重
+                map(godebug => {
+                    const key = `godebug:${godebug.Key}`;
+                    const item = this._itemMap.get(key);
+                    if (item) {
+                        item.description = godebug.Value;
+                        return item;
+                    }
+                    const modItem = new ModItem(godebug.Key, vscode.Uri.file(key), false);
+                    modItem.iconPath = new vscode.ThemeIcon('symbol-key');
+                    modItem.description = godebug.Value;
+                    modItem.command = null;
+                    this._itemMap.set(key, modItem);
+                    return modItem;
+                }
+                );
+        }
+
+        if (element.label === TreeLabel.BuildIgnored) {
+            return this._modCmdInfos.flatMap(m => m.FileInfo.Ignore.map(dir => {
+                // 路径相对于 go.mod 所在目录，标签保持 go.mod 中的原样
+                // Paths are relative to the go.mod directory, the label keeps them as written in go.mod
+                const key = `ignore:${m.Dir}:${dir}`;
+                const item = this._itemMap.get(key);
+                if (item) {
+                    return item;
+                }
+                const modItem = new ModItem(dir, vscode.Uri.file(path.resolve(m.Dir, dir)), false);
+                modItem.iconPath = vscode.ThemeIcon.Folder;
+                modItem.description = m.Path;
+                modItem.command = null;
+                this._itemMap.set(key, modItem);
+                return modItem;
+            }));
+        }
+
         return this.getDirectoryChildren(element.resourceUri.fsPath);
     }
 
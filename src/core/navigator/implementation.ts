@@ -0,0 +1,301 @@
+import * as vscode from 'vscode';
+import { Logger } from '../../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+import { ImplementationInfo, InterfaceInfo } from '../../types';
+import { findWorkspaceGoFiles } from './workspace';
+
+const logger = Logger.withContext('navigator_implementation');
+
+/**
+ * 接口及其实现（由 WASM 返回）
+ * Interface and its implementers (returned by WASM)
+ */
+export interface InterfaceImplementations {
+    interface: string;        // 接口名称
+    package: string;          // 接口所在包的导入路径
+    path: string;             // 接口定义的文件路径
+    line: number;             // 接口定义的行号（从 1 开始）
+    methods: MethodLocation[];   // 完整方法集，包含嵌入接口的方法
+    implementations: TypeImplementation[];
+    partial: TypeImplementation[]; // 只声明了部分方法的类型
+}
+
+/**
+ * 实现接口的具体类型
+ * Concrete type implementing an interface
+ */
+export interface TypeImplementation {
+    name: string;             // 实现类型名称，指针接收者带 * 前缀
+    package: string;          // 实现类型所在包的导入路径
+    pointer: boolean;         // 是否只有指针类型实现了接口
+    path: string;             // 实现类型定义的文件路径
+    line: number;             // 实现类型定义的行号（从 1 开始）
+    methods: MethodLocation[];   // 满足接口方法的具体方法
+    ambiguous: string[];      // 由同一深度的多个嵌入字段提升的接口方法，不满足接口
+}
+
+/**
+ * 方法声明位置
+ * Method declaration location
+ */
+export interface MethodLocation {
+    name: string;             // 方法名称
+    path: string;             // 方法定义的文件路径
+    line: number;             // 方法定义的行号（从 1 开始）
+    via?: string;             // 方法提升所经过的嵌入字段，例如 Loud.EnglishGreeter；自身声明的方法为空
+}
+
+/**
+ * 具体方法实现的接口方法
+ * Interface method implemented by a concrete method
+ */
+export interface ImplementedMethod {
+    interface: string;        // 接口名称
+    method: MethodLocation;   // 接口方法声明
+}
+
+/**
+ * 传给 WASM 的源文件
+ * Source file passed to WASM
+ */
+export interface SourceFile {
+    path: string;             // 文件路径
+    content: string;          // 文件内容
+}
+
+/**
+ * 读取工作空间中的 Go 文件和 go.mod，已打开的文档使用编辑器中的内容
+ * Read the workspace Go files and go.mod files, using editor content for open documents
+ * @returns 源文件列表 (source files)
+ */
+export async function readWorkspaceGoFiles(): Promise<SourceFile[]> {
+    const uris = await findWorkspaceGoFiles();
+    const openDocuments = new Map(vscode.workspace.textDocuments.map(doc => [doc.uri.toString(), doc]));
+
+    return Promise.all(uris.map(async uri => {
+        const doc = openDocuments.get(uri.toString());
+        return {
+            path: uri.fsPath,
+            content: doc ? doc.getText() : Buffer.from(await vscode.workspace.fs.readFile(uri)).toString('utf-8')
+        };
+    }));
+}
+
+/**
+ * 工作空间接口实现索引
+ * Workspace interface implementation index
+ * 索引工作空间中的 Go 文件，无需 gopls 即可查找接口实现。
+ * WASM 中保留每个文件的缓存，更新时只发送版本变化的文件，只重新分析受影响的包
+ * Indexes the workspace Go files so implementers can be found without gopls.
+ * WASM keeps a per-file cache; updates send only files whose version changed and re-analyze only the affected packages
+ */
+export class ImplementationIndex {
+    // 当前索引结果，文件变化时失效
+    // Current index result, invalidated when files change
+    private static index: Promise<InterfaceImplementations[]> | null = null;
+
+    // 已发送到 WASM 的文件版本：打开的文档为文档版本，其他文件为修改时间
+    // File versions sent to WASM: document version for open documents, modification time otherwise
+    private static versions: Map<string, string> = new Map();
+
+    /**
+     * 使索引失效，下次查询时增量更新
+     * Invalidate the index so it is updated incrementally on the next lookup
+     */
+    public static invalidate(): void {
+        ImplementationIndex.index = null;
+    }
+
+    /**
+     * 查找接口的实现
+     * Find implementations of an interface
+     * @param ctx 扩展上下文 (extension context)
+     * @param document 接口所在文档 (document declaring the interface)
+     * @param interfaceName 接口名称 (interface name)
+     * @param line 接口定义所在行，从 0 开始 (interface definition line, 0-based)
+     * @returns 接口信息和实现列表 (interface info and implementations)
+     */
+    public static async lookup(
+        ctx: vscode.ExtensionContext,
+        document: vscode.TextDocument,
+        interfaceName: string,
+        line: number
+    ): Promise<{ info: InterfaceInfo; implementations: ImplementationInfo[] } | undefined> {
+        const entries = await ImplementationIndex.entries(ctx);
+        const filePath = document.uri.fsPath;
+        const entry = entries.find(e => e.interface === interfaceName && e.path === filePath && e.line === line + 1);
+        if (!entry) {
+            return undefined;
+        }
+
+        return {
+            info: {
+                name: entry.interface,
+                methods: [],
+                filePath: entry.path,
+                lineNumber: entry.line,
+                methodLineNumbers: {},
+                fullPath: entry.package
+            },
+            implementations: entry.implementations.map(impl => ({
+                structName: impl.name,
+                filePath: impl.path,
+                lineNumber: impl.line
+            }))
+        };
+    }
+
+    /**
+     * 查找具体方法实现的接口方法
+     * Find interface methods implemented by a concrete method
+     * @param ctx 扩展上下文 (extension context)
+     * @param document 方法所在文档 (document declaring the method)
+     * @param methodName 方法名称 (method name)
+     * @param line 方法定义所在行，从 0 开始 (method definition line, 0-based)
+     * @returns 接口方法列表 (interface methods)
+     */
+    public static async lookupMethod(
+        ctx: vscode.ExtensionContext,
+        document: vscode.TextDocument,
+        methodName: string,
+        line: number
+    ): Promise<ImplementedMethod[]> {
+        const entries = await ImplementationIndex.entries(ctx);
+        const filePath = document.uri.fsPath;
+        const result: ImplementedMethod[] = [];
+
+        for (const entry of entries) {
+            const implemented = entry.implementations.some(impl => impl.methods.some(m =>
+                m.name === methodName && m.path === filePath && m.line === line + 1
+            ));
+            const method = entry.methods.find(m => m.name === methodName);
+            if (implemented && method) {
+                result.push({ interface: entry.interface, method });
+            }
+        }
+        return result;
+    }
+
+    /**
+     * 查找类型只实现了部分方法的接口
+     * Find interfaces a type implements only partially
+     * @param ctx 扩展上下文 (extension context)
+     * @param document 类型所在文档 (document declaring the type)
+     * @param typeName 类型名称 (type name)
+     * @param line 类型定义所在行，从 0 开始 (type definition line, 0-based)
+     * @returns 接口列表 (interfaces)
+     */
+    public static async lookupPartial(
+        ctx: vscode.ExtensionContext,
+        document: vscode.TextDocument,
+        typeName: string,
+        line: number
+    ): Promise<InterfaceImplementations[]> {
+        const entries = await ImplementationIndex.entries(ctx);
+        const filePath = document.uri.fsPath;
+        return entries.filter(e => e.partial.some(impl =>
+            impl.name === typeName && impl.path === filePath && impl.line === line + 1
+        ));
+    }
+
+    /**
+     * 获取工作空间中的所有接口
+     * Get all interfaces in the workspace
+     * @param ctx 扩展上下文 (extension context)
+     */
+    public static async interfaces(ctx: vscode.ExtensionContext): Promise<InterfaceImplementations[]> {
+        return ImplementationIndex.entries(ctx);
+    }
+
+    /**
+     * 获取索引，必要时重建
+     * Get the index, rebuilding it if needed
+     * @param ctx 扩展上下文 (extension context)
+     */
+    private static entries(ctx: vscode.ExtensionContext): Promise<InterfaceImplementations[]> {
+        if (!ImplementationIndex.index) {
+            ImplementationIndex.index = ImplementationIndex.build(ctx);
+        }
+        return ImplementationIndex.index;
+    }
+
+    /**
+     * 收集版本变化的文件并增量更新索引，首次调用或出错后重建；重建时在状态栏显示扫描进度
+     * Collect the files whose version changed and update the index incrementally, rebuilding on first use
+     * or after an error; a rebuild shows the scan progress in the status bar
+     * @param ctx 扩展上下文 (extension context)
+     */
+    private static async build(ctx: vscode.ExtensionContext): Promise<InterfaceImplementations[]> {
+        const reset = ImplementationIndex.versions.size === 0;
+        if (!reset) {
+            return ImplementationIndex.update(ctx, reset);
+        }
+        return vscode.window.withProgress({
+            location: vscode.ProgressLocation.Window,
+            title: '正在索引 Go 文件 (Indexing Go files)'
+        }, progress => ImplementationIndex.update(ctx, reset, progress));
+    }
+
+    /**
+     * 将版本变化的文件发送到 WASM 更新索引
+     * Send the files whose version changed to WASM to update the index
+     * @param ctx 扩展上下文 (extension context)
+     * @param reset 是否重建索引 (whether the index is rebuilt)
+     * @param progress 扫描进度 (scan progress)
+     */
+    private static async update(
+        ctx: vscode.ExtensionContext,
+        reset: boolean,
+        progress?: vscode.Progress<{ message?: string; increment?: number }>
+    ): Promise<InterfaceImplementations[]> {
+        try {
+            const uris = await findWorkspaceGoFiles();
+            const openDocuments = new Map(vscode.workspace.textDocuments.map(doc => [doc.uri.toString(), doc]));
+
+            const versions = new Map<string, string>();
+            const changed: SourceFile[] = [];
+            let scanned = 0;
+            await Promise.all(uris.map(async uri => {
+                const doc = openDocuments.get(uri.toString());
+                const version = doc ? `doc:${doc.version}` : `mtime:${(await vscode.workspace.fs.stat(uri)).mtime}`;
+                versions.set(uri.fsPath, version);
+                if (ImplementationIndex.versions.get(uri.fsPath) === version) {
+                    return;
+                }
+                changed.push({
+                    path: uri.fsPath,
+                    content: doc ? doc.getText() : Buffer.from(await vscode.workspace.fs.readFile(uri)).toString('utf-8')
+                });
+                if (progress && ++scanned % 100 === 0) {
+                    progress.report({ message: `${scanned}/${uris.length}`, increment: 100 * 100 / uris.length });
+                }
+            }));
+            progress?.report({ message: `分析 ${uris.length} 个文件 (Analyzing ${uris.length} files)` });
+            const removed = [...ImplementationIndex.versions.keys()].filter(p => !versions.has(p));
+
+            const result = await WasmExecutor.callFunction<string>(
+                ctx,
+                GoWasmFunction.UpdateImplementationsFunc,
+                JSON.stringify(changed),
+                JSON.stringify(removed),
+                reset
+            );
+
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                logger.error(`构建实现索引失败: ${data.error}`);
+                ImplementationIndex.versions.clear(); // 下次重建
+                return [];
+            }
+
+            ImplementationIndex.versions = versions;
+            logger.debug(`[实现索引] 文件数: ${uris.length}, 变化: ${changed.length}, 删除: ${removed.length}, 接口数: ${data.length}`);
+            return data as InterfaceImplementations[];
+        } catch (error) {
+            logger.error('构建实现索引时发生错误', error);
+            ImplementationIndex.index = null; // 下次重试
+            ImplementationIndex.versions.clear(); // 下次重建
+            return [];
+        }
+    }
+}
@@ -0,0 +1,128 @@
+import * as vscode from 'vscode';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+import { readWorkspaceGoFiles } from './implementation';
+
+/**
+ * 矩阵中的位置（接口列或类型行）
+ * Location in the matrix (interface column or type row)
+ */
+interface MatrixEntry {
+    name: string;             // 名称，指针类型带 * 前缀
+    path: string;             // 声明所在文件
+    line: number;             // 声明所在行
+}
+
+/**
+ * 包的接口实现矩阵（由 WASM 返回）
+ * Implementation matrix of a package (returned by WASM)
+ */
+interface Matrix {
+    package: string;
+    interfaces: (MatrixEntry & { package: string })[];
+    types: (MatrixEntry & { pointer: boolean; implements: string[] })[];
+}
+
+/**
+ * 在面板中显示文档所在包的接口实现矩阵，点击单元格跳转到类型或接口
+ * Show the implementation matrix of the document's package in a panel, clicking a cell jumps to the type or the interface
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 包中的文档 (a document of the package)
+ */
+export async function showImplementationMatrix(ctx: vscode.ExtensionContext, document: vscode.TextDocument): Promise<void> {
+    const files = await readWorkspaceGoFiles();
+    const result = await WasmExecutor.callFunction<string>(
+        ctx,
+        GoWasmFunction.ImplementationMatrixFunc,
+        JSON.stringify(files),
+        document.fileName
+    );
+
+    const data = JSON.parse(result);
+    if (data.error !== undefined) {
+        throw new Error(data.error);
+    }
+
+    const matrix = data as Matrix;
+    const panel = vscode.window.createWebviewPanel('goppImplementationMatrix', `Implementations: ${matrix.package}`, vscode.ViewColumn.Beside, {
+        enableScripts: true
+    });
+    panel.webview.html = renderMatrix(matrix);
+    panel.webview.onDidReceiveMessage(async (message: { row?: number; column?: number }) => {
+        const row = message.row !== undefined ? matrix.types[message.row] : undefined;
+        const column = message.column !== undefined ? matrix.interfaces[message.column] : undefined;
+
+        let target: MatrixEntry | undefined = row || column;
+        if (row && column) {
+            const picked = await vscode.window.showQuickPick([
+                { label: `$(symbol-struct) ${row.name}`, entry: row as MatrixEntry },
+                { label: `$(symbol-interface) ${column.name}`, entry: column as MatrixEntry }
+            ], { placeHolder: `${row.name} implements ${column.name}` });
+            target = picked?.entry;
+        }
+        if (target) {
+            const doc = await vscode.workspace.openTextDocument(vscode.Uri.file(target.path));
+            const position = new vscode.Position(target.line - 1, 0);
+            await vscode.window.showTextDocument(doc, {
+                viewColumn: vscode.ViewColumn.One,
+                selection: new vscode.Range(position, position)
+            });
+        }
+    }, undefined, ctx.subscriptions);
+}
+
+/**
+ * 将矩阵渲染为 HTML 表格
+ * Render the matrix as an HTML table
+ * @param matrix 接口实现矩阵 (implementation matrix)
+ */
+function renderMatrix(matrix: Matrix): string {
+    const header = matrix.interfaces
+        .map((iface, column) => `<th><a data-column="${column}" title="${escapeHtml(iface.package)}">${escapeHtml(iface.name)}</a></th>`)
+        .join('');
+    const rows = matrix.types.map((type, row) => {
+        const cells = matrix.interfaces.map((iface, column) => type.implements.includes(iface.name)
+            ? `<td class="yes"><a data-row="${row}" data-column="${column}">✓</a></td>`
+            : '<td></td>').join('');
+        return `<tr><th><a data-row="${row}">${escapeHtml(type.name)}</a></th>${cells}</tr>`;
+    }).join('\n');
+
+    const body = matrix.interfaces.length === 0 || matrix.types.length === 0
+        ? '<p>没有接口或具体类型 (No interfaces or concrete types)</p>'
+        : `<table><tr><th></th>${header}</tr>\n${rows}</table>`;
+
+    return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<style>
+    body { font-family: var(--vscode-font-family); color: var(--vscode-foreground); }
+    table { border-collapse: collapse; }
+    th, td { border: 1px solid var(--vscode-panel-border); padding: 4px 8px; text-align: center; }
+    th { font-weight: normal; white-space: nowrap; }
+    td.yes { color: var(--vscode-testing-iconPassed); }
+    a { cursor: pointer; color: inherit; }
+    a:hover { color: var(--vscode-textLink-activeForeground); }
+</style>
+</head>
+<body>
+<h3>${escapeHtml(matrix.package)}</h3>
+${body}
+<script>
+    const vscode = acquireVsCodeApi();
+    document.querySelectorAll('a').forEach(a => a.addEventListener('click', () => {
+        const row = a.dataset.row === undefined ? undefined : Number(a.dataset.row);
+        const column = a.dataset.column === undefined ? undefined : Number(a.dataset.column);
+        vscode.postMessage({ row, column });
+    }));
+</script>
+</body>
+</html>`;
+}
+
+/**
+ * 转义 HTML 特殊字符
+ * Escape HTML special characters
+ */
+function escapeHtml(text: string): string {
+    return text.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;');
+}
@@ -0,0 +1,144 @@
+import * as vscode from 'vscode';
+import * as path from 'path';
+import { Logger } from '../../pkg/logger';
+
+const logger = Logger.withContext('navigator_workspace');
+
+/**
+ * .gitignore 中的一条规则
+ * A rule of a .gitignore file
+ */
+interface IgnoreRule {
+    base: string;             // .gitignore 所在目录 / Directory of the .gitignore
+    pattern: RegExp;          // 匹配相对 base 的路径 / Matches paths relative to base
+    negate: boolean;          // ! 开头的规则重新包含匹配的路径 / Rules starting with ! include the paths again
+    dirOnly: boolean;         // / 结尾的规则只匹配目录 / Rules ending with / only match directories
+}
+
+/**
+ * 查找工作空间中需要索引的 Go 文件和 go.mod：跳过 gopp.index.exclude 中的路径，
+ * gopp.index.respectGitignore 开启时跳过 .gitignore 忽略的文件
+ * Find the workspace Go files and go.mod files to index: paths in gopp.index.exclude are skipped,
+ * and so are files ignored by .gitignore when gopp.index.respectGitignore is on
+ * @returns 文件 URI 列表 (file URIs)
+ */
+export async function findWorkspaceGoFiles(): Promise<vscode.Uri[]> {
+    const config = vscode.workspace.getConfiguration('gopp.index');
+    const exclude = config.get<string[]>('exclude', ['**/vendor/**', '**/testdata/**']);
+    const excludeGlob = exclude.length > 0 ? `{${exclude.join(',')}}` : undefined;
+
+    const uris = await vscode.workspace.findFiles('**/{*.go,go.mod}', excludeGlob);
+    if (!config.get<boolean>('respectGitignore', true)) {
+        return uris;
+    }
+
+    const rules = await loadIgnoreRules(excludeGlob);
+    return rules.length === 0 ? uris : uris.filter(uri => !isIgnored(rules, uri.fsPath));
+}
+
+/**
+ * 读取工作空间中的 .gitignore 规则，上层目录的规则在前，使后面（更深）的规则优先
+ * Read the .gitignore rules of the workspace, rules of outer directories first so later (deeper) rules win
+ * @param excludeGlob 跳过的路径 (skipped paths)
+ */
+async function loadIgnoreRules(excludeGlob?: string): Promise<IgnoreRule[]> {
+    const files = await vscode.workspace.findFiles('**/.gitignore', excludeGlob);
+    files.sort((a, b) => a.fsPath.split(path.sep).length - b.fsPath.split(path.sep).length);
+
+    const rules: IgnoreRule[] = [];
+    for (const uri of files) {
+        try {
+            const content = Buffer.from(await vscode.workspace.fs.readFile(uri)).toString('utf-8');
+            rules.push(...parseIgnoreRules(path.dirname(uri.fsPath), content));
+        } catch (error) {
+            logger.warn(`读取 .gitignore 失败: ${uri.fsPath}`, error);
+        }
+    }
+    return rules;
+}
+
+/**
+ * 解析 .gitignore 内容，支持注释、! 取反、/ 锚定、目录规则以及 *、?、** 通配符
+ * Parse .gitignore content, supporting comments, ! negation, / anchoring, directory rules and the *, ? and ** wildcards
+ * @param base .gitignore 所在目录 (directory of the .gitignore)
+ * @param content 文件内容 (file content)
+ */
+function parseIgnoreRules(base: string, content: string): IgnoreRule[] {
+    const rules: IgnoreRule[] = [];
+    for (const raw of content.split(/\r?\n/)) {
+        let line = raw.replace(/(?<!\\)\s+$/, '');
+        if (line === '' || line.startsWith('#')) {
+            continue;
+        }
+
+        const negate = line.startsWith('!');
+        if (negate) {
+            line = line.slice(1);
+        } else if (line.startsWith('\\!') || line.startsWith('\\#')) {
+            line = line.slice(1);
+        }
+        const dirOnly = line.endsWith('/');
+        line = line.replace(/\/+$/, '');
+
+        // 含有 / 的规则相对 .gitignore 所在目录匹配，否则匹配任意层级的名称
+        // Rules containing a / match relative to the .gitignore directory, others match a name at any depth
+        const anchored = line.includes('/');
+        line = line.replace(/^\//, '');
+        if (line === '') {
+            continue;
+        }
+
+        const source = line.split('/').map((segment, i, segments) => {
+            if (segment === '**') {
+                return i === segments.length - 1 ? '.*' : '(?:.*/)?';
+            }
+            const body = segment.replace(/\\(.)|([*?])|(\[[^\]]*\])|([.+^${}()|\\])/g, (_, escaped, wildcard, range, special) => {
+                if (escaped) {
+                    return escaped.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+                }
+                if (wildcard) {
+                    return wildcard === '*' ? '[^/]*' : '[^/]';
+                }
+                if (range) {
+                    return range.replace(/^\[!/, '[^');
+                }
+                return `\\${special}`;
+            });
+            return i === segments.length - 1 ? body : `${body}/`;
+        }).join('');
+
+        rules.push({
+            base,
+            pattern: new RegExp(`^${anchored ? '' : '(?:.*/)?'}${source}$`),
+            negate,
+            dirOnly
+        });
+    }
+    return rules;
+}
+
+/**
+ * 判断文件是否被忽略：文件本身或它的任一上级目录匹配规则即被忽略，最后匹配的规则决定结果
+ * Whether a file is ignored: it is when the file or any of its parent directories matches, the last matching rule decides
+ * @param rules .gitignore 规则 (.gitignore rules)
+ * @param filePath 文件路径 (file path)
+ */
+function isIgnored(rules: IgnoreRule[], filePath: string): boolean {
+    let ignored = false;
+    for (const rule of rules) {
+        const relative = path.relative(rule.base, filePath);
+        if (relative.startsWith('..') || path.isAbsolute(relative)) {
+            continue;
+        }
+
+        const segments = relative.split(path.sep);
+        const matches = segments.some((_, i) => {
+            const isFile = i === segments.length - 1;
+            return (!isFile || !rule.dirOnly) && rule.pattern.test(segments.slice(0, i + 1).join('/'));
+        });
+        if (matches) {
+            ignored = !rule.negate;
+        }
+    }
+    return ignored;
+}
@@ -0,0 +1,97 @@
+import * as vscode from 'vscode';
+import { Logger } from '../../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
+
+const logger = Logger.withContext('run_test');
+
+/**
+ * 测试函数类型
+ * Test function kind
+ */
+export type TestKind = 'test' | 'benchmark' | 'example' | 'fuzz';
+
+/**
+ * go test 运行的函数（由 WASM 返回）
+ * Function run by go test (returned by WASM)
+ */
+export interface TestFunc {
+    name: string;             // 函数名称
+    kind: TestKind;           // 测试、基准测试、示例或模糊测试
+    line: number;             // 函数定义的行号（从 1 开始）
+    subtests: Subtest[];      // 以字面量名称声明的子测试
+    output?: 'ordered' | 'unordered'; // 仅示例函数：输出检查方式，没有输出注释时不存在
+    outputLine?: number;      // 输出注释的起始行号（从 1 开始）
+    outputEndLine?: number;   // 输出注释的结束行号（从 1 开始）
+}
+
+/**
+ * 子测试
+ * Subtest
+ */
+export interface Subtest {
+    name: string;             // 完整名称，如 TestParse/empty input
+    line: number;             // t.Run 调用的行号（从 1 开始）
+}
+
+/**
+ * 查找测试文件中 go test 会运行的函数
+ * Find the functions of a test file that go test runs
+ * @param ctx 扩展上下文 (extension context)
+ * @param document 测试文件 (test file)
+ * @returns 测试函数列表 (test functions)
+ */
+export async function findTests(ctx: vscode.ExtensionContext, document: vscode.TextDocument): Promise<TestFunc[]> {
+    const subtests = vscode.workspace.getConfiguration('gopp.test').get<boolean>('subtests', true);
+    try {
+        const result = await WasmExecutor.callFunction<string>(
+            ctx,
+            GoWasmFunction.FindTestsFunc,
+            document.getText(),
+            subtests
+        );
+
+        const data = JSON.parse(result);
+        if (!Array.isArray(data)) {
+            logger.error(`查找测试函数失败: ${data.error}`);
+            return [];
+        }
+        return data as TestFunc[];
+    } catch (error) {
+        logger.error('查找测试函数时发生错误', error);
+        return [];
+    }
+}
+
+/**
+ * 生成 go test 的 -run/-bench 匹配模式，按 / 分段精确匹配
+ * Build the go test -run/-bench pattern, matching each / element exactly
+ *
+ * go test 会把子测试名称中的空格替换为下划线。
+ * go test rewrites spaces in subtest names to underscores.
+ *
+ * @param name 测试完整名称 (full test name)
+ * @returns 匹配模式 (pattern)
+ */
+export function testPattern(name: string): string {
+    return name.split('/')
+        .map(part => `^${part.replace(/ /g, '_').replace(/[.*+?^${}()|[\]\\]/g, '\\$&')}$`)
+        .join('/');
+}
+
+/**
+ * 生成运行测试的 go test 参数；模糊测试目标以 test 类型运行时只执行种子语料
+ * Build the go test flags that run a test; a fuzz target run with the test kind only runs its seed corpus
+ * @param name 测试完整名称 (full test name)
+ * @param kind 测试类型 (test kind)
+ * @param prefix 参数前缀，调试时为 -test. (flag prefix, -test. when debugging)
+ * @returns 参数列表 (flags)
+ */
+export function testFlags(name: string, kind: TestKind, prefix = '-'): string[] {
+    if (kind === 'benchmark') {
+        return [`${prefix}run`, '^$', `${prefix}bench`, testPattern(name)];
+    }
+    if (kind === 'fuzz') {
+        return [`${prefix}run`, '^$', `${prefix}fuzz`, testPattern(name)];
+    }
+    return [`${prefix}run`, testPattern(name)];
+}
@@ -0,0 +1,198 @@
+import * as fs from 'fs';
+import * as path from 'path';
+import * as crypto from 'crypto';
+import { Logger } from '../../pkg/logger';
+import { debounce } from '../../pkg/util';
+
+// 初始化日志实例
+const logger = Logger.withContext('TranslationCache');
+
+/**
+ * 缓存条目
+ * Cache entry
+ */
+interface CacheEntry {
+    result: string;       // 翻译结果 / Translation result
+    timestamp: number;    // 写入时间 / Time written
+}
+
+/**
+ * 持久化翻译缓存，按 LRU 淘汰
+ * Persistent translation cache with LRU eviction
+ *
+ * 缓存保存在扩展的全局存储目录中，重新打开文件时无需再次请求翻译接口。
+ * The cache lives in the extension's global storage directory, so reopening a
+ * file does not call the translation API again.
+ */
+export class TranslationCache {
+
+    // Map 按插入顺序迭代，最近使用的条目位于末尾
+    // Map iterates in insertion order, most recently used entries are at the end
+    private entries = new Map<string, CacheEntry>();
+
+    // 缓存过期时间（毫秒） - 默认 31 天
+    // Cache expiration time (ms) - default 31 days
+    private readonly CACHE_EXPIRATION = 31 * 24 * 60 * 60 * 1000;
+
+    // 缓存文件路径
+    // Cache file path
+    private readonly filePath: string;
+
+    // 延迟写盘，避免每次翻译都写文件
+    // Delay writes so that not every translation writes the file
+    private readonly scheduleSave = debounce(() => this.save(), 2000);
+
+    /**
+     * @param storageDir 存储目录 / Storage directory
+     * @param maxSize 最大缓存条目数 / Maximum number of entries
+     */
+    constructor(storageDir: string, private maxSize: number) {
+        this.filePath = path.join(storageDir, 'translation-cache.json');
+        this.load();
+    }
+
+    /**
+     * 生成缓存键：源文本和目标语言的哈希
+     * Generate cache key: hash of source text and target language
+     *
+     * @param text 要翻译的文本 / Text to translate
+     * @param targetLang 目标语言 / Target language
+     * @returns 缓存键 / Cache key
+     */
+    public static key(text: string, targetLang: string): string {
+        return crypto.createHash('md5')
+            .update(`${text.trim()}|${targetLang}`)
+            .digest('hex');
+    }
+
+    /**
+     * 获取缓存的翻译结果，命中时标记为最近使用
+     * Get a cached translation, marking it as most recently used on a hit
+     *
+     * @param key 缓存键 / Cache key
+     * @returns 缓存的翻译结果，如果未命中缓存则返回null / Cached translation result, or null if cache miss
+     */
+    public get(key: string): string | null {
+        const cached = this.entries.get(key);
+        if (!cached) {
+            return null;
+        }
+
+        // 如果缓存已过期，删除它
+        // If cache is expired, delete it
+        if ((Date.now() - cached.timestamp) >= this.CACHE_EXPIRATION) {
+            logger.debug('缓存已过期，删除 / Cache expired, removing');
+            this.entries.delete(key);
+            this.scheduleSave();
+            return null;
+        }
+
+        this.entries.delete(key);
+        this.entries.set(key, cached);
+        return cached.result;
+    }
+
+    /**
+     * 存入翻译结果，超出容量时淘汰最久未使用的条目
+     * Store a translation, evicting the least recently used entries when full
+     *
+     * @param key 缓存键 / Cache key
+     * @param result 翻译结果 / Translation result
+     */
+    public set(key: string, result: string): void {
+        if (this.maxSize <= 0) {
+            return;
+        }
+
+        this.entries.delete(key);
+        this.entries.set(key, { result, timestamp: Date.now() });
+        this.evict();
+        this.scheduleSave();
+    }
+
+    /**
+     * 调整最大缓存条目数
+     * Change the maximum number of entries
+     *
+     * @param maxSize 最大缓存条目数 / Maximum number of entries
+     */
+    public resize(maxSize: number): void {
+        this.maxSize = maxSize;
+        if (this.evict()) {
+            this.scheduleSave();
+        }
+    }
+
+    /**
+     * 清除过期缓存
+     * Clear expired cache
+     */
+    public clearExpired(): void {
+        const now = Date.now();
+        let removedCount = 0;
+
+        // 遍历所有缓存条目，删除过期的
+        // Iterate all cache entries, remove expired ones
+        for (const [key, value] of this.entries) {
+            if ((now - value.timestamp) >= this.CACHE_EXPIRATION) {
+                this.entries.delete(key);
+                removedCount++;
+            }
+        }
+
+        if (removedCount > 0) {
+            logger.debug(`已清除 ${removedCount} 条过期缓存 / Cleared ${removedCount} expired cache entries`);
+            this.scheduleSave();
+        }
+    }
+
+    /**
+     * 淘汰超出容量的最久未使用条目
+     * Evict least recently used entries beyond the capacity
+     *
+     * @returns 是否有条目被淘汰 / Whether any entry was evicted
+     */
+    private evict(): boolean {
+        let evicted = false;
+        for (const key of this.entries.keys()) {
+            if (this.entries.size <= Math.max(this.maxSize, 0)) {
+                break;
+            }
+            this.entries.delete(key);
+            evicted = true;
+        }
+        return evicted;
+    }
+
+    /**
+     * 从磁盘加载缓存
+     * Load the cache from disk
+     */
+    private load(): void {
+        try {
+            if (!fs.existsSync(this.filePath)) {
+                return;
+            }
+            const data = JSON.parse(fs.readFileSync(this.filePath, 'utf-8')) as [string, CacheEntry][];
+            this.entries = new Map(data);
+            this.evict();
+            logger.debug(`已加载 ${this.entries.size} 条翻译缓存 / Loaded ${this.entries.size} cache entries`);
+        } catch (error) {
+            logger.warn(`加载翻译缓存失败 / Failed to load translation cache: ${error}`);
+            this.entries.clear();
+        }
+    }
+
+    /**
+     * 将缓存写入磁盘
+     * Write the cache to disk
+     */
+    public save(): void {
+        try {
+            fs.mkdirSync(path.dirname(this.filePath), { recursive: true });
+            fs.writeFileSync(this.filePath, JSON.stringify(Array.from(this.entries.entries())), 'utf-8');
+        } catch (error) {
+            logger.warn(`保存翻译缓存失败 / Failed to save translation cache: ${error}`);
+        }
+    }
+}
@@ -0,0 +1,145 @@
+import { TranslationEngine, TranslationOptions, TranslationResult } from './engine';
+import { httpClient, HttpError } from '../../../pkg/http';
+import { Logger } from '../../../pkg/logger';
+import { time } from '../../../pkg/go/time';
+
+/**
+ * DeepL translation engine implementation.
+ * DeepL 翻译引擎实现。
+ */
+export class DeepLTranslationEngine implements TranslationEngine {
+    readonly id = 'deepl';
+    readonly name = 'DeepL Translator';
+    readonly icon = 'Ⓓ'
+
+    private readonly logger = Logger.withContext('DeepLTranslationEngine');
+    private readonly supportedLanguages: string[] = [
+        'en', 'zh', 'zh-TW', 'ja', 'ko', 'fr', 'de', 'es', 'ru', 'pt', 'it'
+    ];
+
+    // 遇到 429 限流时的最大重试次数和初始退避时间
+    // Maximum retries and initial backoff when rate limited with 429
+    private readonly maxRetries = 3;
+    private readonly initialBackoff = 500;
+
+    constructor(private readonly apiKey?: string) {
+        this.logger.debug('DeepL翻译引擎已初始化 / DeepL translation engine initialized');
+    }
+
+    /**
+     * Checks if the engine supports the specified language pair.
+     * 检查引擎是否支持指定的语言对。
+     */
+    supportsLanguagePair(from: string, to: string): boolean {
+        return this.supportedLanguages.includes(from) && this.supportedLanguages.includes(to);
+    }
+
+    /**
+     * Gets the list of supported languages by this engine.
+     * 获取此引擎支持的语言列表。
+     */
+    async getSupportedLanguages(): Promise<string[]> {
+        return Promise.resolve(this.supportedLanguages);
+    }
+
+    /**
+     * Translates the given text according to the specified options.
+     * 根据指定的选项翻译给定的文本。
+     * 翻译失败时返回原文
+     * Returns the original text when translation fails
+     */
+    async translate(text: string, options: TranslationOptions): Promise<TranslationResult> {
+        const [result] = await this.translateBatch([text], options);
+        return result;
+    }
+
+    /**
+     * Translates several texts in one request, DeepL accepts an array of texts.
+     * 在一次请求中翻译多段文本，DeepL 接口接受文本数组。
+     * 翻译失败的文本返回原文
+     * Texts that fail to translate are returned unchanged
+     */
+    async translateBatch(texts: string[], options: TranslationOptions): Promise<TranslationResult[]> {
+        const original = texts.map(text => ({ text, from: options.from, to: options.to }));
+
+        // 如果没有API密钥，返回原文
+        // If no API key is provided, return the original text
+        if (!this.apiKey) {
+            this.logger.warn('未提供DeepL API密钥，无法执行翻译 / No DeepL API key provided, cannot perform translation');
+            return original;
+        }
+
+        // 免费密钥以 :fx 结尾，使用免费版接口
+        // Free keys end with :fx and use the free API host
+        const host = this.apiKey.endsWith(':fx') ? 'api-free.deepl.com' : 'api.deepl.com';
+        const requestUrl = `https://${host}/v2/translate`;
+        const requestData = {
+            text: texts,
+            source_lang: this.convertToDeepLSourceCode(options.from || 'en'),
+            target_lang: this.convertToDeepLTargetCode(options.to)
+        };
+        const requestOptions = {
+            headers: { 'Authorization': `DeepL-Auth-Key ${this.apiKey}` },
+            timeout: options.timeout
+        };
+
+        for (let attempt = 0; ; attempt++) {
+            try {
+                const response = await httpClient.Post<{ translations: { text: string }[] }>(
+                    requestUrl,
+                    requestData,
+                    requestOptions
+                );
+
+                // 提取翻译结果，缺失的段落返回原文
+                // Extract translation results, missing segments return the original text
+                return original.map((item, i) => {
+                    const translated = response?.translations?.[i]?.text;
+                    return translated ? { ...item, text: translated, raw: response } : item;
+                });
+            } catch (error) {
+                // 429 限流时指数退避后重试
+                // Retry with exponential backoff when rate limited with 429
+                if (error instanceof HttpError && error.statusCode === 429 && attempt < this.maxRetries) {
+                    const delay = this.initialBackoff * Math.pow(2, attempt);
+                    this.logger.debug(`DeepL限流，${delay}ms后重试 / DeepL rate limited, retrying in ${delay}ms`);
+                    await time.sleep(delay);
+                    continue;
+                }
+
+                this.logger.error('DeepL翻译请求失败 / DeepL translation request failed:', error);
+                return original;
+            }
+        }
+    }
+
+    /**
+     * 将标准语言代码转换为DeepL源语言代码
+     * Convert standard language codes to DeepL source language codes
+     */
+    private convertToDeepLSourceCode(langCode: string): string {
+        // 源语言不区分变体，如 zh-TW -> ZH
+        // Source languages have no variants, e.g. zh-TW -> ZH
+        return langCode.split('-')[0].toUpperCase();
+    }
+
+    /**
+     * 将标准语言代码转换为DeepL目标语言代码
+     * Convert standard language codes to DeepL target language codes
+     */
+    private convertToDeepLTargetCode(langCode: string): string {
+        switch (langCode) {
+        case 'zh':
+        case 'zh-CN':
+            return 'ZH-HANS';
+        case 'zh-TW':
+            return 'ZH-HANT';
+        case 'en':
+            return 'EN-US';
+        case 'pt':
+            return 'PT-PT';
+        default:
+            return langCode.toUpperCase();
+        }
+    }
+}
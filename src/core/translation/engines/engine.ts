@@ -52,6 +52,22 @@ export interface TranslationEngine {
      */
     translate(text: string, options: TranslationOptions): Promise<TranslationResult>;
 
+    /**
+     * Translates several texts in one request, if the engine supports it natively.
+     * 如果引擎原生支持，在一次请求中翻译多段文本。
+     *
+     * @param texts - The texts to translate / 要翻译的文本
+     * @param options - Translation options / 翻译选项
+     * @returns A promise that resolves to one result per text, in order / 按顺序解析为每段文本一个结果的 Promise
+     */
+    translateBatch?(texts: string[], options: TranslationOptions): Promise<TranslationResult[]>;
+
+    /**
+     * The delimiter used to join texts into one request when translateBatch is not implemented.
+     * 未实现 translateBatch 时，用于将多段文本合并为一次请求的分隔符。
+     */
+    readonly segmentDelimiter?: string;
+
     /**
      * Checks if the engine supports the specified language pair.
      * 检查引擎是否支持指定的语言对。
@@ -6,12 +6,14 @@ import { MicrosoftTranslationEngine } from './microsoft';
 import { GoogleTranslationEngine } from './google';
 import { VolcengineTranslationEngine } from './volcengine';
 import { TencentTranslationEngine } from './tencent';
+import { DeepLTranslationEngine } from './deepl';
 
 export {
     MicrosoftTranslationEngine,
     GoogleTranslationEngine,
     VolcengineTranslationEngine,
-    TencentTranslationEngine
+    TencentTranslationEngine,
+    DeepLTranslationEngine
 };
 
 /**
@@ -23,6 +25,7 @@ export const ENGINE_TYPES = {
     GOOGLE: 'google',
     VOLCENGINE: 'volcengine',
     TENCENT: 'tencent',
+    DEEPL: 'deepl',
     AUTO: 'auto'  // 自动选择引擎类型 / Auto select engine type
 };
 
@@ -37,6 +40,7 @@ export interface TranslationEngineConfig {
     volcengineSecretAccessKey?: string;
     tencentSecretId?: string;
     tencentSecretKey?: string;
+    deeplApiKey?: string;
     engineType?: string; // 引擎类型 / Engine type
 }
 
@@ -56,6 +60,8 @@ export function createTranslationEngine(type: string, config: TranslationEngineC
         return new GoogleTranslationEngine(config.googleApiKey);
     case ENGINE_TYPES.VOLCENGINE:
         return new VolcengineTranslationEngine(config.volcengineAccessKeyId, config.volcengineSecretAccessKey);
+    case ENGINE_TYPES.DEEPL:
+        return new DeepLTranslationEngine(config.deeplApiKey);
     default:
         return new TencentTranslationEngine(config.tencentSecretId, config.tencentSecretKey);
     }
@@ -0,0 +1,71 @@
+/**
+ * 遮蔽后的文本及被替换的代码片段
+ * Masked text and the code spans it replaces
+ */
+export interface MaskedText {
+    text: string;             // 代码片段替换为占位符的文本 / Text with code spans replaced by placeholders
+    spans: string[];          // 按占位符序号排列的代码片段 / Code spans indexed by placeholder number
+}
+
+// 反引号代码、Go 文档链接 [Greeter.SayHello] 以及标识符链 Greeter.SayHello()
+// Backtick code, Go doc links [Greeter.SayHello] and identifier chains Greeter.SayHello()
+const CODE_SPAN = /`[^`\n]+`|\[\*?[A-Za-z_][\w.]*\]|\b[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*(?:\(\))?/g;
+
+// 占位符 {{0}}；翻译引擎可能在括号间插入空格或改用全角括号
+// Placeholder {{0}}; engines may insert spaces between the braces or turn them into full-width ones
+const PLACEHOLDER = /[{｛]\s*[{｛]\s*(\d+)\s*[}｝]\s*[}｝]/g;
+
+/**
+ * 将注释中的代码片段替换为编号占位符，避免翻译引擎改写它们：反引号代码与文档链接总是遮蔽，
+ * 标识符链只在其中某一段是已声明的符号时遮蔽，因此 Greeter.SayHello 保持原样，而普通单词照常翻译
+ * Replace the code spans of a comment with numbered placeholders so engines do not rewrite them:
+ * backtick code and doc links are always masked, identifier chains only when one of their parts
+ * is a declared symbol, so Greeter.SayHello stays intact while ordinary words are still translated
+ *
+ * @param text 要翻译的文本 / Text to translate
+ * @param symbols 已声明的符号名称 / Names of declared symbols
+ * @returns 遮蔽后的文本 / Masked text
+ */
+export function maskCodeSpans(text: string, symbols: ReadonlySet<string> = new Set()): MaskedText {
+    // 原文本身含有占位符形式的文本时无法区分，不做遮蔽
+    // Text already containing something that looks like a placeholder cannot be told apart, leave it as is
+    if (new RegExp(PLACEHOLDER.source).test(text)) {
+        return { text, spans: [] };
+    }
+
+    const spans: string[] = [];
+    const masked = text.replace(CODE_SPAN, span => {
+        const isCode = span.startsWith('`') || span.startsWith('[')
+            || span.replace(/\(\)$/, '').split('.').some(part => part.length > 1 && symbols.has(part));
+        if (!isCode) {
+            return span;
+        }
+        spans.push(span);
+        return `{{${spans.length - 1}}}`;
+    });
+    return { text: masked, spans };
+}
+
+/**
+ * 将译文中的占位符还原为代码片段。占位符按编号而非位置还原，译文调整语序后仍能正确还原；
+ * 任一占位符丢失时返回 undefined，由调用方改用未遮蔽的译文
+ * Restore the code spans of the placeholders in a translation. Placeholders are restored by number rather
+ * than by position, so this still works when the engine reorders the words around them; returns undefined
+ * when a placeholder is lost, so the caller can fall back to an unmasked translation
+ *
+ * @param translated 译文 / Translation
+ * @param spans 代码片段 / Code spans
+ * @returns 还原后的译文 / Restored translation
+ */
+export function restoreCodeSpans(translated: string, spans: string[]): string | undefined {
+    const restored = new Set<number>();
+    const text = translated.replace(PLACEHOLDER, (placeholder, index: string) => {
+        const i = Number(index);
+        if (i >= spans.length) {
+            return placeholder;
+        }
+        restored.add(i);
+        return spans[i];
+    });
+    return restored.size === spans.length ? text : undefined;
+}
@@ -0,0 +1,131 @@
+import * as fs from 'fs';
+import * as path from 'path';
+import { Logger } from '../../pkg/logger';
+import { debounce } from '../../pkg/util';
+
+// 初始化日志实例
+const logger = Logger.withContext('TranslationOverrides');
+
+/**
+ * 手动修正的译文
+ * Manually corrected translation
+ */
+interface OverrideEntry {
+    result: string;       // 修正后的译文 / Corrected translation
+    files: string[];      // 修正时所在的文件 / Files the correction was made in
+}
+
+/**
+ * 手动修正译文的持久化存储，优先于翻译缓存和翻译引擎
+ * Persistent store of manually corrected translations, checked before the cache and the engines
+ *
+ * 键与 TranslationCache 相同，是源文本和目标语言的哈希：修改注释原文后哈希改变，修正自然失效；
+ * 修正不会过期也不参与 LRU 淘汰，只能通过命令清除。
+ * Keys are the same as those of TranslationCache, a hash of the source text and target language:
+ * editing the comment changes the hash, so the correction no longer applies; corrections never
+ * expire nor get evicted, they are only removed by command.
+ */
+export class TranslationOverrides {
+
+    private entries = new Map<string, OverrideEntry>();
+
+    // 存储文件路径
+    // Store file path
+    private readonly filePath: string;
+
+    // 延迟写盘
+    // Delay writes
+    private readonly scheduleSave = debounce(() => this.save(), 2000);
+
+    /**
+     * @param storageDir 存储目录 / Storage directory
+     */
+    constructor(storageDir: string) {
+        this.filePath = path.join(storageDir, 'translation-overrides.json');
+        this.load();
+    }
+
+    /**
+     * 获取修正的译文
+     * Get a corrected translation
+     *
+     * @param key 缓存键 / Cache key
+     * @returns 修正的译文，没有修正时返回 null / Corrected translation, or null without a correction
+     */
+    public get(key: string): string | null {
+        return this.entries.get(key)?.result ?? null;
+    }
+
+    /**
+     * 保存修正的译文
+     * Store a corrected translation
+     *
+     * @param key 缓存键 / Cache key
+     * @param result 修正的译文 / Corrected translation
+     * @param file 修正时所在的文件 / File the correction is made in
+     */
+    public set(key: string, result: string, file: string): void {
+        const files = this.entries.get(key)?.files ?? [];
+        if (!files.includes(file)) {
+            files.push(file);
+        }
+        this.entries.set(key, { result, files });
+        this.scheduleSave();
+    }
+
+    /**
+     * 清除在某个文件中做出的修正，其他文件中为同一注释做出的修正保留
+     * Clear the corrections made in a file, corrections made for the same comment in other files are kept
+     *
+     * @param file 文件路径 / File path
+     * @returns 清除的修正数 / Number of corrections removed
+     */
+    public clearFile(file: string): number {
+        let removed = 0;
+        for (const [key, entry] of this.entries) {
+            if (!entry.files.includes(file)) {
+                continue;
+            }
+            entry.files = entry.files.filter(f => f !== file);
+            if (entry.files.length === 0) {
+                this.entries.delete(key);
+            }
+            removed++;
+        }
+        if (removed > 0) {
+            this.scheduleSave();
+        }
+        return removed;
+    }
+
+    /**
+     * 从磁盘加载修正
+     * Load the corrections from disk
+     */
+    private load(): void {
+        try {
+            if (!fs.existsSync(this.filePath)) {
+                return;
+            }
+            const data = JSON.parse(fs.readFileSync(this.filePath, 'utf-8')) as [string, OverrideEntry][];
+            this.entries = new Map(data);
+            logger.debug(`已加载 ${this.entries.size} 条手动译文 / Loaded ${this.entries.size} manual translations`);
+        } catch (error) {
+            logger.warn(`加载手动译文失败 / Failed to load manual translations: ${error}`);
+            this.entries.clear();
+        }
+    }
+
+    /**
+     * 将修正写入磁盘
+     * Write the corrections to disk
+     */
+    public save(): void {
+        try {
+            fs.mkdirSync(path.dirname(this.filePath), { recursive: true });
+            fs.writeFileSync(this.filePath, JSON.stringify(Array.from(this.entries.entries())), 'utf-8');
+        } catch (error) {
+            logger.warn(`保存手动译文失败 / Failed to save manual translations: ${error}`);
+        }
+    }
+}
@@ -4,10 +4,58 @@ import { Logger } from '../../pkg/logger';
 import { IsGoFile } from '../../pkg/cond';
 import { debounce } from '../../pkg/util';
 import { RequestQueue } from '../../pkg/queue';
+import { WasmExecutor, GoWasmFunction } from '../../pkg/wasm';
 
 // 初始化日志实例
 const logger = Logger.withContext('TranslationProvider');
 
+/**
+ * 翻译范围：doc-only 只翻译文档注释，line-only 只翻译函数内注释与行尾注释，all 翻译全部注释
+ * Translation scope: doc-only translates doc comments, line-only translates comments inside
+ * functions and trailing comments, all translates every comment
+ */
+type TranslationScope = 'doc-only' | 'line-only' | 'all';
+
+/**
+ * 译文显示方式：decoration 使用行尾装饰，inlay 使用内嵌提示
+ * Translation display: decoration renders end-of-line decorations, inlay renders inlay hints
+ */
+type TranslationDisplay = 'decoration' | 'inlay';
+
+/**
+ * 多行注释的内嵌提示：perLine 每行一个，summary 在注释末尾显示一个汇总
+ * Inlay hints of multi-line comments: perLine adds one per line, summary one at the end of the comment
+ */
+type InlayBlockHints = 'perLine' | 'summary';
+
+/**
+ * 文档的注释信息（由 WASM ClassifyComments 返回），按注释起始行（从 0 开始）索引
+ * Comment information of a document (returned by WASM ClassifyComments), keyed by the 0-based start line of the comment
+ */
+interface CommentInfo {
+    uri: string;
+    version: number;
+    kinds: Map<number, string>;            // 注释类型 / Comment kinds
+    anchors: Map<number, vscode.Position>; // 注释结束之后的位置，用于放置内嵌提示 / Position after the comment, for inlay hints
+}
+
+// 每个翻译范围包含的注释类型（与 WASM ClassifyComments 的类型一致）
+// Comment kinds included by each translation scope (the kinds of WASM ClassifyComments)
+const SCOPE_KINDS: Record<Exclude<TranslationScope, 'all'>, string[]> = {
+    'doc-only': ['doc'],
+    'line-only': ['inline', 'trailing'],
+};
+
+/**
+ * 已显示的注释翻译
+ * Comment translation shown in the editor
+ */
+interface CommentTranslation {
+    range: vscode.Range;                                  // 注释范围 / Comment range
+    translation: string;                                  // 译文 / Translated text
+    decorationTypes: vscode.TextEditorDecorationType[];   // 译文装饰器 / Translation decorations
+}
+
 /**
  * 翻译提供程序类
  * Translation provider class
@@ -45,18 +93,50 @@ export class TranslationProvider implements vscode.CodeActionProvider {
         targetLang: 'zh',
         autoDetect: true,
         autoTranslateOnActiveEditor: false,
+        ignorePatterns: [] as RegExp[],
+        scope: 'all' as TranslationScope,
+        display: 'decoration' as TranslationDisplay,
+        inlayBlockHints: 'perLine' as InlayBlockHints,
     };
 
+    private context: vscode.ExtensionContext;
+
+    // 当前文档的注释信息
+    // Comment information of the current document
+    private commentInfo?: CommentInfo;
+
+    // 当前文档声明的符号名称，翻译时引用它们的代码片段保持原样
+    // Symbol names declared by the current document, code spans referring to them are kept when translating
+    private symbolInfo?: { uri: string, version: number, symbols: Set<string> };
+
     private TranslationService: TranslationService;
 
     // 已翻译注释的缓存
     // Cache of translated comments
     private translatedComments = new Map<string, boolean>();
 
+    // 每个文档中已显示的注释翻译，按注释键索引，切换原文时无需重新翻译
+    // Comment translations shown per document, keyed by comment key, so
+    // toggling back from the original does not fetch the translation again
+    private commentTranslations = new Map<string, Map<string, CommentTranslation>>();
+
+    // 每个文档中切换为显示原文的注释，文件关闭时重置
+    // Comments switched to show the original per document, reset when the file closes
+    private originalComments = new Map<string, Set<string>>();
+
+    // 已显示的注释翻译变更事件，用于刷新切换 CodeLens 与内嵌提示
+    // Change event of the shown comment translations, refreshing the toggle CodeLenses and inlay hints
+    private translationsChanged = new vscode.EventEmitter<void>();
+    public readonly onDidChangeTranslations = this.translationsChanged.event;
+
     // 翻译请求队列
     // Translation request queue
     private translationQueue: RequestQueue;
 
+    // 当前悬停的翻译请求，悬停到其他文本时取消
+    // Translation request of the current hover, cancelled when another text is hovered
+    private hoverRequest?: { text: string, cancel: vscode.CancellationTokenSource };
+
     // 最大并发翻译请求数
     // Maximum number of concurrent translation requests
     private readonly MAX_CONCURRENT_TRANSLATIONS = 3;
@@ -65,11 +145,21 @@ export class TranslationProvider implements vscode.CodeActionProvider {
     // Add a translation operation lock to prevent duplicate calls
     private translationInProgress = false;
 
+    // 翻译进行中时配置变更，翻译结束后需要重新渲染
+    // Configuration changed during a translation, re-render once it finishes
+    private refreshPending = false;
+
+    // 影响翻译结果的配置项
+    // Settings that affect translation results
+    private readonly TRANSLATION_SETTINGS = ['sourceLanguage', 'targetLanguage', 'autoDetectLanguage', 'engineType', 'ignorePatterns', 'scope', 'display', 'inlayBlockHints'];
+
     /**
      * 构造函数
      * Constructor
      */
     constructor(context: vscode.ExtensionContext) {
+        this.context = context;
+
         // 初始化配置
         // Initialize configuration
         this.loadConfig();
@@ -108,6 +198,12 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             vscode.workspace.onDidChangeTextDocument(this.handleDocumentChange, this)
         );
 
+        // 订阅文档关闭事件，重置该文件的原文/译文切换状态
+        // Subscribe to document close events to reset the file's toggle state
+        context.subscriptions.push(
+            vscode.workspace.onDidCloseTextDocument(this.handleDocumentClose, this)
+        );
+
         // 订阅编辑器可见范围变更事件
         // Subscribe to editor visible ranges change events
         context.subscriptions.push(
@@ -128,6 +224,38 @@ export class TranslationProvider implements vscode.CodeActionProvider {
                 () => this.translateVisibleComments()
             )
         );
+
+        // 注册当前文件注释批量翻译命令
+        // Register current file comments batch translation command
+        context.subscriptions.push(
+            vscode.commands.registerCommand(
+                'gopp.translateFileComments',
+                () => this.translateFileComments()
+            )
+        );
+
+        // 注册跳转到下一个/上一个未翻译注释的命令
+        // Register commands jumping to the next/previous untranslated comment
+        context.subscriptions.push(
+            vscode.commands.registerCommand('gopp.translation.nextUntranslated', () => this.gotoUntranslated(1)),
+            vscode.commands.registerCommand('gopp.translation.previousUntranslated', () => this.gotoUntranslated(-1))
+        );
+
+        // 注册注释原文/译文切换命令
+        // Register comment original/translation toggle command
+        context.subscriptions.push(
+            vscode.commands.registerCommand(
+                'gopp.toggleCommentTranslation',
+                (uri?: string, key?: string) => this.toggleCommentTranslation(uri, key)
+            )
+        );
+
+        // 注册手动修正译文与清除修正的命令
+        // Register commands correcting a translation manually and clearing the corrections
+        context.subscriptions.push(
+            vscode.commands.registerCommand('gopp.translation.editTranslation', () => this.editCommentTranslation()),
+            vscode.commands.registerCommand('gopp.translation.clearOverrides', () => this.clearOverrides())
+        );
     }
 
     /**
@@ -141,16 +269,85 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             targetLang: config.targetLanguage,
             autoDetect: config.autoDetectLanguage,
             autoTranslateOnActiveEditor: config.autoTranslateOnActiveEditor,
+            ignorePatterns: this.compileIgnorePatterns(config.get<string[]>('ignorePatterns', [])),
+            scope: config.get<TranslationScope>('scope', 'all'),
+            display: config.get<TranslationDisplay>('display', 'decoration'),
+            inlayBlockHints: config.get<InlayBlockHints>('inlayBlockHints', 'perLine'),
         };
     }
 
+    /**
+     * 编译忽略注释的正则表达式，跳过无效的表达式
+     * Compile the regexes of ignored comments, skipping invalid ones
+     *
+     * @param patterns 正则表达式字符串 / Regex strings
+     */
+    private compileIgnorePatterns(patterns: string[]): RegExp[] {
+        const regexes: RegExp[] = [];
+        for (const pattern of patterns) {
+            try {
+                regexes.push(new RegExp(pattern));
+            } catch (error) {
+                logger.warn(`无效的忽略规则 / Invalid ignore pattern: ${pattern}`, error);
+            }
+        }
+        return regexes;
+    }
+
     /**
      * 处理配置变更
      * Handle configuration change
      */
     private handleConfigChange(event: vscode.ConfigurationChangeEvent): void {
-        if (event.affectsConfiguration(this.configKey)) {
-            this.loadConfig();
+        if (!event.affectsConfiguration(this.configKey)) {
+            return;
+        }
+
+        this.loadConfig();
+
+        // 语言或引擎变更后，已显示的翻译立即失效并重新渲染，无需重载窗口
+        // When the language or engine changes, shown translations are stale:
+        // re-render them right away instead of requiring a window reload
+        const affected = this.TRANSLATION_SETTINGS.some(key =>
+            event.affectsConfiguration(`${this.configKey}.${key}`)
+        );
+        if (affected) {
+            this.refreshTranslations();
+        }
+    }
+
+    /**
+     * 按当前配置重新渲染已显示的翻译
+     * Re-render shown translations with the current configuration
+     */
+    private refreshTranslations(): void {
+        if (!this.editor) {
+            return;
+        }
+
+        // 正在翻译时等待其结束，避免旧语言的结果覆盖新结果
+        // Wait for a running translation so its old-language results don't win
+        if (this.translationInProgress) {
+            this.refreshPending = true;
+            return;
+        }
+
+        const hadTranslations = this.translatedComments.size > 0;
+        this.clearDecorations();
+        if (hadTranslations || this.config.autoTranslateOnActiveEditor) {
+            this.translateVisibleComments(/* dontClearDecorations */ true);
+        }
+    }
+
+    /**
+     * 翻译结束后处理挂起的重新渲染
+     * Run a pending re-render once a translation has finished
+     */
+    private finishTranslation(): void {
+        this.translationInProgress = false;
+        if (this.refreshPending) {
+            this.refreshPending = false;
+            this.refreshTranslations();
         }
     }
 
@@ -196,6 +393,8 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             // 清除已翻译注释缓存
             // Clear translated comments cache
             this.translatedComments.clear();
+            this.commentTranslations.delete(this.editor.document.uri.toString());
+            this.translationsChanged.fire();
         }
     }
 
@@ -268,6 +467,7 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             // 使用提取的方法检测语言方向
             // Use extracted method to detect language direction
             const { sourceLang, targetLang } = this.detectLanguageDirection(text);
+            const symbols = await this.loadSymbols(this.editor.document);
 
             // 显示翻译状态信息
             // Show translation status message
@@ -289,7 +489,8 @@ export class TranslationProvider implements vscode.CodeActionProvider {
                         const translatedText = await this.TranslationService.translate(
                             text,
                             targetLang,
-                            sourceLang
+                            sourceLang,
+                            symbols
                         );
 
                         progress.report({ increment: 60, message: '更新显示... / Updating display...' });
@@ -307,6 +508,7 @@ export class TranslationProvider implements vscode.CodeActionProvider {
                         text,
                         targetLang,
                         sourceLang,
+                        symbols
                     );
 
                     // 显示翻译结果
@@ -328,7 +530,7 @@ export class TranslationProvider implements vscode.CodeActionProvider {
         } finally {
             // 无论成功或失败，都释放锁
             // Release lock regardless of success or failure
-            this.translationInProgress = false;
+            this.finishTranslation();
         }
     }
 
@@ -489,6 +691,19 @@ export class TranslationProvider implements vscode.CodeActionProvider {
         }
     }
 
+    /**
+     * 处理文档关闭
+     * Handle document close
+     *
+     * @param document 已关闭的文档 / Closed document
+     */
+    private handleDocumentClose(document: vscode.TextDocument): void {
+        const uri = document.uri.toString();
+        this.commentTranslations.delete(uri);
+        this.originalComments.delete(uri);
+        this.translationsChanged.fire();
+    }
+
     /**
      * 防抖动的翻译可见内容函数
      * Debounced function to translate visible content
@@ -633,92 +848,16 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             // 更新状态栏信息
             // Update status bar message
             statusMessage.dispose();
-            const queueStatusMessage = vscode.window.setStatusBarMessage(
-                `ʕ◔ϖ◔ʔ queueing ${untranslatedComments.length}`
+            const progressMessage = vscode.window.setStatusBarMessage(
+                `ʕ◔ϖ◔ʔ Translating ${untranslatedComments.length}`
             );
 
-            // 串行处理每个注释的翻译，确保不会同时发送太多请求
-            // Process translations serially to ensure we don't send too many requests at once
-            for (let i = 0; i < untranslatedComments.length; i++) {
-                const comment = untranslatedComments[i];
-                const commentText = comment.text.trim();
-
-                queueStatusMessage.dispose();
-                const progressMessage = vscode.window.setStatusBarMessage(
-                    `ʕ◔ϖ◔ʔ Translating ${i+1}/${untranslatedComments.length}`
-                );
-
-                // 使用提取的方法检测语言方向
-                // Use extracted method to detect language direction
-                const { sourceLang, targetLang } = this.detectLanguageDirection(commentText);
-                try {
-
-                    // 执行翻译 - 通过队列控制请求频率
-                    // Perform translation - control request rate through queue
-                    const translatedText = await this.translationQueue.enqueue(async () => {
-                        return await this.TranslationService.translate(
-                            commentText,
-                            targetLang,
-                            sourceLang,
-                        );
-                    });
-
-                    // 检查翻译结果是否为空
-                    // Check if translation result is empty
-                    if (!translatedText || translatedText === commentText) {
-                        logger.warn('Translation result is empty or same as original, skipping display');
-                        continue;
-                    }
-
-                    // 显示翻译结果
-                    // Display translation result
-                    this.showCommentTranslation(comment.range, translatedText);
-
-                    // 标记该注释为已翻译
-                    // Mark this comment as translated
-                    this.markCommentAsTranslated(comment.range);
-
-                    newlyTranslatedCount++;
-                } catch (error) {
-                    logger.error('Failed to translate comment:', error);
-
-                    // 如果因为请求限制失败，增加延迟后重试
-                    // If failed due to request limit, add delay and retry
-                    if (error.toString().includes('RequestLimitExceeded')) {
-                        logger.info('Request rate limit detected, pausing before retry');
-                        await new Promise(resolve => setTimeout(resolve, 2000)); // 暂停2秒后重试 / Pause for 2 seconds before retry
-
-                        // 重试一次
-                        // Retry once
-                        try {
-                            const translatedText = await this.translationQueue.enqueue(async () => {
-                                return await this.TranslationService.translate(
-                                    commentText,
-                                    targetLang,
-                                    sourceLang,
-                                );
-                            });
-
-                            if (translatedText && translatedText !== commentText) {
-                                this.showCommentTranslation(comment.range, translatedText);
-                                this.markCommentAsTranslated(comment.range);
-                                newlyTranslatedCount++;
-                            }
-                        } catch (retryError) {
-                            logger.error('Retry translation failed:', retryError);
-                        }
-                    }
-                } finally {
-                    progressMessage.dispose();
-                }
-
-                // 每个注释之间增加一个小延迟，进一步确保不会超出API速率限制
-                // Add a small delay between comments to further ensure we don't exceed API rate limits
-                await new Promise(resolve => setTimeout(resolve, 300));
+            try {
+                newlyTranslatedCount = await this.translateComments(untranslatedComments);
+            } finally {
+                progressMessage.dispose();
             }
 
-            queueStatusMessage.dispose();
-
             if (!dontClearDecorations && newlyTranslatedCount > 0) {
                 vscode.window.showInformationMessage(`ok ${newlyTranslatedCount}`);
             }
@@ -729,7 +868,198 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             }
         } finally {
             statusMessage.dispose();
-            this.translationInProgress = false;
+            this.finishTranslation();
+        }
+    }
+
+    /**
+     * 批量翻译当前文件中所有未翻译的注释
+     * Batch-translate all untranslated comments in the current file
+     */
+    public async translateFileComments(): Promise<void> {
+        if (!this.editor || !IsGoFile(this.editor.document)) {
+            return;
+        }
+
+        // 如果已经在进行翻译，则忽略此次调用
+        // If translation is already in progress, ignore this call
+        if (this.translationInProgress) {
+            logger.info('翻译操作正在进行中，忽略重复调用 / Translation operation in progress, ignoring duplicate call');
+            return;
+        }
+
+        this.translationInProgress = true;
+        const document = this.editor.document;
+        const fullRange = new vscode.Range(new vscode.Position(0, 0), document.lineAt(document.lineCount - 1).range.end);
+        const comments = this.extractCommentsFromRange(document, fullRange)
+            .filter(comment => comment.text.trim() && !this.isCommentAlreadyTranslated(comment.range));
+
+        const statusMessage = vscode.window.setStatusBarMessage(`ʕ◔ϖ◔ʔ Translating ${comments.length}`);
+        try {
+            const count = await this.translateComments(comments);
+            vscode.window.showInformationMessage(`ok ${count}/${comments.length}`);
+        } catch (error) {
+            logger.error('Error translating file comments:', error);
+            vscode.window.showErrorMessage('Failed to translate comments');
+        } finally {
+            statusMessage.dispose();
+            this.finishTranslation();
+        }
+    }
+
+    /**
+     * 批量翻译注释并显示结果
+     * Batch-translate comments and show the results
+     *
+     * 注释按翻译方向分组，每组通过一次批量请求翻译；翻译失败的注释保持原文。
+     * 匹配 ignorePatterns 或不在 scope 范围内的注释不翻译。
+     * Comments are grouped by translation direction and each group is sent as
+     * one batch; comments that fail to translate keep showing the original text.
+     * Comments matching ignorePatterns or outside the scope are not translated.
+     *
+     * @param comments 注释列表 / Comments
+     * @returns 新翻译的注释数 / Number of newly translated comments
+     */
+    private async translateComments(comments: Array<{ text: string, range: vscode.Range }>): Promise<number> {
+        // 匹配忽略规则的注释（如生成代码的文件头）不进入批量翻译和缓存
+        // Comments matching an ignore pattern (such as generated-code headers)
+        // never reach the batching and caching layer
+        comments = comments.filter(comment => !this.config.ignorePatterns.some(regex => regex.test(comment.text.trim())));
+        comments = await this.filterByScope(comments);
+
+        const groups = new Map<string, { sourceLang: string, targetLang: string, comments: typeof comments }>();
+        for (const comment of comments) {
+            const { sourceLang, targetLang } = this.detectLanguageDirection(comment.text.trim());
+            const key = `${sourceLang}>${targetLang}`;
+            if (!groups.has(key)) {
+                groups.set(key, { sourceLang, targetLang, comments: [] });
+            }
+            groups.get(key)!.comments.push(comment);
+        }
+
+        const symbols = await this.loadSymbols(this.editor?.document);
+        let count = 0;
+        for (const group of groups.values()) {
+            const texts = group.comments.map(comment => comment.text.trim());
+
+            // 执行翻译 - 通过队列控制请求频率
+            // Perform translation - control request rate through queue
+            const translated = await this.translationQueue.enqueue(async () => {
+                return await this.TranslationService.translateBatch(texts, group.targetLang, group.sourceLang, symbols);
+            });
+
+            group.comments.forEach((comment, i) => {
+                // 检查翻译结果是否为空
+                // Check if translation result is empty
+                if (!translated[i] || translated[i] === texts[i]) {
+                    logger.warn('Translation result is empty or same as original, skipping display');
+                    return;
+                }
+
+                this.showCommentTranslation(comment.range, translated[i]);
+                this.markCommentAsTranslated(comment.range);
+                count++;
+            });
+        }
+
+        this.checkAndClearExpiredCache();
+        return count;
+    }
+
+    /**
+     * 按 scope 配置过滤注释，注释类型由 WASM 根据 AST 判断
+     * Filter comments by the scope setting, the comment kinds come from the AST via WASM
+     *
+     * @param comments 注释列表 / Comments
+     * @returns 范围内的注释 / Comments within the scope
+     */
+    private async filterByScope(comments: Array<{ text: string, range: vscode.Range }>): Promise<Array<{ text: string, range: vscode.Range }>> {
+        const document = this.editor?.document;
+        if (this.config.scope === 'all' || !document || !IsGoFile(document)) {
+            return comments;
+        }
+
+        const info = await this.loadCommentInfo(document);
+        if (!info) {
+            logger.warn('注释分类失败，翻译全部注释 / Failed to classify comments, translating all');
+            return comments;
+        }
+
+        const allowed = SCOPE_KINDS[this.config.scope];
+        return comments.filter(comment => allowed.includes(info.kinds.get(comment.range.start.line) ?? ''));
+    }
+
+    /**
+     * 获取文档中顶层声明的名称（类型、函数、方法、变量与常量），按文档版本缓存
+     * Get the names of the top-level declarations of a document (types, functions, methods, variables and constants),
+     * cached per document version
+     *
+     * @param document 文档 / Document
+     * @returns 符号名称，非 Go 文件或失败时为空 / Symbol names, empty for non-Go files or on failure
+     */
+    private async loadSymbols(document?: vscode.TextDocument): Promise<Set<string>> {
+        if (!document || !IsGoFile(document)) {
+            return new Set();
+        }
+        const uri = document.uri.toString();
+        if (this.symbolInfo && this.symbolInfo.uri === uri && this.symbolInfo.version === document.version) {
+            return this.symbolInfo.symbols;
+        }
+
+        try {
+            const result = await WasmExecutor.callFunction<string>(this.context, GoWasmFunction.FileOutlineFunc, document.getText());
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                logger.warn(`获取文件大纲失败 / Failed to get file outline: ${data.error}`);
+                return new Set();
+            }
+
+            const symbols = new Set<string>();
+            for (const entry of data as Array<{ name: string, receiver?: string }>) {
+                symbols.add(entry.name);
+                if (entry.receiver) {
+                    symbols.add(entry.receiver.replace(/^\*/, '').replace(/\[.*$/, ''));
+                }
+            }
+            this.symbolInfo = { uri, version: document.version, symbols };
+            return symbols;
+        } catch (error) {
+            logger.error('获取文件大纲出错 / Error getting file outline:', error);
+            return new Set();
+        }
+    }
+
+    /**
+     * 获取文档的注释类型与内嵌提示锚点，按文档版本缓存
+     * Get the comment kinds and inlay hint anchors of a document, cached per document version
+     *
+     * @param document 文档 / Document
+     * @returns 注释信息，失败时为 undefined / Comment information, undefined on failure
+     */
+    private async loadCommentInfo(document: vscode.TextDocument): Promise<CommentInfo | undefined> {
+        const uri = document.uri.toString();
+        if (this.commentInfo && this.commentInfo.uri === uri && this.commentInfo.version === document.version) {
+            return this.commentInfo;
+        }
+
+        try {
+            const result = await WasmExecutor.callFunction<string>(this.context, GoWasmFunction.ClassifyCommentsFunc, document.getText());
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                logger.warn(`注释分类失败 / Failed to classify comments: ${data.error}`);
+                return undefined;
+            }
+
+            const info: CommentInfo = { uri, version: document.version, kinds: new Map(), anchors: new Map() };
+            for (const item of data as Array<{ kind: string, line: number, anchor: { line: number, column: number } }>) {
+                info.kinds.set(item.line - 1, item.kind);
+                info.anchors.set(item.line - 1, new vscode.Position(item.anchor.line - 1, item.anchor.column - 1));
+            }
+            this.commentInfo = info;
+            return info;
+        } catch (error) {
+            logger.error('注释分类出错 / Error classifying comments:', error);
+            return undefined;
         }
     }
 
@@ -823,8 +1153,44 @@ export class TranslationProvider implements vscode.CodeActionProvider {
 
         logger.debug(`显示翻译结果: "${translatedText.substring(0, 20)}..." / Showing translation result`);
 
-        const originalLines = this.editor.document.getText(commentRange).split('\n');
-        const translatedLines = translatedText.split('\n');
+        const document = this.editor.document;
+        const uri = document.uri.toString();
+        const key = this.commentKey(document, commentRange);
+
+        if (!this.commentTranslations.has(uri)) {
+            this.commentTranslations.set(uri, new Map());
+        }
+        const translations = this.commentTranslations.get(uri)!;
+        translations.get(key)?.decorationTypes.forEach(decorationType => decorationType.dispose());
+
+        const entry: CommentTranslation = { range: commentRange, translation: translatedText, decorationTypes: [] };
+        translations.set(key, entry);
+
+        // 用户切换为显示原文的注释保持原文
+        // Comments the user switched to the original stay original
+        if (!this.originalComments.get(uri)?.has(key)) {
+            this.renderCommentTranslation(this.editor, entry);
+        }
+        this.translationsChanged.fire();
+    }
+
+    /**
+     * 在编辑器中渲染注释译文
+     * Render a comment translation in the editor
+     *
+     * @param editor 编辑器 / Editor
+     * @param entry 注释翻译 / Comment translation
+     */
+    private renderCommentTranslation(editor: vscode.TextEditor, entry: CommentTranslation): void {
+        // 内嵌提示由 provideTranslationHints 按需提供
+        // Inlay hints are provided on demand by provideTranslationHints
+        if (this.config.display === 'inlay') {
+            return;
+        }
+
+        const commentRange = entry.range;
+        const originalLines = editor.document.getText(commentRange).split('\n');
+        const translatedLines = entry.translation.split('\n');
 
         // 确保原文和译文行数一致
         // Ensure the number of original and translated lines match
@@ -834,7 +1200,7 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             // 找到当前行末尾的确切位置
             // Find the exact end position of the current line
             const currentLineNumber = commentRange.start.line + i;
-            const currentLine = this.editor.document.lineAt(currentLineNumber);
+            const currentLine = editor.document.lineAt(currentLineNumber);
             const lineEndPos = currentLine.range.end;
 
             // 创建只包含行末位置的范围
@@ -852,20 +1218,270 @@ export class TranslationProvider implements vscode.CodeActionProvider {
 
             // 应用装饰器 - 确保在行尾显示而不是插入到最后一个字符前
             // Apply decorator - ensure it's shown at the end of line and not inserted before the last character
-            this.editor.setDecorations(lineDecorationType, [{
+            editor.setDecorations(lineDecorationType, [{
                 range: decorationRange,
                 hoverMessage: new vscode.MarkdownString(`**Original**:\n${originalLines[i]}\n\n**Translation**:\n${translatedLines[i]}`)
             }]);
 
             // 保存装饰器以便后续清理
             // Save decorator for later cleanup
-            if (!this.commentDecorationTypes) {
-                this.commentDecorationTypes = [];
-            }
+            entry.decorationTypes.push(lineDecorationType);
             this.commentDecorationTypes.push(lineDecorationType);
         }
     }
 
+    /**
+     * 在原文和译文之间切换注释
+     * Toggle a comment between the original and the translation
+     *
+     * 连续的行注释作为一个整体切换；未传入参数时切换光标所在的注释。
+     * Consecutive line comments flip together; without arguments the comment
+     * under the cursor is toggled.
+     *
+     * @param uri 文档 URI / Document URI
+     * @param key 注释键 / Comment key
+     */
+    public toggleCommentTranslation(uri?: string, key?: string): void {
+        const editor = uri
+            ? vscode.window.visibleTextEditors.find(e => e.document.uri.toString() === uri)
+            : vscode.window.activeTextEditor;
+        if (!editor) {
+            return;
+        }
+
+        uri = editor.document.uri.toString();
+        const translations = this.commentTranslations.get(uri);
+        if (!key && translations) {
+            const line = editor.selection.active.line;
+            for (const [k, entry] of translations) {
+                if (entry.range.start.line <= line && line <= entry.range.end.line) {
+                    key = k;
+                    break;
+                }
+            }
+        }
+
+        const block = translations && key ? this.commentBlocks(translations).find(keys => keys.includes(key!)) : undefined;
+        if (!translations || !block) {
+            vscode.window.showInformationMessage('光标处没有已翻译的注释 / No translated comment at the cursor');
+            return;
+        }
+
+        if (!this.originalComments.has(uri)) {
+            this.originalComments.set(uri, new Set());
+        }
+        const originals = this.originalComments.get(uri)!;
+        const showOriginal = !originals.has(block[0]);
+
+        for (const k of block) {
+            const entry = translations.get(k)!;
+            if (showOriginal) {
+                originals.add(k);
+                entry.decorationTypes.forEach(decorationType => decorationType.dispose());
+                entry.decorationTypes = [];
+            } else if (originals.delete(k)) {
+                this.renderCommentTranslation(editor, entry);
+            }
+        }
+        this.translationsChanged.fire();
+    }
+
+    /**
+     * 手动修正光标所在注释的译文；修正按原文保存，跨会话保留，修改注释原文后失效
+     * Correct the translation of the comment under the cursor by hand; the correction is stored
+     * by source text, persists across sessions and no longer applies once the comment changes
+     */
+    public async editCommentTranslation(): Promise<void> {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor) {
+            return;
+        }
+        this.editor = editor;
+
+        const document = editor.document;
+        const line = editor.selection.active.line;
+        const shown = Array.from(this.commentTranslations.get(document.uri.toString())?.values() ?? [])
+            .find(entry => entry.range.start.line <= line && line <= entry.range.end.line);
+        const comment = shown
+            ? this.extractCommentsFromRange(document, shown.range).find(c => c.range.isEqual(shown.range))
+            : this.extractCommentsFromRange(document, document.lineAt(line).range)[0];
+        if (!comment) {
+            vscode.window.showInformationMessage('光标处没有注释 / No comment at the cursor');
+            return;
+        }
+
+        const text = comment.text.trim();
+        const { targetLang } = this.detectLanguageDirection(text);
+        const translation = await vscode.window.showInputBox({
+            prompt: `修正译文 / Correct the translation: ${text}`,
+            value: shown?.translation ?? this.TranslationService.cached(text, targetLang) ?? ''
+        });
+        if (!translation) {
+            return;
+        }
+
+        this.TranslationService.setOverride(text, translation, document.fileName, targetLang);
+        this.showCommentTranslation(comment.range, translation);
+        this.markCommentAsTranslated(comment.range);
+    }
+
+    /**
+     * 清除当前文件中手动修正的译文并重新翻译
+     * Clear the manual corrections of the current file and translate again
+     */
+    public clearOverrides(): void {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor) {
+            return;
+        }
+        this.editor = editor;
+
+        const removed = this.TranslationService.clearOverrides(editor.document.fileName);
+        vscode.window.showInformationMessage(`已清除 ${removed} 条手动译文 / Cleared ${removed} manual translations`);
+        if (removed > 0) {
+            this.refreshTranslations();
+        }
+    }
+
+    /**
+     * 将光标移动到下一个或上一个未翻译的注释，到达文件末尾或开头时回绕。
+     * 未翻译指没有显示译文：尚未翻译、翻译中，或翻译结果与原文相同；匹配 ignorePatterns 或不在 scope 范围内的注释跳过
+     * Move the cursor to the next or previous untranslated comment, wrapping around the file.
+     * Untranslated means no translation is shown: not translated yet, pending, or translated to the
+     * original text; comments matching ignorePatterns or outside the scope are skipped
+     *
+     * @param direction 1 为下一个，-1 为上一个 / 1 for next, -1 for previous
+     */
+    public async gotoUntranslated(direction: 1 | -1): Promise<void> {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor) {
+            return;
+        }
+        this.editor = editor;
+
+        const document = editor.document;
+        const fullRange = new vscode.Range(0, 0, document.lineCount - 1, 0);
+        let comments = this.extractCommentsFromRange(document, fullRange)
+            .filter(comment => !this.config.ignorePatterns.some(regex => regex.test(comment.text.trim())));
+        comments = await this.filterByScope(comments);
+
+        const translations = this.commentTranslations.get(document.uri.toString());
+        const untranslated = comments.filter(comment => !translations?.has(this.commentKey(document, comment.range)));
+        if (untranslated.length === 0) {
+            vscode.window.showInformationMessage('没有未翻译的注释 / No untranslated comments');
+            return;
+        }
+
+        const line = editor.selection.active.line;
+        const target = direction > 0
+            ? untranslated.find(comment => comment.range.start.line > line) ?? untranslated[0]
+            : [...untranslated].reverse().find(comment => comment.range.start.line < line) ?? untranslated[untranslated.length - 1];
+
+        const position = target.range.start;
+        editor.selection = new vscode.Selection(position, position);
+        editor.revealRange(target.range, vscode.TextEditorRevealType.InCenterIfOutsideViewport);
+    }
+
+    /**
+     * 提供原文/译文切换 CodeLens，每个注释块一个
+     * Provide original/translation toggle CodeLenses, one per comment block
+     *
+     * @param document 文档 / Document
+     * @returns CodeLens 列表 / CodeLenses
+     */
+    public provideToggleLenses(document: vscode.TextDocument): vscode.CodeLens[] {
+        const uri = document.uri.toString();
+        const translations = this.commentTranslations.get(uri);
+        if (!translations) {
+            return [];
+        }
+
+        const originals = this.originalComments.get(uri);
+        return this.commentBlocks(translations).map(keys => new vscode.CodeLens(translations.get(keys[0])!.range, {
+            title: originals?.has(keys[0]) ? '$(globe) 显示译文 (Show Translation)' : '$(book) 显示原文 (Show Original)',
+            command: 'gopp.toggleCommentTranslation',
+            arguments: [uri, keys[0]]
+        }));
+    }
+
+    /**
+     * 以内嵌提示显示注释译文（display 为 inlay 时），位于注释结束之后；
+     * 多行注释每行一个提示，或按 inlayBlockHints 设置在注释末尾显示一个汇总
+     * Provide the comment translations as inlay hints (when display is inlay), placed after the comment;
+     * multi-line comments get a hint per line, or a summary at the end of the comment as set by inlayBlockHints
+     *
+     * @param document 文档 / Document
+     * @param range 请求的范围 / Requested range
+     * @returns 内嵌提示 / Inlay hints
+     */
+    public async provideTranslationHints(document: vscode.TextDocument, range: vscode.Range): Promise<vscode.InlayHint[]> {
+        const uri = document.uri.toString();
+        const translations = this.commentTranslations.get(uri);
+        if (this.config.display !== 'inlay' || !translations) {
+            return [];
+        }
+
+        const info = IsGoFile(document) ? await this.loadCommentInfo(document) : undefined;
+        const originals = this.originalComments.get(uri);
+        const hints: vscode.InlayHint[] = [];
+        const hint = (position: vscode.Position, label: string, tooltip: string) => {
+            const item = new vscode.InlayHint(position, label);
+            item.paddingLeft = true;
+            item.tooltip = tooltip;
+            hints.push(item);
+        };
+
+        for (const [key, entry] of translations) {
+            if (originals?.has(key) || !entry.range.intersection(range)) {
+                continue;
+            }
+
+            // 最后一行放在注释结束之后，避免覆盖块注释后的代码；其余行放在行尾
+            // The last line goes after the end of the comment so code after a block comment stays readable,
+            // the other lines go to the end of the line
+            const lastLine = entry.range.end.line;
+            const anchor = info?.anchors.get(entry.range.start.line);
+            const positionOf = (line: number) => line === lastLine && anchor?.line === lastLine
+                ? anchor
+                : document.lineAt(line).range.end;
+
+            const originalLines = document.getText(entry.range).split('\n');
+            const translatedLines = entry.translation.split('\n');
+            if (this.config.inlayBlockHints === 'summary') {
+                hint(positionOf(lastLine), translatedLines.map(line => line.trim()).filter(line => line).join(' '), entry.translation);
+                continue;
+            }
+
+            const lineCount = Math.min(originalLines.length, translatedLines.length);
+            for (let i = 0; i < lineCount; i++) {
+                hint(positionOf(entry.range.start.line + i), translatedLines[i], originalLines[i]);
+            }
+        }
+        return hints;
+    }
+
+    /**
+     * 将相邻行的注释翻译分组为注释块
+     * Group comment translations on adjacent lines into comment blocks
+     *
+     * @param translations 文档的注释翻译 / Comment translations of a document
+     * @returns 按行排序的注释键分组 / Groups of comment keys ordered by line
+     */
+    private commentBlocks(translations: Map<string, CommentTranslation>): string[][] {
+        const sorted = Array.from(translations).sort(([, a], [, b]) => a.range.start.line - b.range.start.line);
+        const blocks: string[][] = [];
+        let lastLine = -2;
+        for (const [key, entry] of sorted) {
+            if (entry.range.start.line === lastLine + 1 && blocks.length > 0) {
+                blocks[blocks.length - 1].push(key);
+            } else {
+                blocks.push([key]);
+            }
+            lastLine = entry.range.end.line;
+        }
+        return blocks;
+    }
+
     /**
      * 检查注释是否已经翻译
      * Check if comment is already translated
@@ -876,11 +1492,7 @@ export class TranslationProvider implements vscode.CodeActionProvider {
     private isCommentAlreadyTranslated(range: vscode.Range): boolean {
         if (!this.editor) return false;
 
-        // 创建一个唯一键来标识注释
-        // Create a unique key to identify the comment
-        const document = this.editor.document;
-        const commentText = document.getText(range);
-        const key = `${document.fileName}:${range.start.line}:${range.start.character}:${commentText.substring(0, 100)}`;
+        const key = this.commentKey(this.editor.document, range);
 
         // 检查是否在已翻译缓存中
         // Check if in translated cache
@@ -896,17 +1508,195 @@ export class TranslationProvider implements vscode.CodeActionProvider {
     private markCommentAsTranslated(range: vscode.Range): void {
         if (!this.editor) return;
 
-        // 创建一个唯一键来标识注释
-        // Create a unique key to identify the comment
-        const document = this.editor.document;
-        const commentText = document.getText(range);
-        const key = `${document.fileName}:${range.start.line}:${range.start.character}:${commentText.substring(0, 100)}`;
+        const key = this.commentKey(this.editor.document, range);
 
         // 将注释标记为已翻译
         // Mark comment as translated
         this.translatedComments.set(key, true);
     }
 
+    /**
+     * 创建一个唯一键来标识注释
+     * Create a unique key to identify the comment
+     *
+     * @param document 文档 / Document
+     * @param range 注释范围 / Comment range
+     * @returns 注释键 / Comment key
+     */
+    private commentKey(document: vscode.TextDocument, range: vscode.Range): string {
+        const commentText = document.getText(range);
+        return `${document.fileName}:${range.start.line}:${range.start.character}:${commentText.substring(0, 100)}`;
+    }
+
+    /**
+     * 悬停在声明名称上时显示其文档注释的第一句及译文
+     * Show the first sentence of the doc comment and its translation when hovering a declared name
+     *
+     * @param document 文档 / Document
+     * @param position 悬停位置 / Hover position
+     * @param token 取消令牌 / Cancellation token
+     * @returns 悬停信息 / Hover
+     */
+    public async provideSynopsisHover(document: vscode.TextDocument, position: vscode.Position, token: vscode.CancellationToken): Promise<vscode.Hover | undefined> {
+        const wordRange = document.getWordRangeAtPosition(position);
+        if (!wordRange || !vscode.workspace.getConfiguration(this.configKey).get<boolean>('hoverSynopsis', true)) {
+            return undefined;
+        }
+
+        try {
+            const result = await WasmExecutor.callFunction<string>(
+                this.context,
+                GoWasmFunction.DocSynopsisFunc,
+                document.getText(),
+                position.line + 1
+            );
+            const data = JSON.parse(result) as { name?: string, synopsis?: string, error?: string };
+            if (data.error !== undefined || !data.synopsis || data.name !== document.getText(wordRange)) {
+                return undefined;
+            }
+
+            const translated = await this.hoverTranslate(document, data.synopsis, token);
+            if (token.isCancellationRequested) {
+                return undefined;
+            }
+
+            const markdown = new vscode.MarkdownString();
+            markdown.appendMarkdown(`**${data.name}**: `);
+            markdown.appendText(data.synopsis);
+            if (translated && translated !== data.synopsis) {
+                markdown.appendMarkdown('\n\n');
+                markdown.appendText(translated);
+            }
+            return new vscode.Hover(markdown, wordRange);
+        } catch (error) {
+            logger.error('获取文档摘要出错 / Error getting doc synopsis:', error);
+            return undefined;
+        }
+    }
+
+    /**
+     * 悬停在注释上时显示译文
+     * Show the translation when hovering a comment
+     *
+     * @param document 文档 / Document
+     * @param position 悬停位置 / Hover position
+     * @param token 取消令牌 / Cancellation token
+     * @returns 悬停信息 / Hover
+     */
+    public async provideCommentHover(document: vscode.TextDocument, position: vscode.Position, token: vscode.CancellationToken): Promise<vscode.Hover | undefined> {
+        if (!vscode.workspace.getConfiguration(this.configKey).get<boolean>('hoverComments', true)) {
+            return undefined;
+        }
+        const comment = this.commentAt(document, position);
+        if (!comment || this.config.ignorePatterns.some(pattern => pattern.test(comment.text))) {
+            return undefined;
+        }
+
+        try {
+            const translated = await this.hoverTranslate(document, comment.text, token);
+            if (!translated || translated === comment.text || token.isCancellationRequested) {
+                return undefined;
+            }
+            const markdown = new vscode.MarkdownString();
+            markdown.appendText(translated);
+            return new vscode.Hover(markdown, comment.range);
+        } catch (error) {
+            logger.error('悬停翻译注释出错 / Error translating hovered comment:', error);
+            return undefined;
+        }
+    }
+
+    /**
+     * 翻译悬停的文本：缓存命中时立即返回；否则等待指针停留 hoverDelay 毫秒后才发起请求，
+     * 悬停结束或悬停到其他文本时取消尚未发出的请求，避免掠过的注释消耗翻译额度。
+     * 已发给翻译引擎的请求无法中止，其结果仍会写入缓存
+     * Translate a hovered text: cache hits return immediately; otherwise the request is only sent once
+     * the pointer has rested for hoverDelay milliseconds, and requests not sent yet are cancelled when the
+     * hover ends or another text is hovered, so grazed comments do not use up the translation quota.
+     * Requests already sent to the engine cannot be aborted, their results still go to the cache
+     *
+     * @param document 悬停的文档 / Hovered document
+     * @param text 要翻译的文本 / Text to translate
+     * @param token 悬停的取消令牌 / Cancellation token of the hover
+     * @returns 译文，取消时为 undefined / Translation, undefined when cancelled
+     */
+    private async hoverTranslate(document: vscode.TextDocument, text: string, token: vscode.CancellationToken): Promise<string | undefined> {
+        const { sourceLang, targetLang } = this.detectLanguageDirection(text);
+        const cached = this.TranslationService.cached(text, targetLang);
+        if (cached !== null) {
+            return cached;
+        }
+
+        if (this.hoverRequest?.text !== text) {
+            this.hoverRequest?.cancel.cancel();
+            this.hoverRequest?.cancel.dispose();
+            this.hoverRequest = { text, cancel: new vscode.CancellationTokenSource() };
+        }
+        const superseded = this.hoverRequest.cancel.token;
+        const cancelled = () => token.isCancellationRequested || superseded.isCancellationRequested;
+
+        const delay = vscode.workspace.getConfiguration(this.configKey).get<number>('hoverDelay', 400);
+        await new Promise<void>(resolve => {
+            const timer = setTimeout(done, delay);
+            const listeners = [token.onCancellationRequested(done), superseded.onCancellationRequested(done)];
+            function done() {
+                clearTimeout(timer);
+                listeners.forEach(listener => listener.dispose());
+                resolve();
+            }
+        });
+        if (cancelled()) {
+            return undefined;
+        }
+        const symbols = await this.loadSymbols(document);
+
+        // 排队期间也可能被取消，开始执行时再检查一次
+        // The request may be cancelled while queued, check again when it starts
+        return this.translationQueue.enqueue(async () =>
+            cancelled() ? undefined : this.TranslationService.translate(text, targetLang, sourceLang, symbols)
+        );
+    }
+
+    /**
+     * 查找位置所在的行注释；整行注释会与相邻的整行注释合并为一段
+     * Find the line comment at a position; full-line comments are merged with the adjacent full-line comments
+     *
+     * @param document 文档 / Document
+     * @param position 位置 / Position
+     * @returns 注释文本和范围 / Comment text and range
+     */
+    private commentAt(document: vscode.TextDocument, position: vscode.Position): { text: string, range: vscode.Range } | undefined {
+        const line = document.lineAt(position.line);
+        const comment = this.extractCommentsFromRange(document, line.range)
+            .find(c => c.range.start.line === position.line && c.range.contains(position));
+        if (!comment) {
+            return undefined;
+        }
+
+        const fullLine = (i: number) => i >= 0 && i < document.lineCount && document.lineAt(i).text.trimStart().startsWith('//');
+        if (!fullLine(position.line)) {
+            return comment;
+        }
+
+        let start = position.line;
+        let end = position.line;
+        while (fullLine(start - 1)) {
+            start--;
+        }
+        while (fullLine(end + 1)) {
+            end++;
+        }
+        const lines: string[] = [];
+        for (let i = start; i <= end; i++) {
+            lines.push(document.lineAt(i).text.trimStart().replace(/^\/\/\s?/, ''));
+        }
+        const first = document.lineAt(start);
+        return {
+            text: lines.join('\n').trim(),
+            range: new vscode.Range(start, first.firstNonWhitespaceCharacterIndex, end, document.lineAt(end).text.length)
+        };
+    }
+
     // 存储注释装饰器类型
     // Store comment decoration types
     private commentDecorationTypes: vscode.TextEditorDecorationType[] = [];
@@ -927,6 +1717,35 @@ export class TranslationProvider implements vscode.CodeActionProvider {
             )
         );
 
+        // 注册注释原文/译文切换 CodeLens
+        // Register comment original/translation toggle CodeLens
+        context.subscriptions.push(
+            vscode.languages.registerCodeLensProvider({ language: 'go' }, {
+                onDidChangeCodeLenses: provider.onDidChangeTranslations,
+                provideCodeLenses: document => provider.provideToggleLenses(document)
+            })
+        );
+
+        // 注册以内嵌提示显示的注释译文
+        // Register comment translations shown as inlay hints
+        context.subscriptions.push(
+            vscode.languages.registerInlayHintsProvider({ scheme: 'file' }, {
+                onDidChangeInlayHints: provider.onDidChangeTranslations,
+                provideInlayHints: (document, range) => provider.provideTranslationHints(document, range)
+            })
+        );
+
+        // 注册声明文档摘要悬停与注释翻译悬停
+        // Register doc synopsis hover on declarations and translation hover on comments
+        context.subscriptions.push(
+            vscode.languages.registerHoverProvider({ language: 'go' }, {
+                provideHover: (document, position, token) => provider.provideSynopsisHover(document, position, token)
+            }),
+            vscode.languages.registerHoverProvider({ language: 'go' }, {
+                provideHover: (document, position, token) => provider.provideCommentHover(document, position, token)
+            })
+        );
+
         // 注册翻译命令
         // Register translation command
         context.subscriptions.push(
@@ -1,7 +1,9 @@
-import { TranslationOptions, TranslationResult } from './engines/engine';
+import { TranslationEngine, TranslationOptions, TranslationResult } from './engines/engine';
 import { Logger } from '../../pkg/logger';
-import * as crypto from 'crypto';
 import * as vscode from 'vscode';
+import { TranslationCache } from './cache';
+import { TranslationOverrides } from './override';
+import { maskCodeSpans, restoreCodeSpans } from './mask';
 import {
     ENGINE_TYPES,
     TranslationEngineConfig,
@@ -17,20 +19,25 @@ const logger = Logger.withContext('TranslationService');
  */
 export class TranslationService {
 
-    // 翻译缓存 - 使用Map进行高效存取
-    // Translation cache - use Map for efficient access
-    private translationCache = new Map<string, {
-        result: string,
-        timestamp: number
-    }>();
+    // 持久化翻译缓存
+    // Persistent translation cache
+    private cache: TranslationCache;
 
-    // 缓存过期时间（毫秒） - 默认 31 天
-    // Cache expiration time (ms) - default 31 days
-    private readonly CACHE_EXPIRATION = 31 * 24 * 60 * 60 * 1000;
+    // 手动修正的译文，优先于缓存
+    // Manually corrected translations, checked before the cache
+    private overrides: TranslationOverrides;
 
-    // 最大缓存条目数 - 防止内存溢出
-    // Maximum cache entries - prevent memory overflow
-    private readonly MAX_CACHE_SIZE = 3000;
+    // 默认最大缓存条目数
+    // Default maximum cache entries
+    private readonly DEFAULT_CACHE_SIZE = 3000;
+
+    // 批量翻译时合并文本使用的默认分隔符
+    // Default delimiter used to join texts for batch translation
+    private readonly DEFAULT_SEGMENT_DELIMITER = '\n@@@\n';
+
+    // 单次批量请求的最大字符数
+    // Maximum characters in one batch request
+    private readonly MAX_BATCH_CHARS = 4000;
 
     private configKey = 'gopp.translation';
     private conf: TranslationEngineConfig;
@@ -41,7 +48,8 @@ export class TranslationService {
         [ENGINE_TYPES.MICROSOFT]: 20,
         [ENGINE_TYPES.GOOGLE]: 10,
         [ENGINE_TYPES.VOLCENGINE]: 20,
-        [ENGINE_TYPES.TENCENT]: 50
+        [ENGINE_TYPES.TENCENT]: 50,
+        [ENGINE_TYPES.DEEPL]: 20
     };
 
     // 当前引擎计数器
@@ -50,12 +58,16 @@ export class TranslationService {
 
     constructor(context: vscode.ExtensionContext) {
         const config = vscode.workspace.getConfiguration(this.configKey);
+        this.cache = new TranslationCache(context.globalStorageUri.fsPath, this.cacheSize(config));
+        this.overrides = new TranslationOverrides(context.globalStorageUri.fsPath);
         this.updateConfig(config);
 
         // 订阅配置变更事件
         // Subscribe to configuration change events
         context.subscriptions.push(
-            vscode.workspace.onDidChangeConfiguration(this.handleConfigChange, this)
+            vscode.workspace.onDidChangeConfiguration(this.handleConfigChange, this),
+            { dispose: () => this.cache.save() },
+            { dispose: () => this.overrides.save() }
         );
     }
 
@@ -84,89 +96,21 @@ export class TranslationService {
             volcengineSecretAccessKey: config.volcengineSecretAccessKey,
             tencentSecretId: config.tencentSecretId,
             tencentSecretKey: config.tencentSecretKey,
+            deeplApiKey: config.deeplApiKey,
             engineType: config.engineType,
         };
+        this.cache.resize(this.cacheSize(config));
     }
 
     /**
-     * 生成缓存键
-     * Generate cache key
-     *
-     * @param text 要翻译的文本 / Text to translate
-     * @param targetLang 目标语言 / Target language
-     * @param sourceLang 源语言 / Source language
-     * @param engineType 引擎类型 / Engine type
-     * @returns 缓存键 / Cache key
-     */
-    private generateCacheKey(
-        text: string,
-        targetLang: string,
-        sourceLang: string,
-        engineType: string
-    ): string {
-        return crypto.createHash('md5')
-            .update(`${text.trim()}|${targetLang}|${sourceLang}|${engineType}`)
-            .digest('hex');
-    }
-
-    /**
-     * 从缓存获取翻译结果
-     * Get translation result from cache
+     * 读取缓存大小配置
+     * Read the cache size setting
      *
-     * @param key 缓存键 / Cache key
-     * @returns 缓存的翻译结果，如果未命中缓存则返回null / Cached translation result, or null if cache miss
+     * @param config 配置 / Configuration
+     * @returns 最大缓存条目数 / Maximum cache entries
      */
-    private getFromCache(key: string): string | null {
-        const cached = this.translationCache.get(key);
-
-        // 如果缓存存在且未过期，返回缓存结果
-        // If cache exists and not expired, return cached result
-        if (cached && (Date.now() - cached.timestamp) < this.CACHE_EXPIRATION) {
-            return cached.result;
-        }
-
-        // 如果缓存已过期，删除它
-        // If cache is expired, delete it
-        if (cached) {
-            logger.debug('缓存已过期，删除 / Cache expired, removing');
-            this.translationCache.delete(key);
-        }
-
-        return null;
-    }
-
-    /**
-     * 将翻译结果存入缓存
-     * Store translation result into cache
-     *
-     * @param key 缓存键 / Cache key
-     * @param result 翻译结果 / Translation result
-     */
-    private storeInCache(key: string, result: string): void {
-        // 如果缓存太大，删除最旧的条目
-        // If cache is too large, remove oldest entries
-        if (this.translationCache.size >= this.MAX_CACHE_SIZE) {
-            logger.debug('缓存已满，清理最旧条目 / Cache full, cleaning oldest entries');
-
-            // 获取所有缓存条目并按时间戳排序
-            // Get all cache entries and sort by timestamp
-            const entries = Array.from(this.translationCache.entries())
-                .sort((a, b) => a[1].timestamp - b[1].timestamp);
-
-            // 删除25%的最旧条目
-            // Remove 25% of the oldest entries
-            const entriesToRemove = Math.floor(this.MAX_CACHE_SIZE * 0.25);
-            for (let i = 0; i < entriesToRemove && i < entries.length; i++) {
-                this.translationCache.delete(entries[i][0]);
-            }
-        }
-
-        // 存储新结果
-        // Store new result
-        this.translationCache.set(key, {
-            result,
-            timestamp: Date.now()
-        });
+    private cacheSize(config: vscode.WorkspaceConfiguration): number {
+        return config.get<number>('cacheSize', this.DEFAULT_CACHE_SIZE);
     }
 
     /**
@@ -174,21 +118,7 @@ export class TranslationService {
      * Clear expired cache
      */
     public clearExpiredCache(): void {
-        const now = Date.now();
-        let removedCount = 0;
-
-        // 遍历所有缓存条目，删除过期的
-        // Iterate all cache entries, remove expired ones
-        for (const [key, value] of this.translationCache.entries()) {
-            if ((now - value.timestamp) >= this.CACHE_EXPIRATION) {
-                this.translationCache.delete(key);
-                removedCount++;
-            }
-        }
-
-        if (removedCount > 0) {
-            logger.debug(`已清除 ${removedCount} 条过期缓存 / Cleared ${removedCount} expired cache entries`);
-        }
+        this.cache.clearExpired();
     }
 
     /**
@@ -279,6 +209,12 @@ export class TranslationService {
                 weight: this.ENGINE_WEIGHTS[ENGINE_TYPES.VOLCENGINE]
             });
         }
+        if (this.conf.deeplApiKey) {
+            configuredEngines.push({
+                type: ENGINE_TYPES.DEEPL,
+                weight: this.ENGINE_WEIGHTS[ENGINE_TYPES.DEEPL]
+            });
+        }
 
         // 如果没有配置任何引擎，返回自动模式
         // If no engines configured, return auto mode
@@ -309,6 +245,43 @@ export class TranslationService {
         return configuredEngines[0].type;
     }
 
+    /**
+     * 只从缓存读取译文，不发起翻译请求
+     * Read a translation from the cache only, without sending a translation request
+     *
+     * @param text 要翻译的文本 / Text to translate
+     * @param targetLang 目标语言代码 / Target language code
+     * @returns 缓存的译文，未缓存时为 null / Cached translation, null when not cached
+     */
+    public cached(text: string, targetLang = 'zh-CN'): string | null {
+        const key = TranslationCache.key(this.preprocessMultilineText(text), targetLang);
+        return this.overrides.get(key) ?? this.cache.get(key);
+    }
+
+    /**
+     * 保存手动修正的译文，之后翻译同一原文时不再请求翻译引擎
+     * Store a manually corrected translation, translating the same text no longer asks the engines
+     *
+     * @param text 原文 / Source text
+     * @param translation 修正的译文 / Corrected translation
+     * @param file 修正时所在的文件 / File the correction is made in
+     * @param targetLang 目标语言代码 / Target language code
+     */
+    public setOverride(text: string, translation: string, file: string, targetLang = 'zh-CN'): void {
+        this.overrides.set(TranslationCache.key(this.preprocessMultilineText(text), targetLang), translation, file);
+    }
+
+    /**
+     * 清除在某个文件中手动修正的译文
+     * Clear the translations corrected manually in a file
+     *
+     * @param file 文件路径 / File path
+     * @returns 清除的修正数 / Number of corrections removed
+     */
+    public clearOverrides(file: string): number {
+        return this.overrides.clearFile(file);
+    }
+
     /**
      * 根据配置选择的引擎翻译文本
      * Translate text using the engine specified in configuration
@@ -318,12 +291,14 @@ export class TranslationService {
      * @param sourceLang 源语言代码 / Source language code
      * @param engineType 翻译引擎类型 / Translation engine type
      * @param config 翻译配置 / Translation configuration
+     * @param symbols 已声明的符号名称，引用它们的代码片段不翻译 / Names of declared symbols, code spans referring to them are not translated
      * @returns 翻译后的文本 / Translated text
      */
     public async translate(
         text: string,
         targetLang = 'zh-CN',
         sourceLang = 'en',
+        symbols?: ReadonlySet<string>,
     ): Promise<string> {
         // 预处理文本 - 处理多行文本
         // Preprocess text - handle multi-line text
@@ -332,13 +307,13 @@ export class TranslationService {
         // 智能选择翻译引擎 / Intelligently select translation engine
         const actualEngineType = this.selectTranslationEngine(this.conf.engineType);
 
-        // 生成缓存键并尝试从缓存获取结果
-        // Generate cache key and try to get result from cache
-        const cacheKey = this.generateCacheKey(text, targetLang, sourceLang, actualEngineType);
-        const cachedResult = this.getFromCache(cacheKey);
+        // 生成缓存键并尝试从缓存获取结果，缓存与引擎无关
+        // Generate cache key and try to get result from cache, the cache does not depend on the engine
+        const cacheKey = TranslationCache.key(text, targetLang);
+        const cachedResult = this.overrides.get(cacheKey) ?? this.cache.get(cacheKey);
 
-        // 如果缓存命中，直接返回缓存结果
-        // If cache hit, return cached result directly
+        // 如果手动修正或缓存命中，直接返回
+        // If there is a manual correction or a cache hit, return it directly
         if (cachedResult !== null) {
             return cachedResult;
         }
@@ -355,15 +330,190 @@ export class TranslationService {
             timeout: 10000 // 10秒超时
         };
 
-        const result = await engine.translate(text, options);
+        const result = await this.translateMasked(engine, text, options, symbols);
 
         // 存入缓存
         // Store in cache
-        this.storeInCache(cacheKey, result.text);
+        if (result.text) {
+            this.cache.set(cacheKey, result.text);
+        }
 
         if (result.text && this.conf.engineType === ENGINE_TYPES.AUTO) {
             return engine.icon + ' ' + result.text;
         }
         return result.text;
     }
+
+    /**
+     * 批量翻译多段文本，未命中缓存的文本合并为尽量少的请求
+     * Translate several texts, sending the cache misses in as few requests as possible
+     *
+     * 返回结果与输入一一对应；翻译失败的段落返回空字符串，由调用方显示原文。
+     * Results match the input by index; segments that fail come back as empty
+     * strings so that the caller can show the original text.
+     *
+     * @param texts 要翻译的文本 / Texts to translate
+     * @param targetLang 目标语言代码 / Target language code
+     * @param sourceLang 源语言代码 / Source language code
+     * @param symbols 已声明的符号名称，引用它们的代码片段不翻译 / Names of declared symbols, code spans referring to them are not translated
+     * @returns 翻译后的文本 / Translated texts
+     */
+    public async translateBatch(
+        texts: string[],
+        targetLang = 'zh-CN',
+        sourceLang = 'en',
+        symbols?: ReadonlySet<string>,
+    ): Promise<string[]> {
+        const results: string[] = texts.map(() => '');
+        const pending: { index: number, text: string, key: string }[] = [];
+
+        // 先从手动修正和缓存获取结果
+        // Resolve manual corrections and cache hits first
+        texts.forEach((raw, index) => {
+            const text = this.preprocessMultilineText(raw);
+            if (!text) {
+                return;
+            }
+            const key = TranslationCache.key(text, targetLang);
+            const cached = this.overrides.get(key) ?? this.cache.get(key);
+            if (cached !== null) {
+                results[index] = cached;
+            } else {
+                pending.push({ index, text, key });
+            }
+        });
+
+        if (pending.length === 0) {
+            return results;
+        }
+
+        // 智能选择翻译引擎 / Intelligently select translation engine
+        const actualEngineType = this.selectTranslationEngine(this.conf.engineType);
+        const engine = createTranslationEngine(actualEngineType, this.conf);
+        const options: TranslationOptions = {
+            from: sourceLang,
+            to: targetLang,
+            cache: true,
+            timeout: 10000 // 10秒超时
+        };
+
+        for (const chunk of this.chunkSegments(pending)) {
+            const masked = chunk.map(p => maskCodeSpans(p.text, symbols));
+            const translated = await this.translateSegments(engine, masked.map(m => m.text), options);
+
+            // 占位符丢失的段落改用原文重新翻译
+            // Segments that lost a placeholder are translated again from the original text
+            const restored = translated.map((text, i) => text && restoreCodeSpans(text, masked[i].spans));
+            const lost = chunk.filter((_, i) => translated[i] && restored[i] === undefined);
+            if (lost.length > 0) {
+                logger.warn(`${lost.length} 段译文丢失了代码占位符，改用原文翻译 / segments lost code placeholders, translating the original text`);
+                const retried = await this.translateSegments(engine, lost.map(p => p.text), options);
+                lost.forEach((p, i) => restored[chunk.indexOf(p)] = retried[i]);
+            }
+
+            chunk.forEach((p, i) => {
+                const text = restored[i]?.trim();
+
+                // 单段失败不影响其他段落
+                // A failed segment does not affect the others
+                if (!text || text === p.text) {
+                    return;
+                }
+
+                this.cache.set(p.key, text);
+                results[p.index] = this.conf.engineType === ENGINE_TYPES.AUTO ? engine.icon + ' ' + text : text;
+            });
+        }
+        return results;
+    }
+
+    /**
+     * 遮蔽代码片段后翻译文本，并在译文中还原它们；占位符丢失时改用原文翻译
+     * Translate a text with its code spans masked and restore them in the translation;
+     * the original text is translated instead when a placeholder is lost
+     */
+    private async translateMasked(
+        engine: TranslationEngine,
+        text: string,
+        options: TranslationOptions,
+        symbols?: ReadonlySet<string>
+    ): Promise<TranslationResult> {
+        const masked = maskCodeSpans(text, symbols);
+        if (masked.spans.length === 0) {
+            return engine.translate(text, options);
+        }
+
+        const result = await engine.translate(masked.text, options);
+        const restored = result.text ? restoreCodeSpans(result.text, masked.spans) : result.text;
+        if (restored !== undefined) {
+            return { ...result, text: restored };
+        }
+        logger.warn('译文丢失了代码占位符，改用原文翻译 / Translation lost code placeholders, translating the original text');
+        return engine.translate(text, options);
+    }
+
+    /**
+     * 将待翻译文本按最大字符数分组
+     * Group pending texts by the maximum request size
+     */
+    private chunkSegments<T extends { text: string }>(segments: T[]): T[][] {
+        const chunks: T[][] = [];
+        let current: T[] = [];
+        let size = 0;
+
+        for (const segment of segments) {
+            if (current.length > 0 && size + segment.text.length > this.MAX_BATCH_CHARS) {
+                chunks.push(current);
+                current = [];
+                size = 0;
+            }
+            current.push(segment);
+            size += segment.text.length;
+        }
+        if (current.length > 0) {
+            chunks.push(current);
+        }
+        return chunks;
+    }
+
+    /**
+     * 在一次请求中翻译多段文本
+     * Translate several texts in one request
+     *
+     * 引擎原生支持批量时直接使用，否则用分隔符合并文本后拆分结果；
+     * 拆分后段数不一致时逐段翻译。
+     * Uses the engine's native batch support when available, otherwise joins
+     * the texts with a delimiter and splits the result, translating segment by
+     * segment if the segment count does not match.
+     */
+    private async translateSegments(
+        engine: TranslationEngine,
+        texts: string[],
+        options: TranslationOptions
+    ): Promise<string[]> {
+        try {
+            if (engine.translateBatch) {
+                return (await engine.translateBatch(texts, options)).map(r => r.text);
+            }
+
+            if (texts.length > 1) {
+                const delimiter = engine.segmentDelimiter || this.DEFAULT_SEGMENT_DELIMITER;
+                const result = await engine.translate(texts.join(delimiter), options);
+                const segments = result.text.split(delimiter.trim()).map(segment => segment.trim());
+                if (segments.length === texts.length) {
+                    return segments;
+                }
+                logger.warn(`批量翻译段数不一致 ${segments.length}/${texts.length}，逐段翻译 / Segment count mismatch, translating one by one`);
+            }
+
+            const results: string[] = [];
+            for (const text of texts) {
+                results.push((await engine.translate(text, options)).text);
+            }
+            return results;
+        } catch (error) {
+            logger.error('批量翻译失败 / Batch translation failed:', error);
+            return texts.map(() => '');
+        }
+    }
 }
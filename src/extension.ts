@@ -1,5 +1,12 @@
 import * as vscode from 'vscode';
 import { DisposeCodeLensProvider } from './provider/codelens';
+import { DisposeAssertionProvider } from './provider/assertion';
+import { DisposeNilInterfaceProvider } from './provider/nilcheck';
+import { DisposeHoverProvider } from './provider/hover';
+import { DisposeGoModProvider } from './provider/gomod';
+import { DisposeBuildConstraintProvider } from './provider/constraint';
+import { DisposeTestPlacementProvider } from './provider/testplacement';
+import { DisposeShadowProvider } from './provider/shadow';
 import { goLibraryModule } from './core/library/integration';
 import { Logger } from './pkg/logger';  // 新增日志模块导入
 import { Home } from './core/home/home';  // 导入工作空间导航器模块
@@ -66,6 +73,13 @@ export function activate(context: vscode.ExtensionContext) {
 
         context.subscriptions.push(
             DisposeCodeLensProvider(context),
+            ...DisposeAssertionProvider(context), // 接口断言诊断
+            ...DisposeNilInterfaceProvider(context), // nil 接口陷阱诊断
+            DisposeHoverProvider(context), // go.mod 依赖悬停
+            ...DisposeGoModProvider(context), // go.mod 诊断
+            ...DisposeBuildConstraintProvider(context), // 构建约束提示
+            ...DisposeTestPlacementProvider(context), // 测试函数位置诊断
+            ...DisposeShadowProvider(context), // 方法遮蔽提示
             ...DisposeCommands(context) // 注册命令
         );
 
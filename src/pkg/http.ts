@@ -2,6 +2,17 @@ import * as https from 'https'; // 添加原生https模块
 import * as http from 'http'; // 添加原生http模块
 import { URL } from 'url'; // 用于解析URL
 
+/**
+ * HTTP 错误，携带响应状态码
+ * HTTP error carrying the response status code
+ */
+export class HttpError extends Error {
+    constructor(message: string, public readonly statusCode?: number) {
+        super(message);
+        this.name = 'HttpError';
+    }
+}
+
 export class httpClient {
 
     /**
@@ -67,7 +78,7 @@ export class httpClient {
                     } else {
                         // 请求失败
                         // Request failed
-                        reject(new Error(`请求失败，状态码: ${res.statusCode} / Request failed with status code: ${res.statusCode}`));
+                        reject(new HttpError(`请求失败，状态码: ${res.statusCode} / Request failed with status code: ${res.statusCode}`, res.statusCode));
                     }
                 });
             });
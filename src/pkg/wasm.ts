@@ -15,6 +15,242 @@ export enum GoWasmFunction {
     // 解析 go.mod 文件为 JSON
     ParseModFunc = 'ParseModFunc',
 
+    // Parse go.work file to JSON, or only its use directories with the "use" mode
+    // 解析 go.work 文件为 JSON，"use" 模式下只返回 use 目录
+    ParseWorkFunc = 'ParseWorkFunc',
+
+    // Find workspace modules depending on each other through requires and replaces
+    // 查找通过 require 与 replace 相互依赖的工作区模块
+    FindWorkspaceCyclesFunc = 'FindWorkspaceCyclesFunc',
+
+    // Split a go.mod into workspace member modules and a go.work
+    // 将 go.mod 拆分为工作区成员模块和 go.work
+    SplitWorkspaceFunc = 'SplitWorkspaceFunc',
+
+    // Parse vendor/modules.txt to JSON
+    // 解析 vendor/modules.txt 为 JSON
+    ParseVendorModulesFunc = 'ParseVendorModulesFunc',
+
+    // Run all go.mod checks and return the issues with their severity
+    // 运行所有 go.mod 检查并返回带严重程度的问题
+    LintModFunc = 'LintModFunc',
+
+    // Parse several go.mod files in one call
+    // 一次调用解析多个 go.mod 文件
+    ParseModBatchFunc = 'ParseModBatchFunc',
+
+    // Format go.mod content
+    // 格式化 go.mod 内容
+    FormatModFunc = 'FormatModFunc',
+
+    // Add or update a require directive in go.mod
+    // 在 go.mod 中添加或更新 require 指令
+    AddRequireFunc = 'AddRequireFunc',
+
+    // Remove a require directive from go.mod
+    // 从 go.mod 中删除 require 指令
+    DropRequireFunc = 'DropRequireFunc',
+
+    // Add an exclude directive, warning when the required version is excluded
+    // 添加 exclude 指令，排除所需版本时发出警告
+    AddExcludeFunc = 'AddExcludeFunc',
+
+    // Remove an exclude directive
+    // 删除 exclude 指令
+    DropExcludeFunc = 'DropExcludeFunc',
+
+    // Drop the // indirect mark of requires
+    // 去掉 require 的 // indirect 标记
+    PromoteIndirectFunc = 'PromoteIndirectFunc',
+
+    // Mark requires as // indirect
+    // 为 require 添加 // indirect 标记
+    DemoteDirectFunc = 'DemoteDirectFunc',
+
+    // Rename the module path of go.mod and the replaces referring to it
+    // 重命名 go.mod 的模块路径以及引用它的 replace
+    RenameModuleFunc = 'RenameModuleFunc',
+
+    // Resolve the import path of a package directory from the module path and root
+    // 根据模块路径与模块根目录解析包目录的导入路径
+    ResolveImportPathFunc = 'ResolveImportPathFunc',
+
+    // Sort the require blocks of go.mod
+    // 对 go.mod 的 require 块排序
+    TidyFormatFunc = 'TidyFormatFunc',
+
+    // Rewrite local replace paths to forward slashes
+    // 将本地 replace 路径改写为正斜杠
+    NormalizeReplacePathsFunc = 'NormalizeReplacePathsFunc',
+
+    // Render an overview of the module dependencies as markdown
+    // 将模块依赖概览渲染为 markdown
+    RenderModMarkdownFunc = 'RenderModMarkdownFunc',
+
+    // Set the go directive version in go.mod
+    // 设置 go.mod 中 go 指令的版本
+    SetGoVersionFunc = 'SetGoVersionFunc',
+
+    // Check whether a Go version satisfies the go directive
+    // 检查 Go 版本是否满足 go 指令
+    CheckGoVersionFunc = 'CheckGoVersionFunc',
+
+    // Report the minimum Go version implied by the go.mod directives
+    // 报告 go.mod 指令所要求的最低 Go 版本
+    MinGoVersionFunc = 'MinGoVersionFunc',
+
+    // Compare two module versions
+    // 比较两个模块版本
+    CompareVersionsFunc = 'CompareVersionsFunc',
+
+    // Remove the versions excluded by a go.mod from upgrade candidates
+    // 从升级候选版本中移除 go.mod 排除的版本
+    FilterExcludedVersionsFunc = 'FilterExcludedVersionsFunc',
+
+    // Attach the licenses found in the module cache to the requires of a go.mod
+    // 将模块缓存中找到的许可证附加到 go.mod 的依赖上
+    JoinLicensesFunc = 'JoinLicensesFunc',
+
+    // Sort the requires of a go.mod by path or by how outdated they are
+    // 按路径或落后程度排序 go.mod 的依赖
+    SortModsFunc = 'SortModsFunc',
+
+    // Compare two go.mod files
+    // 比较两个 go.mod 文件
+    DiffModFunc = 'DiffModFunc',
+
+    // Merge the requirements of two go.mod files
+    // 合并两个 go.mod 文件的依赖
+    MergeModFunc = 'MergeModFunc',
+
+    // Check go.sum against the requirements of go.mod
+    // 根据 go.mod 的依赖检查 go.sum
+    CheckSumFunc = 'CheckSumFunc',
+
+    // Report indirect requires imported directly by the project
+    // 报告被项目直接导入的间接依赖
+    CheckIndirectImportsFunc = 'CheckIndirectImportsFunc',
+
+    // Report direct requires that no import uses
+    // 报告没有被任何导入使用的直接依赖
+    FindUnusedRequiresFunc = 'FindUnusedRequiresFunc',
+
+    // Label requires as imported from tests only, the build only or both
+    // 将依赖标记为只被测试导入、只被构建导入或两者都有
+    ClassifyRequireUsageFunc = 'ClassifyRequireUsageFunc',
+
+    // Resolve the dependency tree of a module from pre-fetched go.mod files
+    // 根据预先获取的 go.mod 文件解析模块的依赖树
+    DependencyTreeFunc = 'DependencyTreeFunc',
+
+    // Explain why the main module needs a module from pre-fetched go.mod files
+    // 根据预先获取的 go.mod 文件解释主模块为什么需要某个模块
+    ExplainRequireFunc = 'ExplainRequireFunc',
+
+    // Find concrete types implementing each interface
+    // 查找实现每个接口的具体类型
+    FindImplementationsFunc = 'FindImplementationsFunc',
+
+    // Incrementally update the implementation index kept in WASM
+    // 增量更新保存在 WASM 中的实现索引
+    UpdateImplementationsFunc = 'UpdateImplementationsFunc',
+
+    // Report which interfaces each concrete type of a package satisfies
+    // 报告包中每个具体类型满足哪些接口
+    ImplementationMatrixFunc = 'ImplementationMatrixFunc',
+
+    // Report exported interfaces that no concrete type implements
+    // 报告没有任何具体类型实现的导出接口
+    UnimplementedInterfacesFunc = 'UnimplementedInterfacesFunc',
+
+    // Check interface assertions and generate stubs for missing methods
+    // 检查接口断言并为缺少的方法生成桩代码
+    CheckAssertionsFunc = 'CheckAssertionsFunc',
+
+    // Find methods shadowing a method promoted from an embedded field
+    // 查找遮蔽了嵌入字段提升方法的方法
+    FindShadowedMethodsFunc = 'FindShadowedMethodsFunc',
+
+    // Find nil pointers of concrete types returned as interfaces
+    // 查找以接口形式返回的具体类型 nil 指针
+    CheckNilInterfacesFunc = 'CheckNilInterfacesFunc',
+
+    // Generate stubs for the interface methods a type does not implement
+    // 为类型未实现的接口方法生成桩代码
+    GenerateStubsFunc = 'GenerateStubsFunc',
+
+    // Generate a mock of an interface with one func field per method
+    // 为接口生成每个方法对应一个函数字段的 mock
+    GenerateMockFunc = 'GenerateMockFunc',
+
+    // Extract an interface from the exported methods of a concrete type
+    // 根据具体类型的导出方法提取接口
+    ExtractInterfaceFunc = 'ExtractInterfaceFunc',
+
+    // Expand the method set of an interface, embedded interfaces included
+    // 展开接口的方法集，包括嵌入的接口
+    InterfaceMethodSetFunc = 'InterfaceMethodSetFunc',
+
+    // Tell whether either of two interfaces can be used where the other is expected
+    // 判断两个接口中的任一个能否用在需要另一个的位置
+    CompareInterfacesFunc = 'CompareInterfacesFunc',
+
+    // Find the uses of an interface method through interface-typed values
+    // 查找通过接口类型的值对接口方法的使用
+    FindInterfaceCallsFunc = 'FindInterfaceCallsFunc',
+
+    // Diff the method sets of interfaces between two versions
+    // 比较两个版本中接口的方法集
+    DiffInterfacesFunc = 'DiffInterfacesFunc',
+
+    // Find the test, benchmark and example functions of a test file
+    // 查找测试文件中的测试、基准测试和示例函数
+    FindTestsFunc = 'FindTestsFunc',
+
+    // Report tests outside _test.go files and malformed test functions
+    // 报告 _test.go 文件之外的测试以及格式不正确的测试函数
+    CheckTestPlacementFunc = 'CheckTestPlacementFunc',
+
+    // Add missing tags to the fields of a struct
+    // 为结构体字段补充缺少的标签
+    GenerateStructTagsFunc = 'GenerateStructTagsFunc',
+
+    // Align the names, types, tags and trailing comments of struct fields the way gofmt does
+    // 与 gofmt 相同地对齐字段的名称、类型、标签与行尾注释
+    AlignStructFieldsFunc = 'AlignStructFieldsFunc',
+
+    // Parse the build constraints of a Go file
+    // 解析 Go 文件的构建约束
+    ParseBuildConstraintsFunc = 'ParseBuildConstraintsFunc',
+
+    // Rewrite the build constraints of a Go file to //go:build or // +build only
+    // 将 Go 文件的构建约束改写为只使用 //go:build 或 // +build
+    ConvertBuildConstraintsFunc = 'ConvertBuildConstraintsFunc',
+
+    // Report which files of a package are compiled for a GOOS/GOARCH pair
+    // 报告包中哪些文件会针对指定的 GOOS/GOARCH 编译
+    PackageBuildSetFunc = 'PackageBuildSetFunc',
+
+    // Describe the top-level declarations of a Go file
+    // 描述 Go 文件的顶层声明
+    FileOutlineFunc = 'FileOutlineFunc',
+
+    // Read the package clause name of a Go file
+    // 读取 Go 文件 package 子句的名称
+    PackageNameFunc = 'PackageNameFunc',
+
+    // Classify the comments of a Go file as doc, trailing, inline or other
+    // 将 Go 文件的注释分类为文档、行尾、函数内或其他注释
+    ClassifyCommentsFunc = 'ClassifyCommentsFunc',
+
+    // Find TODO, FIXME and other marked comment lines of a Go file
+    // 查找 Go 文件中 TODO、FIXME 等带标记的注释行
+    ScanTodosFunc = 'ScanTodosFunc',
+
+    // Return the first sentence of the doc comment of a declaration
+    // 返回声明的文档注释的第一句
+    DocSynopsisFunc = 'DocSynopsisFunc',
+
     // Parse AST
     // 解析 AST
     ParseAst = 'ParseAst',
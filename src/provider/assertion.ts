@@ -0,0 +1,193 @@
+import * as vscode from 'vscode';
+import { IsGoFile } from '../pkg/cond';
+import { debounce } from '../pkg/util';
+import { Logger } from '../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+import { readWorkspaceGoFiles } from '../core/navigator/implementation';
+import { GoStubs } from '../core/codegenerate/implement';
+
+const logger = Logger.withContext('AssertionProvider');
+
+/**
+ * 不成立的接口断言（由 WASM 返回）
+ * Interface assertion that does not hold (returned by WASM)
+ * 例如 var _ Greeter = (*EnglishGreeter)(nil)
+ */
+interface MissingMethods {
+    path: string;             // 断言所在文件
+    start: { line: number; column: number };
+    end: { line: number; column: number };
+    interface: string;        // 接口名称
+    type: string;             // 断言的类型
+    missing: string[];        // 缺少的方法
+    mismatched: string[];     // 签名或接收者不一致的方法
+    stubs: GoStubs;           // 缺少方法的桩代码
+    incomplete: string;       // 方法集未知的原因，例如嵌入了工作空间之外的接口
+}
+
+/**
+ * 接口断言诊断提供程序
+ * Interface assertion diagnostics provider
+ * 检查 var _ I = (*T)(nil) 形式的断言并提供生成缺少方法的快速修复
+ * Checks var _ I = (*T)(nil) assertions and offers a quick-fix generating the missing methods
+ */
+class AssertionProvider implements vscode.CodeActionProvider {
+    public static readonly diagnosticCode = 'missing-methods';
+
+    private diagnostics = vscode.languages.createDiagnosticCollection('gopp.implements');
+
+    // 每个文件的断言结果，用于快速修复
+    // Assertion results per file, used by the quick-fix
+    private results: Map<string, MissingMethods[]> = new Map();
+
+    constructor(private context: vscode.ExtensionContext) {}
+
+    /**
+     * 注册事件监听
+     * Register event listeners
+     */
+    public register(): vscode.Disposable[] {
+        const refresh = debounce(() => this.refresh(), 1000);
+        const onDocument = (doc: vscode.TextDocument) => {
+            if (IsGoFile(doc)) {
+                refresh();
+            }
+        };
+
+        refresh();
+        return [
+            this.diagnostics,
+            vscode.workspace.onDidOpenTextDocument(onDocument),
+            vscode.workspace.onDidSaveTextDocument(onDocument),
+            vscode.workspace.onDidChangeTextDocument(e => onDocument(e.document)),
+            vscode.languages.registerCodeActionsProvider({ language: 'go', scheme: 'file' }, this, {
+                providedCodeActionKinds: [vscode.CodeActionKind.QuickFix]
+            })
+        ];
+    }
+
+    /**
+     * 重新检查工作空间中的接口断言
+     * Re-check the interface assertions in the workspace
+     */
+    private async refresh(): Promise<void> {
+        try {
+            const files = await readWorkspaceGoFiles();
+            const result = await WasmExecutor.callFunction<string>(
+                this.context,
+                GoWasmFunction.CheckAssertionsFunc,
+                JSON.stringify(files)
+            );
+
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                logger.error(`检查接口断言失败: ${data.error}`);
+                return;
+            }
+
+            this.results.clear();
+            for (const item of data as MissingMethods[]) {
+                const items = this.results.get(item.path) || [];
+                items.push(item);
+                this.results.set(item.path, items);
+            }
+
+            this.diagnostics.clear();
+            for (const [filePath, items] of this.results) {
+                this.diagnostics.set(vscode.Uri.file(filePath), items.map(item => this.toDiagnostic(item)));
+            }
+        } catch (error) {
+            logger.error('检查接口断言时发生错误', error);
+        }
+    }
+
+    /**
+     * 将断言结果转换为诊断
+     * Convert an assertion result to a diagnostic
+     */
+    private toDiagnostic(item: MissingMethods): vscode.Diagnostic {
+        // 方法集不完整时无法判断断言是否成立
+        // The assertion cannot be decided when the method set is incomplete
+        if (item.incomplete) {
+            const diagnostic = new vscode.Diagnostic(
+                this.toRange(item),
+                `cannot check ${item.type} against ${item.interface}: ${item.incomplete}`,
+                vscode.DiagnosticSeverity.Warning
+            );
+            diagnostic.source = 'gopp';
+            return diagnostic;
+        }
+
+        const details: string[] = [];
+        if (item.missing.length > 0) {
+            details.push(`missing ${item.missing.join(', ')}`);
+        }
+        if (item.mismatched.length > 0) {
+            details.push(`wrong signature for ${item.mismatched.join(', ')}`);
+        }
+
+        const diagnostic = new vscode.Diagnostic(
+            this.toRange(item),
+            `${item.type} does not implement ${item.interface}: ${details.join('; ')}`,
+            vscode.DiagnosticSeverity.Error
+        );
+        diagnostic.source = 'gopp';
+        diagnostic.code = AssertionProvider.diagnosticCode;
+        return diagnostic;
+    }
+
+    /**
+     * 断言值的范围
+     * Range of the asserted value
+     */
+    private toRange(item: MissingMethods): vscode.Range {
+        return new vscode.Range(
+            item.start.line - 1, item.start.column - 1,
+            item.end.line - 1, item.end.column - 1
+        );
+    }
+
+    /**
+     * 提供 "Generate missing methods" 快速修复
+     * Provide the "Generate missing methods" quick-fix
+     */
+    public provideCodeActions(
+        document: vscode.TextDocument,
+        range: vscode.Range | vscode.Selection,
+        context: vscode.CodeActionContext
+    ): vscode.CodeAction[] {
+        const items = this.results.get(document.uri.fsPath) || [];
+        const actions: vscode.CodeAction[] = [];
+
+        for (const diagnostic of context.diagnostics) {
+            if (diagnostic.code !== AssertionProvider.diagnosticCode) {
+                continue;
+            }
+            const item = items.find(i => this.toRange(i).isEqual(diagnostic.range));
+            if (!item || item.missing.length === 0) {
+                continue;
+            }
+
+            const action = new vscode.CodeAction('Generate missing methods', vscode.CodeActionKind.QuickFix);
+            action.diagnostics = [diagnostic];
+            action.isPreferred = true;
+            action.command = {
+                title: 'Generate missing methods',
+                command: 'gopp.applyStubs',
+                arguments: [item.stubs]
+            };
+            actions.push(action);
+        }
+        return actions;
+    }
+}
+
+/**
+ * 注册接口断言诊断和快速修复
+ * Register interface assertion diagnostics and quick-fix
+ * @param context 扩展上下文 (extension context)
+ * @returns 可处置的对象 (disposable objects)
+ */
+export function DisposeAssertionProvider(context: vscode.ExtensionContext): vscode.Disposable[] {
+    return new AssertionProvider(context).register();
+}
@@ -1,6 +1,7 @@
 import * as vscode from 'vscode';
-import { IsGoFile } from '../pkg/cond';
-import { G, I, R, Run, Debug, Args, IToType } from '../codelens';
+import { IsGoFile, IsTestFile } from '../pkg/cond';
+import { G, I, R, Run, Debug, Args, Tests, IToType, Implementations, Implements, Implement, Mock } from '../codelens';
+import { ImplementationIndex } from '../core/navigator/implementation';
 import { cleanupDebugBinaries } from '../core/run/debug_binary';
 import { GoFileParser } from '../pkg/parser';
 import { Logger } from '../pkg/logger';
@@ -46,12 +47,32 @@ class GoCodeLensProvider implements vscode.CodeLensProvider {
         watcher.onDidCreate((uri) => this.handleFileChange(uri));
         watcher.onDidDelete((uri) => this.handleFileChange(uri));
 
+        // 索引范围变化后接口实现索引失效
+        // The implementation index is stale once the indexed files change
+        const gitignoreWatcher = vscode.workspace.createFileSystemWatcher('**/.gitignore');
+        gitignoreWatcher.onDidChange(() => this.handleFileChange());
+        gitignoreWatcher.onDidCreate(() => this.handleFileChange());
+        gitignoreWatcher.onDidDelete(() => this.handleFileChange());
+        vscode.workspace.onDidChangeConfiguration((e) => {
+            if (e.affectsConfiguration('gopp.index')) {
+                this.handleFileChange();
+            }
+        });
+
+        // 后台开始初始扫描，首次显示 CodeLens 时无需等待
+        // Start the initial scan in the background so the first CodeLenses need not wait for it
+        ImplementationIndex.interfaces(context);
+
         vscode.workspace.onDidChangeTextDocument((e) => {
             if (IsGoFile(e.document)) {
                 // 标记为编辑中状态
                 // Mark as editing state
                 this.isEditing = true;
 
+                // 索引是增量更新的，编辑后只会重新分析受影响的包
+                // The index is updated incrementally, an edit only re-analyzes the affected packages
+                ImplementationIndex.invalidate();
+
                 // 清除之前的编辑定时器
                 // Clear previous editing timer
                 if (this.editingTimer) {
@@ -90,7 +111,7 @@ class GoCodeLensProvider implements vscode.CodeLensProvider {
             this._onDidChangeCodeLenses.fire();
         });
 
-        context.subscriptions.push(watcher);
+        context.subscriptions.push(watcher, gitignoreWatcher);
 
         // 定期清理缓存（可选）
         // Periodically clear cache (optional)
@@ -116,6 +137,10 @@ class GoCodeLensProvider implements vscode.CodeLensProvider {
     // 添加文件监听处理函数
     // Add file watcher handler
     private handleFileChange(uri?: vscode.Uri) {
+        // 文件变化后接口实现索引失效
+        // Files changed, the implementation index is stale
+        ImplementationIndex.invalidate();
+
         // 如果当前正在编辑，不立即更新
         // If currently editing, don't update immediately
         if (this.isEditing) {
@@ -204,6 +229,12 @@ class GoCodeLensProvider implements vscode.CodeLensProvider {
                 codeLenses.push(Args(range, document.uri));
             }
 
+            // 处理测试函数
+            // Process test functions
+            if (IsTestFile(document)) {
+                await Tests(this.context, document, codeLenses);
+            }
+
             // 处理其他Go语言元素
             // Process other Go language elements
 
@@ -230,11 +261,13 @@ class GoCodeLensProvider implements vscode.CodeLensProvider {
             };
 
             // 处理 interface
-            // 显示 r、i
+            // 显示 r、i、mock
             parser.onInterfaceFunc = async (i, interfaceName) => {
                 const range = new vscode.Range(i, 0, i, lines[i].length);
+                await Implementations(this.context, document, interfaceName, i, range, codeLenses); // 实现数量
                 await I(document, interfaceName, IToType.ToStruct, i, range, codeLenses); // 接口到结构体
                 await R(document, interfaceName, i, range, codeLenses);
+                Mock(document, interfaceName, range, codeLenses); // 生成 mock
             };
 
             // 处理 interface 包含的方法
@@ -252,6 +285,7 @@ class GoCodeLensProvider implements vscode.CodeLensProvider {
 
                 const structFields = parser.getStructFields(i); // 解析结构体字段
                 G(document, i, range, codeLenses, structName, structFields); // 生成测试用例
+                await Implement(this.context, document, structName, i, range, codeLenses); // 补全接口方法
                 await I(document, structName, IToType.ToInterface, i, range, codeLenses); // 结构体到接口
                 await R(document, structName, i, range, codeLenses);
                 // 解析结构体字段
@@ -262,6 +296,7 @@ class GoCodeLensProvider implements vscode.CodeLensProvider {
             parser.onStructMethodFunc = async (i, methodName, structName, receiverName) => {
                 const range = new vscode.Range(i, 0, i, lines[i].length);
                 G(document, i, range, codeLenses); // 生成测试用例
+                await Implements(this.context, document, methodName, i, range, codeLenses); // 跳转到接口方法
                 await I(document, methodName, IToType.ToStructMethod, i, range, codeLenses); // 结构体方法到接口方法
                 await R(document, methodName, i, range, codeLenses);
             };
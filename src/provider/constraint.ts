@@ -0,0 +1,172 @@
+import * as vscode from 'vscode';
+import { execSync } from 'child_process';
+import { IsGoFile } from '../pkg/cond';
+import { Logger } from '../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+
+const logger = Logger.withContext('BuildConstraintProvider');
+
+/**
+ * 构建约束解析结果（由 WASM 返回）
+ * Build constraints of a file (returned by WASM)
+ */
+interface BuildConstraints {
+    expr: string;             // 规范化后的表达式
+    goBuildLine: number;      // //go:build 所在行，0 表示没有
+    plusBuildLines: number[]; // // +build 所在行
+    platforms: string[];      // 满足约束的 GOOS/GOARCH 组合
+    matches: boolean;         // 当前 GOOS/GOARCH 是否满足
+    warnings: { kind: string; message: string; line: number }[];
+}
+
+/**
+ * 构建约束提供程序
+ * Build constraint provider
+ * 文件被当前 GOOS/GOARCH 排除时在首行显示提示，并报告 //go:build 与 // +build 不一致等问题
+ * Shows a banner on the first line when the file is excluded for the current GOOS/GOARCH,
+ * and reports problems such as //go:build and // +build lines that disagree
+ */
+class BuildConstraintProvider {
+    private diagnostics = vscode.languages.createDiagnosticCollection('gopp.constraint');
+
+    private banner = vscode.window.createTextEditorDecorationType({
+        isWholeLine: true,
+        backgroundColor: new vscode.ThemeColor('editorWarning.background'),
+        after: {
+            color: new vscode.ThemeColor('editorWarning.foreground'),
+            margin: '0 0 0 2em'
+        }
+    });
+
+    // 当前 GOOS/GOARCH，首次使用时通过 go env 获取
+    // Current GOOS/GOARCH, read from go env on first use
+    private platform: { goos: string; goarch: string } | undefined;
+
+    constructor(private context: vscode.ExtensionContext) {}
+
+    /**
+     * 注册事件监听
+     * Register event listeners
+     */
+    public register(): vscode.Disposable[] {
+        const onEditor = (editor: vscode.TextEditor | undefined) => {
+            if (editor && IsGoFile(editor.document)) {
+                this.refresh(editor);
+            }
+        };
+
+        onEditor(vscode.window.activeTextEditor);
+        return [
+            this.diagnostics,
+            this.banner,
+            vscode.window.onDidChangeActiveTextEditor(onEditor),
+            vscode.workspace.onDidSaveTextDocument(doc => {
+                vscode.window.visibleTextEditors.filter(editor => editor.document === doc).forEach(onEditor);
+            }),
+            vscode.workspace.onDidCloseTextDocument(doc => this.diagnostics.delete(doc.uri)),
+            vscode.workspace.onDidChangeConfiguration(e => {
+                if (e.affectsConfiguration('go.buildTags')) {
+                    onEditor(vscode.window.activeTextEditor);
+                }
+            })
+        ];
+    }
+
+    /**
+     * 重新解析编辑器中文件的构建约束
+     * Re-parse the build constraints of the file in an editor
+     * @param editor 文本编辑器 (text editor)
+     */
+    private async refresh(editor: vscode.TextEditor): Promise<void> {
+        const platform = this.currentPlatform();
+        if (!platform) {
+            return;
+        }
+
+        try {
+            const document = editor.document;
+            const result = await WasmExecutor.callFunction<string>(
+                this.context,
+                GoWasmFunction.ParseBuildConstraintsFunc,
+                document.getText(),
+                platform.goos,
+                platform.goarch,
+                JSON.stringify(this.buildTags())
+            );
+
+            const data = JSON.parse(result);
+            if (data.error !== undefined) {
+                logger.error(`解析构建约束失败: ${data.error}`);
+                return;
+            }
+
+            const constraints = data as BuildConstraints;
+            this.diagnostics.set(document.uri, constraints.warnings.map(warning => {
+                const diagnostic = new vscode.Diagnostic(
+                    document.lineAt(warning.line - 1).range,
+                    warning.message,
+                    vscode.DiagnosticSeverity.Warning
+                );
+                diagnostic.source = 'gopp';
+                diagnostic.code = warning.kind;
+                return diagnostic;
+            }));
+
+            if (constraints.matches) {
+                editor.setDecorations(this.banner, []);
+                return;
+            }
+
+            const line = Math.max(constraints.goBuildLine || constraints.plusBuildLines[0] || 1, 1) - 1;
+            const hover = new vscode.MarkdownString();
+            hover.appendMarkdown(`\`${constraints.expr}\`\n\n`);
+            hover.appendMarkdown(constraints.platforms.length > 0
+                ? `满足约束的平台 (Satisfied on): ${constraints.platforms.join(', ')}`
+                : '没有平台满足该约束 (No platform satisfies this constraint)');
+            editor.setDecorations(this.banner, [{
+                range: document.lineAt(line).range,
+                hoverMessage: hover,
+                renderOptions: {
+                    after: { contentText: `excluded from current build (${platform.goos}/${platform.goarch})` }
+                }
+            }]);
+        } catch (error) {
+            logger.error('解析构建约束时发生错误', error);
+        }
+    }
+
+    /**
+     * 获取当前 GOOS/GOARCH
+     * Get the current GOOS/GOARCH
+     */
+    private currentPlatform(): { goos: string; goarch: string } | undefined {
+        if (!this.platform) {
+            try {
+                const [goos, goarch] = execSync('go env GOOS GOARCH', { encoding: 'utf-8' }).trim().split(/\s+/);
+                this.platform = { goos, goarch };
+            } catch (error) {
+                logger.error('go env GOOS GOARCH error: ', error);
+            }
+        }
+        return this.platform;
+    }
+
+    /**
+     * 读取 go.buildTags 配置中的构建标签
+     * Read the build tags of the go.buildTags setting
+     */
+    private buildTags(): string[] {
+        const tags = vscode.workspace.getConfiguration('go').get<string>('buildTags', '');
+        return tags.split(/[\s,]+/).filter(tag => tag !== '');
+    }
+}
+
+/**
+ * 注册构建约束提示
+ * Register build constraint hints
+ * @param context 扩展上下文 (extension context)
+ * @returns 可处置的对象 (disposable objects)
+ */
+export function DisposeBuildConstraintProvider(context: vscode.ExtensionContext): vscode.Disposable[] {
+    return new BuildConstraintProvider(context).register();
+}
@@ -0,0 +1,517 @@
+import * as vscode from 'vscode';
+import * as path from 'path';
+import * as fs from 'fs';
+import { execSync } from 'child_process';
+import { Logger } from '../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+import { GoSDK } from '../core/library/sdk';
+import { joinModuleLicenses } from '../core/library/modcache';
+
+const logger = Logger.withContext('GoModProvider');
+
+/**
+ * 被项目直接导入的间接依赖（由 WASM 返回）
+ * Indirect require imported directly by the project (returned by WASM)
+ */
+interface DirectImport {
+    path: string;             // 模块路径
+    version: string;          // 模块版本
+    imports: string[];        // 项目导入的该模块的包
+    start: { line: number; column: number };
+    end: { line: number; column: number };
+}
+
+/**
+ * 没有被任何导入使用的直接依赖（由 WASM 返回）
+ * Direct require that no import uses (returned by WASM)
+ */
+interface UnusedRequire {
+    path: string;             // 模块路径
+    version: string;          // 模块版本
+    start: { line: number; column: number };
+    end: { line: number; column: number };
+}
+
+/**
+ * vendor/modules.txt 中列出的模块（由 WASM 返回）
+ * Module listed in vendor/modules.txt (returned by WASM)
+ */
+interface VendorModule {
+    path: string;             // 模块路径
+    version: string;          // 模块版本
+    explicit: boolean;        // 是否被 go.mod 直接 require
+    line: number;             // 模块所在行
+}
+
+/**
+ * 模块源码的导入信息
+ * Imports of the module source
+ */
+interface ModuleImports {
+    imports: string[];                 // 参与构建的文件的导入路径
+    ignored: Record<string, string>;   // 被构建约束排除的文件路径到内容
+}
+
+/**
+ * go.mod 诊断提供程序
+ * go.mod diagnostics provider
+ * 标出语法错误所在的行，提示被直接导入、应改为直接依赖的间接依赖，没有被使用的直接依赖，循环或降级的 replace 指令，
+ * 版本无效的 exclude，与 vendor/modules.txt 不一致的 require，+incompatible 的依赖，许可证被标记或未知的依赖，以及与本地不一致的 toolchain
+ * Marks the lines of syntax errors, and hints indirect requires that are imported directly and should become direct,
+ * direct requires that nothing uses, cyclic or downgrading replace directives, excludes with invalid
+ * versions, requires out of sync with vendor/modules.txt, +incompatible requires, requires with flagged or unknown
+ * licenses, and toolchain directives
+ * that differ from the local toolchain
+ */
+class GoModProvider implements vscode.CodeActionProvider {
+    public static readonly diagnosticCode = 'used-directly';
+    public static readonly toolchainCode = 'toolchain-mismatch';
+    public static readonly unusedCode = 'unused-require';
+    public static readonly vendorCode = 'vendor-drift';
+    public static readonly incompatibleCode = 'incompatible';
+    public static readonly parseErrorCode = 'syntax-error';
+    public static readonly missingGoCode = 'missing-go-directive';
+    public static readonly warningCodes = ['replace-cycle', 'invalid-exclude', 'replace-downgrade', 'directive-version', 'toolchain-below-go',
+        GoModProvider.missingGoCode];
+    public static readonly errorCodes = ['toolchain-below-go'];
+    public static readonly licenseCodes = ['flagged-license', 'missing-license'];
+
+    private diagnostics = vscode.languages.createDiagnosticCollection('gopp.gomod');
+
+    constructor(private context: vscode.ExtensionContext) {}
+
+    /**
+     * 注册事件监听
+     * Register event listeners
+     */
+    public register(): vscode.Disposable[] {
+        const onDocument = (doc: vscode.TextDocument) => {
+            if (path.basename(doc.fileName) === 'go.mod') {
+                this.refresh(doc);
+            }
+        };
+
+        vscode.workspace.textDocuments.forEach(onDocument);
+        return [
+            this.diagnostics,
+            vscode.workspace.onDidOpenTextDocument(onDocument),
+            vscode.workspace.onDidSaveTextDocument(onDocument),
+            vscode.workspace.onDidCloseTextDocument(doc => this.diagnostics.delete(doc.uri)),
+            vscode.languages.registerCodeActionsProvider({ pattern: '**/go.mod' }, this, {
+                providedCodeActionKinds: [vscode.CodeActionKind.QuickFix]
+            })
+        ];
+    }
+
+    /**
+     * 重新检查 go.mod 中的间接依赖
+     * Re-check the indirect requires of a go.mod
+     * @param document go.mod 文档 (go.mod document)
+     */
+    private async refresh(document: vscode.TextDocument): Promise<void> {
+        try {
+            // 语法错误时其他检查都无法进行，只报告错误位置
+            // Nothing else can be checked with syntax errors, only their positions are reported
+            const parsed = JSON.parse(await WasmExecutor.callFunction<string>(this.context, GoWasmFunction.ParseModFunc, document.getText()));
+            if (parsed.errors !== undefined) {
+                this.diagnostics.set(document.uri, this.parseErrors(document, parsed.errors));
+                return;
+            }
+
+            const moduleImports = this.listImports(path.dirname(document.fileName));
+            const result = await WasmExecutor.callFunction<string>(
+                this.context,
+                GoWasmFunction.CheckIndirectImportsFunc,
+                document.getText(),
+                JSON.stringify(moduleImports.imports)
+            );
+
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                logger.error(`检查间接依赖失败: ${data.error}`);
+                return;
+            }
+
+            const diagnostics = (data as DirectImport[]).map(item => this.toDiagnostic(item));
+            diagnostics.push(...await this.checkUnused(document, moduleImports));
+
+            if (parsed.error === undefined) {
+                diagnostics.push(...this.checkWarnings(document, parsed));
+                diagnostics.push(...this.checkIncompatible(document, parsed));
+                diagnostics.push(...await this.checkVendor(document, parsed));
+                diagnostics.push(...await this.checkLicenses(document));
+                const toolchain = this.checkToolchain(document, parsed);
+                if (toolchain) {
+                    diagnostics.push(toolchain);
+                }
+            }
+            this.diagnostics.set(document.uri, diagnostics);
+        } catch (error) {
+            logger.error('检查间接依赖时发生错误', error);
+        }
+    }
+
+    /**
+     * 将 ParseMod 返回的语法错误转换为诊断，列未知时标出整行
+     * Turn the syntax errors returned by ParseMod into diagnostics, marking the whole line when the column is unknown
+     * @param document go.mod 文档 (go.mod document)
+     * @param errors 带位置的语法错误 (syntax errors with positions)
+     */
+    private parseErrors(document: vscode.TextDocument, errors: { line: number; column: number; message: string }[]): vscode.Diagnostic[] {
+        return errors.filter(error => error.line <= document.lineCount).map(error => {
+            const line = document.lineAt(error.line - 1);
+            const range = error.column > 1
+                ? new vscode.Range(error.line - 1, error.column - 1, error.line - 1, line.range.end.character)
+                : line.range;
+            const diagnostic = new vscode.Diagnostic(range, error.message, vscode.DiagnosticSeverity.Error);
+            diagnostic.source = 'gopp';
+            diagnostic.code = GoModProvider.parseErrorCode;
+            return diagnostic;
+        });
+    }
+
+    /**
+     * 检查没有被任何导入使用的直接依赖
+     * Check the direct requires that no import uses
+     * @param document go.mod 文档 (go.mod document)
+     * @param moduleImports 模块源码的导入信息 (imports of the module source)
+     */
+    private async checkUnused(document: vscode.TextDocument, moduleImports: ModuleImports): Promise<vscode.Diagnostic[]> {
+        // go list 失败时无法判断依赖是否被使用
+        // Whether a require is used is unknown when go list fails
+        if (moduleImports.imports.length === 0) {
+            return [];
+        }
+
+        const result = await WasmExecutor.callFunction<string>(
+            this.context,
+            GoWasmFunction.FindUnusedRequiresFunc,
+            document.getText(),
+            JSON.stringify(moduleImports.imports),
+            JSON.stringify(moduleImports.ignored)
+        );
+
+        const data = JSON.parse(result);
+        if (!Array.isArray(data)) {
+            logger.error(`检查未使用的依赖失败: ${data.error}`);
+            return [];
+        }
+
+        return (data as UnusedRequire[]).map(item => {
+            const diagnostic = new vscode.Diagnostic(
+                new vscode.Range(
+                    item.start.line - 1, item.start.column - 1,
+                    item.end.line - 1, item.end.column - 1
+                ),
+                `${item.path} is not imported by any package`,
+                vscode.DiagnosticSeverity.Information
+            );
+            diagnostic.source = 'gopp';
+            diagnostic.code = GoModProvider.unusedCode;
+            diagnostic.tags = [vscode.DiagnosticTag.Unnecessary];
+            return diagnostic;
+        });
+    }
+
+    /**
+     * 报告需要在编辑器中标出的解析警告：相互指向的 replace 指令、版本无效的 exclude、
+     * 将模块降级到低于所需版本的 replace、需要更高 go 版本的指令，这些通常都是错误；
+     * 缺少 go 指令时 go 命令假定为 go 1.16；低于 go 版本的工具链会被 go 命令拒绝，报告为错误
+     * Report the parse warnings underlined in the editor: replace directives pointing
+     * at each other, excludes with invalid versions, replaces downgrading a module
+     * below its required version and directives needing a newer go version, all usually
+     * mistakes; without a go directive the go command assumes go 1.16; a toolchain older
+     * than the go version is rejected by the go command and reported as an error
+     * @param document go.mod 文档 (go.mod document)
+     * @param data ParseMod 的结果 (result of ParseMod)
+     */
+    private checkWarnings(document: vscode.TextDocument, data: any): vscode.Diagnostic[] {
+        return (data.warnings || [])
+            .filter((warning: { kind: string }) => GoModProvider.warningCodes.includes(warning.kind))
+            .map((warning: { kind: string; message: string; line: number }) => {
+                const diagnostic = new vscode.Diagnostic(
+                    document.lineAt(warning.line - 1).range,
+                    warning.message,
+                    GoModProvider.errorCodes.includes(warning.kind) ? vscode.DiagnosticSeverity.Error : vscode.DiagnosticSeverity.Warning
+                );
+                diagnostic.source = 'gopp';
+                diagnostic.code = warning.kind;
+                return diagnostic;
+            });
+    }
+
+    /**
+     * 报告许可证在 gopp.dependencies.flaggedLicenses 中的依赖，以及模块缓存中找不到许可证的依赖
+     * Report requires whose license is listed in gopp.dependencies.flaggedLicenses, and requires
+     * whose license is not found in the module cache
+     * @param document go.mod 文档 (go.mod document)
+     */
+    private async checkLicenses(document: vscode.TextDocument): Promise<vscode.Diagnostic[]> {
+        const data = await joinModuleLicenses(this.context, document.getText());
+        return ((data?.warnings || []) as { kind: string; message: string; line: number }[])
+            .filter(warning => GoModProvider.licenseCodes.includes(warning.kind))
+            .map(warning => {
+                const diagnostic = new vscode.Diagnostic(
+                    document.lineAt(warning.line - 1).range,
+                    warning.message,
+                    warning.kind === 'flagged-license' ? vscode.DiagnosticSeverity.Warning : vscode.DiagnosticSeverity.Information
+                );
+                diagnostic.source = 'gopp';
+                diagnostic.code = warning.kind;
+                return diagnostic;
+            });
+    }
+
+    /**
+     * 标出 +incompatible 的依赖：模块在该主版本没有 go.mod，诊断链接到 pkg.go.dev 的版本列表，
+     * 用于查看是否已有支持模块的新主版本
+     * Mark +incompatible requires: the module has no go.mod for that major version; the diagnostic
+     * links to the versions tab of pkg.go.dev to check whether a module-aware major version exists
+     * @param document go.mod 文档 (go.mod document)
+     * @param data ParseMod 的结果 (result of ParseMod)
+     */
+    private checkIncompatible(document: vscode.TextDocument, data: any): vscode.Diagnostic[] {
+        return ((data.require || []) as { path: string; version: string; incompatible: boolean; start: { line: number } }[])
+            .filter(req => req.incompatible)
+            .map(req => {
+                const diagnostic = new vscode.Diagnostic(
+                    document.lineAt(req.start.line - 1).range,
+                    `${req.path} ${req.version} has no go.mod, check whether a module-aware version exists`,
+                    vscode.DiagnosticSeverity.Information
+                );
+                diagnostic.source = 'gopp';
+                diagnostic.code = {
+                    value: GoModProvider.incompatibleCode,
+                    target: vscode.Uri.parse(`https://pkg.go.dev/${req.path}?tab=versions`)
+                };
+                return diagnostic;
+            });
+    }
+
+    /**
+     * 比较 require 与 vendor/modules.txt，报告版本不一致、未 vendor 以及已不再 require 的模块，
+     * 这些都需要重新运行 go mod vendor；没有 vendor 目录时不检查
+     * Compare the requires with vendor/modules.txt, reporting modules whose versions differ,
+     * that are not vendored, or that are vendored but no longer required, all of which need
+     * go mod vendor to run again; nothing is checked without a vendor directory
+     * @param document go.mod 文档 (go.mod document)
+     * @param data ParseMod 的结果 (result of ParseMod)
+     */
+    private async checkVendor(document: vscode.TextDocument, data: any): Promise<vscode.Diagnostic[]> {
+        const modulesTxt = path.join(path.dirname(document.fileName), 'vendor', 'modules.txt');
+        if (!fs.existsSync(modulesTxt)) {
+            return [];
+        }
+
+        const result = await WasmExecutor.callFunction<string>(
+            this.context,
+            GoWasmFunction.ParseVendorModulesFunc,
+            fs.readFileSync(modulesTxt, 'utf-8')
+        );
+        const vendored = JSON.parse(result);
+        if (!Array.isArray(vendored)) {
+            logger.error(`解析 vendor/modules.txt 失败: ${vendored.error}`);
+            return [];
+        }
+
+        const byPath = new Map((vendored as VendorModule[]).map(mod => [mod.path, mod]));
+        const required = new Set<string>();
+        const diagnostics: vscode.Diagnostic[] = [];
+        const report = (line: number, message: string) => {
+            const diagnostic = new vscode.Diagnostic(
+                document.lineAt(line - 1).range,
+                `${message}, run go mod vendor`,
+                vscode.DiagnosticSeverity.Warning
+            );
+            diagnostic.source = 'gopp';
+            diagnostic.code = GoModProvider.vendorCode;
+            diagnostics.push(diagnostic);
+        };
+
+        for (const req of (data.require || []) as { path: string; version: string; start: { line: number } }[]) {
+            required.add(req.path);
+            const mod = byPath.get(req.path);
+            if (!mod || !mod.explicit) {
+                report(req.start.line, `${req.path} ${req.version} is not marked explicit in vendor/modules.txt`);
+            } else if (mod.version !== req.version) {
+                report(req.start.line, `${req.path} is ${req.version} in go.mod but ${mod.version} in vendor/modules.txt`);
+            }
+        }
+
+        let moduleLine = 1;
+        for (let i = 0; i < document.lineCount; i++) {
+            if (/^module\s/.test(document.lineAt(i).text)) {
+                moduleLine = i + 1;
+                break;
+            }
+        }
+        for (const mod of vendored as VendorModule[]) {
+            if (mod.explicit && !required.has(mod.path)) {
+                report(moduleLine, `vendor/modules.txt lists ${mod.path} ${mod.version} which go.mod does not require`);
+            }
+        }
+        return diagnostics;
+    }
+
+    /**
+     * 比较 toolchain 指令与本地工具链
+     * Compare the toolchain directive with the local toolchain
+     * @param document go.mod 文档 (go.mod document)
+     * @param data ParseMod 的结果 (result of ParseMod)
+     * @returns 不一致时的诊断 (diagnostic when they differ)
+     */
+    private checkToolchain(document: vscode.TextDocument, data: any): vscode.Diagnostic | undefined {
+        if (!data.toolchainVersion) {
+            return undefined;
+        }
+
+        const local = new GoSDK().execute()?.GoVersion;
+        if (!local || local === data.toolchainVersion) {
+            return undefined;
+        }
+
+        const line = data.toolchainStart.line - 1;
+        const diagnostic = new vscode.Diagnostic(
+            document.lineAt(line).range,
+            `go.mod requests toolchain ${data.toolchain}, the local toolchain is go${local}`,
+            vscode.DiagnosticSeverity.Information
+        );
+        diagnostic.source = 'gopp';
+        diagnostic.code = GoModProvider.toolchainCode;
+        return diagnostic;
+    }
+
+    /**
+     * 提供 "Switch toolchain"、"Remove unused dependency"、"Add go directive" 与 "Use forward slashes" 快速修复
+     * Provide the "Switch toolchain", "Remove unused dependency", "Add go directive" and "Use forward slashes" quick-fixes
+     */
+    public provideCodeActions(
+        document: vscode.TextDocument,
+        range: vscode.Range | vscode.Selection,
+        context: vscode.CodeActionContext
+    ): vscode.CodeAction[] {
+        const actions: vscode.CodeAction[] = [];
+        for (const diagnostic of context.diagnostics) {
+            if (diagnostic.code === GoModProvider.unusedCode) {
+                const line = document.lineAt(diagnostic.range.start.line);
+                const modulePath = line.text.trim().replace(/^require\s+/, '').split(/\s+/)[0];
+                const action = new vscode.CodeAction(`Remove unused dependency ${modulePath}`, vscode.CodeActionKind.QuickFix);
+                action.diagnostics = [diagnostic];
+                action.edit = new vscode.WorkspaceEdit();
+                action.edit.delete(document.uri, line.rangeIncludingLineBreak);
+                actions.push(action);
+                continue;
+            }
+            if (diagnostic.code === GoModProvider.parseErrorCode && diagnostic.message.includes('appears to be Windows path')) {
+                // 反斜杠的 replace 路径只能在 Windows 上解析
+                // Replace paths with backslashes only parse on Windows
+                const action = new vscode.CodeAction('Use forward slashes in replace paths', vscode.CodeActionKind.QuickFix);
+                action.diagnostics = [diagnostic];
+                action.isPreferred = true;
+                action.command = { title: action.title, command: 'gopp.normalizeReplacePaths' };
+                actions.push(action);
+                continue;
+            }
+            if (diagnostic.code === GoModProvider.missingGoCode) {
+                const action = this.addGoDirective(document, diagnostic);
+                if (action) {
+                    actions.push(action);
+                }
+                continue;
+            }
+            if (diagnostic.code !== GoModProvider.toolchainCode) {
+                continue;
+            }
+            const name = document.lineAt(diagnostic.range.start.line).text.trim().replace(/^toolchain\s+/, '');
+            const action = new vscode.CodeAction(`Switch toolchain to ${name}`, vscode.CodeActionKind.QuickFix);
+            action.diagnostics = [diagnostic];
+            action.command = {
+                title: `Switch toolchain to ${name}`,
+                command: 'gopp.switchToolchain',
+                arguments: [name, path.dirname(document.fileName)]
+            };
+            actions.push(action);
+        }
+        return actions;
+    }
+
+    /**
+     * 在 module 指令之后插入本地工具链版本的 go 指令，与 go mod init 生成的位置一致
+     * Insert a go directive with the local toolchain version after the module directive, where go mod init puts it
+     * @param document go.mod 文档 (go.mod document)
+     * @param diagnostic 缺少 go 指令的诊断，位于 module 指令所在行 (missing go directive diagnostic, on the module directive line)
+     */
+    private addGoDirective(document: vscode.TextDocument, diagnostic: vscode.Diagnostic): vscode.CodeAction | undefined {
+        const version = new GoSDK().execute()?.GoVersion;
+        if (!version) {
+            return undefined;
+        }
+
+        const action = new vscode.CodeAction(`Add go ${version} directive`, vscode.CodeActionKind.QuickFix);
+        action.diagnostics = [diagnostic];
+        action.isPreferred = true;
+        action.edit = new vscode.WorkspaceEdit();
+        const moduleLine = document.lineAt(diagnostic.range.start.line);
+        action.edit.insert(document.uri, moduleLine.range.end, `\n\ngo ${version}`);
+        return action;
+    }
+
+    /**
+     * 列出模块中所有包（含测试）导入的路径，以及被构建约束排除的文件
+     * List the import paths of every package in the module, tests included,
+     * and the files excluded by build constraints
+     * @param cwd 模块目录 (module directory)
+     */
+    private listImports(cwd: string): ModuleImports {
+        const format = '{{join .Imports "\\n"}}\n{{join .TestImports "\\n"}}\n{{join .XTestImports "\\n"}}' +
+            '{{range .IgnoredGoFiles}}\n{{$.Dir}}{{"\\t"}}{{.}}{{end}}';
+        const moduleImports: ModuleImports = { imports: [], ignored: {} };
+        try {
+            const stdout = execSync(`go list -e -f '${format}' ./...`, { cwd }).toString();
+            const imports = new Set<string>();
+            for (const line of stdout.split('\n').map(line => line.trim()).filter(line => line !== '')) {
+                // 含制表符的行是被排除的文件: 目录\t文件名
+                // Lines with a tab are excluded files: dir\tfile name
+                const [dir, file] = line.split('\t');
+                if (file === undefined) {
+                    imports.add(line);
+                    continue;
+                }
+                const filePath = path.join(dir, file);
+                moduleImports.ignored[filePath] = fs.readFileSync(filePath, 'utf-8');
+            }
+            moduleImports.imports = [...imports];
+        } catch (error) {
+            logger.error('go list 获取导入路径失败', error);
+        }
+        return moduleImports;
+    }
+
+    /**
+     * 将检查结果转换为诊断
+     * Convert a check result to a diagnostic
+     */
+    private toDiagnostic(item: DirectImport): vscode.Diagnostic {
+        const diagnostic = new vscode.Diagnostic(
+            new vscode.Range(
+                item.start.line - 1, item.start.column - 1,
+                item.end.line - 1, item.end.column - 1
+            ),
+            `${item.path} is used directly — run go mod tidy (${item.imports.join(', ')})`,
+            vscode.DiagnosticSeverity.Information
+        );
+        diagnostic.source = 'gopp';
+        diagnostic.code = GoModProvider.diagnosticCode;
+        return diagnostic;
+    }
+}
+
+/**
+ * 注册 go.mod 诊断
+ * Register go.mod diagnostics
+ * @param context 扩展上下文 (extension context)
+ * @returns 可处置的对象 (disposable objects)
+ */
+export function DisposeGoModProvider(context: vscode.ExtensionContext): vscode.Disposable[] {
+    return new GoModProvider(context).register();
+}
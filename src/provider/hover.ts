@@ -0,0 +1,137 @@
+import * as vscode from 'vscode';
+import {
+    resolveDependencyTree, fetchLatestVersion, isUpgradeAvailable, explainRequire, filterExcludedVersions, classifyRequireUsage,
+    invalidateImportLists, joinModuleLicenses, DependencyNode, RequireExplanation
+} from '../core/library/modcache';
+
+/**
+ * go.mod 悬停提供程序
+ * go.mod hover provider
+ * 悬停在 require 条目上时显示最新版本和直接子依赖，间接依赖还显示引入它的直接依赖，
+ * 只被测试导入的依赖标记为 test-only，并显示模块缓存中找到的许可证
+ * Shows the latest version and the direct sub-dependencies when hovering a require entry,
+ * and for indirect requires the direct dependencies that introduce them; requires imported
+ * from tests only are marked test-only, and the license found in the module cache is shown
+ */
+class GoModHoverProvider implements vscode.HoverProvider {
+
+    constructor(private context: vscode.ExtensionContext) {}
+
+    /**
+     * 提供悬停信息
+     * Provide hover
+     * @param document 当前文档 (current document)
+     * @param position 悬停位置 (hover position)
+     */
+    public async provideHover(document: vscode.TextDocument, position: vscode.Position): Promise<vscode.Hover | undefined> {
+        // 匹配 require 行: [require] path version [// indirect]
+        // Match require lines: [require] path version [// indirect]
+        const line = document.lineAt(position.line).text;
+        const match = line.match(/^\s*(?:require\s+)?(\S+)\s+(v\S+)/);
+        if (!match || ['module', 'go', 'toolchain', 'replace', 'exclude', 'retract'].includes(match[1])) {
+            return undefined;
+        }
+
+        const [tree, latest, why, usages, licensed] = await Promise.all([
+            resolveDependencyTree(this.context, match[1], match[2], 2),
+            fetchLatestVersion(match[1]),
+            /\/\/\s*indirect\b/.test(line) ? explainRequire(this.context, document.getText(), match[1]) : undefined,
+            classifyRequireUsage(this.context, document.fileName, document.getText()),
+            joinModuleLicenses(this.context, document.getText())
+        ]);
+        if (!tree) {
+            return undefined;
+        }
+
+        const markdown = new vscode.MarkdownString();
+        markdown.appendMarkdown(`**${tree.path}@${tree.version}**\n\n`);
+        if (usages?.find(usage => usage.path === tree.path)?.usage === 'test-only') {
+            markdown.appendMarkdown('`test-only` 只被测试导入，不会进入生产构建 (imported from tests only, not in production builds)\n\n');
+        }
+        const license = (licensed?.require as { path: string; license: string }[] | undefined)?.find(req => req.path === tree.path)?.license;
+        if (license) {
+            markdown.appendMarkdown(`license: ${license}\n\n`);
+        }
+        if (latest) {
+            // 最新版本被 exclude 时不建议升级到它
+            // Don't suggest upgrading to the latest version when it is excluded
+            const [versions] = await filterExcludedVersions(this.context, document.getText(), { [tree.path]: [latest] }) ?? [];
+            if (versions && versions.candidates.length === 0) {
+                markdown.appendMarkdown(`latest: ~~${latest}~~ (excluded)\n\n`);
+            } else {
+                const upgrade = await isUpgradeAvailable(this.context, tree.version, latest);
+                markdown.appendMarkdown(upgrade ? `latest: ${latest} (you have ${tree.version})\n\n` : `latest: ${latest}\n\n`);
+            }
+        }
+        if (tree.missing) {
+            markdown.appendMarkdown('go.mod 不在模块缓存中 (go.mod not found in the module cache)');
+        } else if (tree.error) {
+            markdown.appendText(tree.error);
+        } else if (tree.children.length === 0) {
+            markdown.appendMarkdown('没有依赖 (No dependencies)');
+        } else {
+            markdown.appendMarkdown(tree.children.map(child => `- ${describeNode(child)}`).join('\n'));
+        }
+        if (why) {
+            markdown.appendMarkdown(`\n\n${describeExplanation(why)}`);
+        }
+        return new vscode.Hover(markdown);
+    }
+}
+
+/**
+ * 描述依赖树节点
+ * Describe a dependency tree node
+ * @param node 依赖树节点 (dependency tree node)
+ */
+function describeNode(node: DependencyNode): string {
+    const marks: string[] = [];
+    if (node.indirect) {
+        marks.push('indirect');
+    }
+    if (node.cycle) {
+        marks.push('cycle');
+    }
+    if (node.children.length > 0) {
+        marks.push(`${node.children.length} deps`);
+    }
+    return `\`${node.path}\` ${node.version}${marks.length > 0 ? ` _(${marks.join(', ')})_` : ''}`;
+}
+
+/**
+ * 描述依赖引入原因
+ * Describe why a module is required
+ * @param why 引入原因 (explanation)
+ */
+function describeExplanation(why: RequireExplanation): string {
+    const incomplete = why.missing.length > 0
+        ? `\n\n_${why.missing.length} 个 go.mod 不在模块缓存中，结果可能不完整 (${why.missing.length} go.mod files not in the module cache, results may be incomplete)_`
+        : '';
+    if (!why.required) {
+        return `**Why:** 没有直接依赖引入该模块 (not required by any direct dependency)${incomplete}`;
+    }
+    const chains = why.chains.map(chain => `- ${chain.path.map(mod => `\`${mod}\``).join(' → ')}`).join('\n');
+    return `**Why:** 由 ${why.chains.length} 个直接依赖引入 (required via ${why.chains.length} direct dependencies)\n\n${chains}${incomplete}`;
+}
+
+/**
+ * 注册 go.mod 悬停提供程序
+ * Register go.mod hover provider
+ * @param context 扩展上下文 (extension context)
+ * @returns 可处置的对象 (disposable object)
+ */
+export function DisposeHoverProvider(context: vscode.ExtensionContext): vscode.Disposable {
+    return vscode.Disposable.from(
+        vscode.languages.registerHoverProvider(
+            { pattern: '**/go.mod' },
+            new GoModHoverProvider(context)
+        ),
+        // 导入变化后重新运行 go list
+        // Run go list again once imports may have changed
+        vscode.workspace.onDidSaveTextDocument(doc => {
+            if (doc.languageId === 'go') {
+                invalidateImportLists();
+            }
+        })
+    );
+}
@@ -0,0 +1,124 @@
+import * as vscode from 'vscode';
+import { IsGoFile } from '../pkg/cond';
+import { debounce } from '../pkg/util';
+import { Logger } from '../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+import { readWorkspaceGoFiles } from '../core/navigator/implementation';
+
+const logger = Logger.withContext('NilInterfaceProvider');
+
+/**
+ * 以接口形式返回的可能为 nil 的具体类型值（由 WASM 返回）
+ * Possibly nil concrete value returned as an interface (returned by WASM)
+ * 例如返回 error 的函数中 var err *MyError ... return err
+ */
+interface NilInterface {
+    path: string;             // return 语句所在文件
+    start: { line: number; column: number };
+    end: { line: number; column: number };
+    function: string;         // 函数名称，函数字面量为空
+    type: string;             // 返回的具体类型
+    interface: string;        // 返回值的接口类型
+    explicit: boolean;        // 是否为 (*T)(nil) 形式的带类型 nil
+    comparisons: { path: string; line: number; column: number }[]; // 函数结果与 nil 的比较
+}
+
+/**
+ * nil 接口陷阱诊断提供程序
+ * Nil interface pitfall diagnostics provider
+ * 包装了 nil 指针的接口值不等于 nil，调用方的 err != nil 判断会始终成立
+ * An interface holding a nil pointer is not nil, so err != nil at the caller always holds
+ */
+class NilInterfaceProvider {
+    public static readonly diagnosticCode = 'nil-interface';
+
+    private diagnostics = vscode.languages.createDiagnosticCollection('gopp.nilInterface');
+
+    constructor(private context: vscode.ExtensionContext) {}
+
+    /**
+     * 注册事件监听
+     * Register event listeners
+     */
+    public register(): vscode.Disposable[] {
+        const refresh = debounce(() => this.refresh(), 1000);
+        const onDocument = (doc: vscode.TextDocument) => {
+            if (IsGoFile(doc)) {
+                refresh();
+            }
+        };
+
+        refresh();
+        return [
+            this.diagnostics,
+            vscode.workspace.onDidOpenTextDocument(onDocument),
+            vscode.workspace.onDidSaveTextDocument(onDocument),
+            vscode.workspace.onDidChangeTextDocument(e => onDocument(e.document))
+        ];
+    }
+
+    /**
+     * 重新检查工作空间中的 nil 接口返回
+     * Re-check the nil interface returns in the workspace
+     */
+    private async refresh(): Promise<void> {
+        try {
+            const files = await readWorkspaceGoFiles();
+            const result = await WasmExecutor.callFunction<string>(
+                this.context,
+                GoWasmFunction.CheckNilInterfacesFunc,
+                JSON.stringify(files)
+            );
+
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                logger.error(`检查 nil 接口失败: ${data.error}`);
+                return;
+            }
+
+            const byFile = new Map<string, vscode.Diagnostic[]>();
+            for (const item of data as NilInterface[]) {
+                const items = byFile.get(item.path) || [];
+                items.push(this.toDiagnostic(item));
+                byFile.set(item.path, items);
+            }
+
+            this.diagnostics.clear();
+            for (const [filePath, items] of byFile) {
+                this.diagnostics.set(vscode.Uri.file(filePath), items);
+            }
+        } catch (error) {
+            logger.error('检查 nil 接口时发生错误', error);
+        }
+    }
+
+    /**
+     * 将检查结果转换为诊断，与 nil 的比较作为相关信息
+     * Convert a result to a diagnostic, with the comparisons with nil as related information
+     */
+    private toDiagnostic(item: NilInterface): vscode.Diagnostic {
+        const value = item.explicit ? `a nil ${item.type}` : `${item.type}, which may be nil,`;
+        const diagnostic = new vscode.Diagnostic(
+            new vscode.Range(item.start.line - 1, item.start.column - 1, item.end.line - 1, item.end.column - 1),
+            `returning ${value} as ${item.interface} gives a non-nil interface; return nil explicitly when there is no value`,
+            vscode.DiagnosticSeverity.Warning
+        );
+        diagnostic.source = 'gopp';
+        diagnostic.code = NilInterfaceProvider.diagnosticCode;
+        diagnostic.relatedInformation = item.comparisons.map(cmp => new vscode.DiagnosticRelatedInformation(
+            new vscode.Location(vscode.Uri.file(cmp.path), new vscode.Position(cmp.line - 1, cmp.column - 1)),
+            `result of ${item.function} compared with nil, it is never nil after this return`
+        ));
+        return diagnostic;
+    }
+}
+
+/**
+ * 注册 nil 接口陷阱诊断
+ * Register nil interface pitfall diagnostics
+ * @param context 扩展上下文 (extension context)
+ * @returns 可处置的对象 (disposable objects)
+ */
+export function DisposeNilInterfaceProvider(context: vscode.ExtensionContext): vscode.Disposable[] {
+    return new NilInterfaceProvider(context).register();
+}
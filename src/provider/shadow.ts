@@ -0,0 +1,137 @@
+import * as vscode from 'vscode';
+import { IsGoFile } from '../pkg/cond';
+import { debounce } from '../pkg/util';
+import { Logger } from '../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+import { readWorkspaceGoFiles } from '../core/navigator/implementation';
+
+const logger = Logger.withContext('ShadowProvider');
+
+/**
+ * 遮蔽了提升方法的方法（由 WASM 返回）
+ * Method hiding a promoted method (returned by WASM)
+ * 例如嵌入 EnglishGreeter 的 ChineseGreeter 上声明的 SayHello
+ */
+interface ShadowedMethod {
+    type: string;             // 声明方法的类型
+    method: string;           // 方法名称
+    path: string;             // 方法所在文件
+    start: { line: number; column: number };  // 方法名起始位置
+    end: { line: number; column: number };    // 方法名结束位置
+    embedded: string;         // 被遮蔽方法经过的嵌入字段，如 EnglishGreeter 或 Base.Inner
+    shadowedPath: string;     // 被遮蔽方法所在文件
+    shadowedLine: number;     // 被遮蔽方法所在行
+    interfaces: string[];     // 类型实现的、包含该方法的接口
+}
+
+/**
+ * 方法遮蔽提示提供程序
+ * Shadowed method hints provider
+ * 以提示的形式标出遮蔽了嵌入字段提升方法的方法，并链接到被遮蔽的方法；
+ * 默认只报告类型所实现接口中的方法，可通过 gopp.shadowedMethods.interfacesOnly 关闭
+ * Marks methods hiding a method promoted from an embedded field as hints, linking to the hidden method;
+ * only methods of interfaces the type implements are reported unless gopp.shadowedMethods.interfacesOnly is off
+ */
+class ShadowProvider {
+    private diagnostics = vscode.languages.createDiagnosticCollection('gopp.shadowedMethods');
+
+    constructor(private context: vscode.ExtensionContext) {}
+
+    /**
+     * 注册事件监听
+     * Register event listeners
+     */
+    public register(): vscode.Disposable[] {
+        const refresh = debounce(() => this.refresh(), 1000);
+        const onDocument = (doc: vscode.TextDocument) => {
+            if (IsGoFile(doc)) {
+                refresh();
+            }
+        };
+
+        refresh();
+        return [
+            this.diagnostics,
+            vscode.workspace.onDidOpenTextDocument(onDocument),
+            vscode.workspace.onDidSaveTextDocument(onDocument),
+            vscode.workspace.onDidChangeTextDocument(e => onDocument(e.document)),
+            vscode.workspace.onDidChangeConfiguration(e => {
+                if (e.affectsConfiguration('gopp.shadowedMethods')) {
+                    refresh();
+                }
+            })
+        ];
+    }
+
+    /**
+     * 重新检查工作空间中被遮蔽的方法
+     * Re-check the shadowed methods in the workspace
+     */
+    private async refresh(): Promise<void> {
+        try {
+            const interfacesOnly = vscode.workspace.getConfiguration('gopp.shadowedMethods').get<boolean>('interfacesOnly', true);
+            const files = await readWorkspaceGoFiles();
+            const result = await WasmExecutor.callFunction<string>(
+                this.context,
+                GoWasmFunction.FindShadowedMethodsFunc,
+                JSON.stringify(files),
+                !interfacesOnly
+            );
+
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                logger.error(`检查被遮蔽的方法失败: ${data.error}`);
+                return;
+            }
+
+            const byFile = new Map<string, vscode.Diagnostic[]>();
+            for (const item of data as ShadowedMethod[]) {
+                const diagnostics = byFile.get(item.path) || [];
+                diagnostics.push(this.toDiagnostic(item));
+                byFile.set(item.path, diagnostics);
+            }
+
+            this.diagnostics.clear();
+            for (const [filePath, diagnostics] of byFile) {
+                this.diagnostics.set(vscode.Uri.file(filePath), diagnostics);
+            }
+        } catch (error) {
+            logger.error('检查被遮蔽的方法时发生错误', error);
+        }
+    }
+
+    /**
+     * 将检查结果转换为诊断，相关信息指向被遮蔽的方法
+     * Convert a result to a diagnostic whose related information points at the hidden method
+     */
+    private toDiagnostic(item: ShadowedMethod): vscode.Diagnostic {
+        const via = item.interfaces.length > 0 ? ` (used by ${item.interfaces.join(', ')})` : '';
+        const diagnostic = new vscode.Diagnostic(
+            new vscode.Range(
+                item.start.line - 1, item.start.column - 1,
+                item.end.line - 1, item.end.column - 1
+            ),
+            `${item.type}.${item.method} shadows ${item.embedded}.${item.method}${via}`,
+            vscode.DiagnosticSeverity.Hint
+        );
+        diagnostic.source = 'gopp';
+        diagnostic.code = 'shadowed-method';
+        diagnostic.relatedInformation = [
+            new vscode.DiagnosticRelatedInformation(
+                new vscode.Location(vscode.Uri.file(item.shadowedPath), new vscode.Position(item.shadowedLine - 1, 0)),
+                `${item.embedded}.${item.method} is declared here`
+            )
+        ];
+        return diagnostic;
+    }
+}
+
+/**
+ * 注册方法遮蔽提示
+ * Register shadowed method hints
+ * @param context 扩展上下文 (extension context)
+ * @returns 可处置的对象 (disposable objects)
+ */
+export function DisposeShadowProvider(context: vscode.ExtensionContext): vscode.Disposable[] {
+    return new ShadowProvider(context).register();
+}
@@ -0,0 +1,170 @@
+import * as vscode from 'vscode';
+import * as path from 'path';
+import { IsGoFile } from '../pkg/cond';
+import { debounce } from '../pkg/util';
+import { Logger } from '../pkg/logger';
+import { WasmExecutor, GoWasmFunction } from '../pkg/wasm';
+
+const logger = Logger.withContext('TestPlacementProvider');
+
+/**
+ * go test 不会运行的疑似测试函数（由 WASM 返回）
+ * Test-looking function go test does not run (returned by WASM)
+ * 例如 x.go 中的 func TestFoo(t *testing.T) 或 x_test.go 中的 func Testfoo(t *testing.T)
+ */
+interface TestProblem {
+    name: string;             // 函数名称
+    kind: string;             // test、benchmark 或 fuzz
+    problem: string;          // 问题类型，如 test-in-non-test-file
+    message: string;
+    start: { line: number; column: number };  // 函数名起始位置
+    end: { line: number; column: number };    // 函数名结束位置
+    suggestion: string;       // 建议的文件名或函数名，没有时为空
+}
+
+/**
+ * 测试函数位置诊断提供程序
+ * Test placement diagnostics provider
+ * 报告 _test.go 之外的测试以及名称或签名不正确的测试函数，并提供重命名文件或函数的快速修复
+ * Reports tests outside _test.go files and test functions with a malformed name or signature,
+ * offering quick-fixes that rename the file or the function
+ */
+class TestPlacementProvider implements vscode.CodeActionProvider {
+    private diagnostics = vscode.languages.createDiagnosticCollection('gopp.testPlacement');
+
+    // 每个文件的检查结果，用于快速修复
+    // Problems per file, used by the quick-fixes
+    private results: Map<string, TestProblem[]> = new Map();
+
+    constructor(private context: vscode.ExtensionContext) {}
+
+    /**
+     * 注册事件监听
+     * Register event listeners
+     */
+    public register(): vscode.Disposable[] {
+        const refresh = debounce((doc: vscode.TextDocument) => this.refresh(doc), 500);
+        const onDocument = (doc: vscode.TextDocument) => {
+            if (IsGoFile(doc)) {
+                refresh(doc);
+            }
+        };
+
+        vscode.workspace.textDocuments.forEach(doc => {
+            if (IsGoFile(doc)) {
+                this.refresh(doc);
+            }
+        });
+        return [
+            this.diagnostics,
+            vscode.workspace.onDidOpenTextDocument(onDocument),
+            vscode.workspace.onDidChangeTextDocument(e => onDocument(e.document)),
+            vscode.workspace.onDidCloseTextDocument(doc => {
+                this.diagnostics.delete(doc.uri);
+                this.results.delete(doc.uri.fsPath);
+            }),
+            vscode.languages.registerCodeActionsProvider({ language: 'go', scheme: 'file' }, this, {
+                providedCodeActionKinds: [vscode.CodeActionKind.QuickFix]
+            })
+        ];
+    }
+
+    /**
+     * 重新检查文件中的测试函数
+     * Re-check the test functions of a file
+     * @param document Go 文件 (Go file)
+     */
+    private async refresh(document: vscode.TextDocument): Promise<void> {
+        try {
+            const result = await WasmExecutor.callFunction<string>(
+                this.context,
+                GoWasmFunction.CheckTestPlacementFunc,
+                document.getText(),
+                document.fileName
+            );
+
+            const data = JSON.parse(result);
+            if (!Array.isArray(data)) {
+                // 编辑中的文件可能暂时无法解析
+                // A file being edited may not parse for a while
+                logger.debug(`检查测试函数失败: ${data.error}`);
+                return;
+            }
+
+            const problems = data as TestProblem[];
+            this.results.set(document.uri.fsPath, problems);
+            this.diagnostics.set(document.uri, problems.map(problem => {
+                const diagnostic = new vscode.Diagnostic(
+                    this.toRange(problem),
+                    problem.message,
+                    vscode.DiagnosticSeverity.Warning
+                );
+                diagnostic.source = 'gopp';
+                diagnostic.code = problem.problem;
+                return diagnostic;
+            }));
+        } catch (error) {
+            logger.error('检查测试函数时发生错误', error);
+        }
+    }
+
+    /**
+     * 函数名的范围
+     * Range of the function name
+     */
+    private toRange(problem: TestProblem): vscode.Range {
+        return new vscode.Range(
+            problem.start.line - 1, problem.start.column - 1,
+            problem.end.line - 1, problem.end.column - 1
+        );
+    }
+
+    /**
+     * 提供重命名文件或函数的快速修复
+     * Provide the quick-fixes renaming the file or the function
+     */
+    public provideCodeActions(
+        document: vscode.TextDocument,
+        range: vscode.Range | vscode.Selection,
+        context: vscode.CodeActionContext
+    ): vscode.CodeAction[] {
+        const problems = this.results.get(document.uri.fsPath) || [];
+        const actions: vscode.CodeAction[] = [];
+
+        for (const diagnostic of context.diagnostics) {
+            const problem = problems.find(p => p.problem === diagnostic.code && this.toRange(p).isEqual(diagnostic.range));
+            if (!problem || !problem.suggestion) {
+                continue;
+            }
+
+            const edit = new vscode.WorkspaceEdit();
+            let title: string;
+            if (problem.problem === 'test-in-non-test-file') {
+                title = `Rename file to ${problem.suggestion}`;
+                edit.renameFile(document.uri, vscode.Uri.file(path.join(path.dirname(document.uri.fsPath), problem.suggestion)));
+            } else {
+                // 测试函数不会被其他代码调用，只需修改声明
+                // Test functions are not called by other code, so only the declaration changes
+                title = `Rename to ${problem.suggestion}`;
+                edit.replace(document.uri, this.toRange(problem), problem.suggestion);
+            }
+
+            const action = new vscode.CodeAction(title, vscode.CodeActionKind.QuickFix);
+            action.diagnostics = [diagnostic];
+            action.isPreferred = true;
+            action.edit = edit;
+            actions.push(action);
+        }
+        return actions;
+    }
+}
+
+/**
+ * 注册测试函数位置诊断和快速修复
+ * Register test placement diagnostics and quick-fixes
+ * @param context 扩展上下文 (extension context)
+ * @returns 可处置的对象 (disposable objects)
+ */
+export function DisposeTestPlacementProvider(context: vscode.ExtensionContext): vscode.Disposable[] {
+    return new TestPlacementProvider(context).register();
+}